@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/factory"
+	"github.com/urfave/cli"
+)
+
+var (
+	payloadFile = cli.StringFlag{
+		Name:  "file",
+		Usage: "The `path` of the file holding the payload, marshalled with the configured marshaller.",
+	}
+	formatJSON = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Boolean option for printing the incoming header in human-readable JSON, instead of its hash only.",
+	}
+	payloadTopic = cli.StringFlag{
+		Name:  "topic",
+		Usage: "The outport `topic` of the payload, e.g. SaveBlock, FinalizedBlock or RevertIndexedBlock.",
+		Value: outport.TopicSaveBlock,
+	}
+)
+
+// commands are the notifier commands. Except run, the commands write their logs to stderr, so their output can be
+// piped to other tools
+var commands = []cli.Command{
+	{
+		Name:   "run",
+		Usage:  "Starts the notifier",
+		Action: startNotifier,
+	},
+	{
+		Name:   "validate-config",
+		Usage:  "Validates the config, with the environment variables overrides applied, without starting the notifier",
+		Before: redirectLogsToStderr,
+		Action: validateConfig,
+	},
+	{
+		Name:   "inspect-payload",
+		Usage:  "Decodes a captured outport payload with the configured marshaller and prints it as JSON",
+		Flags:  []cli.Flag{payloadFile, payloadTopic},
+		Before: redirectLogsToStderr,
+		Action: inspectPayload,
+	},
+	{
+		Name:   "compute-hash",
+		Usage:  "Computes the hash of the incoming header which would be notified for a captured outport block",
+		Flags:  []cli.Flag{payloadFile, formatJSON},
+		Before: redirectLogsToStderr,
+		Action: computeHash,
+	},
+	{
+		Name:      "encode-address",
+		Usage:     "Encodes a hex public key with the configured address pubkey converter",
+		ArgsUsage: "<hex public key>",
+		Before:    redirectLogsToStderr,
+		Action:    encodeAddress,
+	},
+	{
+		Name:      "decode-address",
+		Usage:     "Decodes an address with the configured address pubkey converter and prints its hex public key",
+		ArgsUsage: "<address>",
+		Before:    redirectLogsToStderr,
+		Action:    decodeAddress,
+	},
+	{
+		Name:   "version",
+		Usage:  "Prints the version and build info",
+		Action: printVersion,
+	},
+}
+
+func redirectLogsToStderr(ctx *cli.Context) error {
+	err := logger.RemoveLogObserver(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	var formatter logger.Formatter = &logger.ConsoleFormatter{}
+	if ctx.GlobalBool(disableAnsiColor.Name) {
+		formatter = &logger.PlainFormatter{}
+	}
+
+	return logger.AddLogObserver(os.Stderr, formatter)
+}
+
+// validateConfig prints the result of each config check and returns an error if any check failed
+func validateConfig(ctx *cli.Context) error {
+	configPath := ctx.GlobalString(configFile.Name)
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("cannot load config %s, error: %w", configPath, err)
+	}
+
+	numFailedChecks := 0
+	fmt.Printf("config %s\n", configPath)
+	for _, configCheck := range factory.ValidateConfig(cfg) {
+		if configCheck.Err != nil {
+			numFailedChecks++
+			fmt.Printf("[FAIL] %s: %s\n", configCheck.Name, configCheck.Err.Error())
+			continue
+		}
+
+		fmt.Printf("[OK]   %s\n", configCheck.Name)
+	}
+
+	if numFailedChecks != 0 {
+		return fmt.Errorf("invalid config, %d check(s) failed", numFailedChecks)
+	}
+
+	fmt.Println("config is valid")
+	return nil
+}
+
+func inspectPayload(ctx *cli.Context) error {
+	cfg, payload, err := loadConfigAndPayload(ctx)
+	if err != nil {
+		return err
+	}
+
+	decodedPayload, err := factory.DecodePayload(cfg.WebSocketConfig.MarshallerType, ctx.String(payloadTopic.Name), payload)
+	if err != nil {
+		return fmt.Errorf("cannot decode payload, error: %w", err)
+	}
+
+	jsonPayload, err := json.MarshalIndent(decodedPayload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(jsonPayload))
+	return nil
+}
+
+func computeHash(ctx *cli.Context) error {
+	cfg, outportBlockBytes, err := loadConfigAndPayload(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(formatJSON.Name) {
+		jsonHeader, errFormat := factory.FormatIncomingHeader(cfg, outportBlockBytes)
+		if errFormat != nil {
+			return fmt.Errorf("cannot format incoming header, error: %w", errFormat)
+		}
+
+		fmt.Println(string(jsonHeader))
+		return nil
+	}
+
+	incomingHeaderHash, err := factory.ComputeIncomingHeaderHash(cfg, outportBlockBytes)
+	if err != nil {
+		return fmt.Errorf("cannot compute incoming header hash, error: %w", err)
+	}
+
+	fmt.Println(hex.EncodeToString(incomingHeaderHash))
+	return nil
+}
+
+func loadConfigAndPayload(ctx *cli.Context) (config.Config, []byte, error) {
+	filePath := ctx.String(payloadFile.Name)
+	if len(filePath) == 0 {
+		return config.Config{}, nil, fmt.Errorf("the --%s flag is required", payloadFile.Name)
+	}
+
+	cfg, err := loadConfig(ctx.GlobalString(configFile.Name))
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+
+	payload, err := os.ReadFile(filePath)
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+
+	return cfg, payload, nil
+}
+
+func encodeAddress(ctx *cli.Context) error {
+	addressPubkeyConverter, err := createAddressPubkeyConverter(ctx)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid hex public key, error: %w", err)
+	}
+
+	address, err := addressPubkeyConverter.Encode(pubKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(address)
+	return nil
+}
+
+func decodeAddress(ctx *cli.Context) error {
+	addressPubkeyConverter, err := createAddressPubkeyConverter(ctx)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := addressPubkeyConverter.Decode(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hex.EncodeToString(pubKey))
+	return nil
+}
+
+func createAddressPubkeyConverter(ctx *cli.Context) (core.PubkeyConverter, error) {
+	if ctx.NArg() != 1 {
+		return nil, fmt.Errorf("expected a single argument, usage: %s %s", ctx.Command.Name, ctx.Command.ArgsUsage)
+	}
+
+	cfg, err := loadConfig(ctx.GlobalString(configFile.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+}
+
+func printVersion(_ *cli.Context) error {
+	fmt.Printf("version:    %s\n", appVersion)
+	fmt.Printf("go version: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			fmt.Printf("commit:     %s\n", setting.Value)
+		case "vcs.time":
+			fmt.Printf("build time: %s\n", setting.Value)
+		case "vcs.modified":
+			fmt.Printf("modified:   %s\n", setting.Value)
+		}
+	}
+
+	return nil
+}
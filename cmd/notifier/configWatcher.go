@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// watchConfigFile polls the config file and signals when its modification time or size changes, until stopped
+func watchConfigFile(path string, interval time.Duration, configChanged chan<- struct{}, stop <-chan struct{}) {
+	lastInfo, err := os.Stat(path)
+	if err != nil {
+		log.Warn("could not read config file info", "path", path, "error", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, errStat := os.Stat(path)
+		if errStat != nil {
+			log.Warn("could not read config file info", "path", path, "error", errStat)
+			continue
+		}
+		if lastInfo != nil && info.ModTime().Equal(lastInfo.ModTime()) && info.Size() == lastInfo.Size() {
+			continue
+		}
+
+		lastInfo = info
+		select {
+		case configChanged <- struct{}{}:
+		default:
+		}
+	}
+}
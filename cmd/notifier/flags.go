@@ -22,4 +22,25 @@ var (
 		Name:  "disable-ansi-color",
 		Usage: "Boolean option for disabling ANSI colors in the logging system.",
 	}
+	watchConfig = cli.BoolFlag{
+		Name: "watch-config",
+		Usage: "Boolean option for reloading the config when the config file changes. The config is always reloaded on" +
+			" SIGHUP. Only the subscribed events, the shadow subscribed events, the sinks and the log level are applied without a restart.",
+	}
+	configFile = cli.StringFlag{
+		Name: "config",
+		Usage: "The `path` of the toml config file. Every config field can be overridden by an environment variable" +
+			" named after its toml path, e.g. SOVEREIGN_NOTIFIER_WEB_SOCKET_URL.",
+		Value: "config/config.toml",
+	}
+	dryRun = cli.BoolFlag{
+		Name: "dry-run",
+		Usage: "Boolean option for running the notifier without delivering the incoming headers. Each incoming header and" +
+			" its events are logged in human-readable form instead.",
+	}
+	dryRunBaseline = cli.StringFlag{
+		Name: "dry-run-baseline",
+		Usage: "The `path` of the currently running config, loaded in the same way as the config. If provided in dry run" +
+			" mode, the notified events are compared with the ones which would be notified with this config.",
+	}
 )
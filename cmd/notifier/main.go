@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,18 +14,22 @@ import (
 	"github.com/multiversx/mx-chain-logger-go/file"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/factory"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
 	"github.com/urfave/cli"
 )
 
 var log = logger.GetOrCreate("mx-chain-sovereign-notifier")
 
-const (
-	configPath = "config/config.toml"
+// appVersion should be populated at build time using ldflags, e.g. -ldflags "-X main.appVersion=$(git describe --tags)"
+var appVersion = "undefined"
 
+const (
 	logsPath       = "logs"
 	logFilePrefix  = "sovereign-notifier"
 	logLifeSpanSec = 432000 // 5 days
 	logLifeSpanMb  = 1024   // 1 GB
+
+	configWatchInterval = 2 * time.Second
 )
 
 func main() {
@@ -37,7 +42,13 @@ func main() {
 		logLevel,
 		logSaveFile,
 		disableAnsiColor,
+		watchConfig,
+		configFile,
+		dryRun,
+		dryRunBaseline,
 	}
+	app.Commands = commands
+	app.Version = appVersion
 	app.Authors = []cli.Author{
 		{
 			Name:  "The MultiversX Team",
@@ -45,6 +56,7 @@ func main() {
 		},
 	}
 
+	// the notifier is started if no command is provided, as before the commands were introduced
 	app.Action = startNotifier
 
 	err := app.Run(os.Args)
@@ -55,16 +67,27 @@ func main() {
 }
 
 func startNotifier(ctx *cli.Context) error {
-	cfg, err := loadConfig(configPath)
+	configPath := ctx.GlobalString(configFile.Name)
+	cfg, overriddenFields, err := readConfig(configPath)
 	if err != nil {
 		return err
 	}
 
-	err = initializeLogger(ctx)
+	err = initializeLogger(ctx, cfg.LogLevel)
 	if err != nil {
 		return err
 	}
 
+	// stdout only holds the incoming headers if the stdout sink is enabled, so it can be piped to other tools
+	if cfg.Sinks.Stdout.Enabled {
+		err = redirectLogsToStderr(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	logLoadedConfig(configPath, overriddenFields)
+
 	var logFile closing.Closer
 	withLogFile := ctx.GlobalBool(logSaveFile.Name)
 	if withLogFile {
@@ -74,7 +97,7 @@ func startNotifier(ctx *cli.Context) error {
 		}
 	}
 
-	wsClient, err := factory.CreateWsSovereignNotifier(cfg)
+	wsClient, configReloader, err := createWsSovereignNotifier(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("cannot create sovereign notifier, error: %w", err)
 	}
@@ -82,9 +105,20 @@ func startNotifier(ctx *cli.Context) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	configChanged := make(chan struct{}, 1)
+	stopWatcher := make(chan struct{})
+	defer close(stopWatcher)
+	if ctx.GlobalBool(watchConfig.Name) {
+		go watchConfigFile(configPath, configWatchInterval, configChanged, stopWatcher)
+	}
+
 	log.Info("starting ws client...")
 
-	<-interrupt
+	logsOnStderr := cfg.Sinks.Stdout.Enabled
+	waitForInterrupt(ctx, interrupt, reload, configChanged, configPath, configReloader, &logsOnStderr)
 	log.Info("closing app at user's signal")
 
 	err = wsClient.Close()
@@ -97,18 +131,114 @@ func startNotifier(ctx *cli.Context) error {
 	return nil
 }
 
+func createWsSovereignNotifier(ctx *cli.Context, cfg config.Config) (process.WSClient, factory.ConfigReloader, error) {
+	if !ctx.GlobalBool(dryRun.Name) {
+		return factory.CreateReloadableWsSovereignNotifier(cfg, nil)
+	}
+
+	baselineConfigPath := ctx.GlobalString(dryRunBaseline.Name)
+	if len(baselineConfigPath) == 0 {
+		return factory.CreateDryRunWsSovereignNotifier(cfg, nil)
+	}
+
+	baselineCfg, err := loadConfig(baselineConfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load dry run baseline config, error: %w", err)
+	}
+
+	return factory.CreateDryRunWsSovereignNotifier(cfg, &baselineCfg)
+}
+
+// waitForInterrupt reloads the config on SIGHUP or when the watched config file changes, until an interrupt is received.
+// The websocket connections and the cached blocks are kept on reload
+func waitForInterrupt(
+	ctx *cli.Context,
+	interrupt chan os.Signal,
+	reload chan os.Signal,
+	configChanged chan struct{},
+	configPath string,
+	configReloader factory.ConfigReloader,
+	logsOnStderr *bool,
+) {
+	for {
+		select {
+		case <-interrupt:
+			return
+		case <-reload:
+			log.Info("received SIGHUP, reloading config")
+		case <-configChanged:
+			log.Info("config file changed, reloading config")
+		}
+
+		reloadConfig(ctx, configPath, configReloader, logsOnStderr)
+	}
+}
+
+// reloadConfig applies the config file to the running notifier. If the reloaded config enables the stdout sink, the logs
+// are redirected to stderr before the sink starts writing, and they stay there even if the reload fails
+func reloadConfig(ctx *cli.Context, configPath string, configReloader factory.ConfigReloader, logsOnStderr *bool) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		log.Error("could not load config, keeping the current one", "error", err)
+		return
+	}
+
+	if cfg.Sinks.Stdout.Enabled && !*logsOnStderr {
+		err = redirectLogsToStderr(ctx)
+		if err != nil {
+			log.Error("could not redirect the logs to stderr, keeping the current config", "error", err)
+			return
+		}
+		*logsOnStderr = true
+	}
+
+	err = configReloader.Reload(cfg)
+	if err != nil {
+		log.Error("invalid config, keeping the current one", "error", err)
+	}
+}
+
+// loadConfig loads the toml config file and applies the environment variables overrides
 func loadConfig(filepath string) (config.Config, error) {
+	cfg, overriddenFields, err := readConfig(filepath)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	logLoadedConfig(filepath, overriddenFields)
+
+	return cfg, nil
+}
+
+// readConfig loads the toml config file and applies the environment variables overrides, returning the overridden
+// fields without logging them
+func readConfig(filepath string) (config.Config, []string, error) {
 	cfg := config.Config{}
 	err := core.LoadTomlFile(&cfg, filepath)
+	if err != nil {
+		return config.Config{}, nil, err
+	}
 
-	log.Info("loaded config", "path", configPath)
+	overriddenFields, err := config.ApplyEnvOverrides(&cfg, os.LookupEnv)
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+
+	return cfg, overriddenFields, nil
+}
 
-	return cfg, err
+func logLoadedConfig(filepath string, overriddenFields []string) {
+	log.Info("loaded config", "path", filepath, "env overrides", strings.Join(overriddenFields, ", "))
 }
 
-func initializeLogger(ctx *cli.Context) error {
-	logLevelFlagValue := ctx.GlobalString(logLevel.Name)
-	err := logger.SetLogLevel(logLevelFlagValue)
+// initializeLogger applies the log level flag, or the config log level if the flag is not provided
+func initializeLogger(ctx *cli.Context, configLogLevel string) error {
+	logLevelValue := ctx.GlobalString(logLevel.Name)
+	if !ctx.GlobalIsSet(logLevel.Name) && len(configLogLevel) != 0 {
+		logLevelValue = configLogLevel
+	}
+
+	err := logger.SetLogLevel(logLevelValue)
 	if err != nil {
 		return err
 	}
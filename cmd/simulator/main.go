@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/factory"
+	"github.com/urfave/cli"
+)
+
+var log = logger.GetOrCreate("outport-simulator")
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "MultiversX outport observer simulator"
+	app.Usage = "This tool will act as an observer connected via websocket outport driver to a sovereign notifier. It " +
+		"generates synthetic blocks with subscribed deposit events and sends them in the same order as a node would, " +
+		"optionally injecting forks and reordered messages."
+	app.Flags = []cli.Flag{
+		logLevel,
+		configFile,
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The MultiversX Team",
+			Email: "contact@multiversx.com",
+		},
+	}
+
+	app.Action = startSimulator
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func startSimulator(ctx *cli.Context) error {
+	err := logger.SetLogLevel(ctx.GlobalString(logLevel.Name))
+	if err != nil {
+		return err
+	}
+
+	cfgPath := ctx.GlobalString(configFile.Name)
+	cfg := config.SimulatorConfig{}
+	err = core.LoadTomlFile(&cfg, cfgPath)
+	if err != nil {
+		return err
+	}
+	log.Info("loaded config", "path", cfgPath)
+
+	outportSimulator, err := factory.CreateOutportSimulator(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create outport simulator, error: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		log.Info("closing app at user's signal")
+		cancel()
+	}()
+
+	log.Info("starting outport simulator...")
+	err = outportSimulator.Run(runCtx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Error("outport simulator stopped", "error", err)
+	}
+
+	return outportSimulator.Close()
+}
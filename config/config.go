@@ -2,10 +2,70 @@ package config
 
 // Config holds notifier configuration
 type Config struct {
-	SubscribedEvents    []SubscribedEvent `toml:"subscribed_events"`
-	HasherType          string            `toml:"hasher_type"`
-	WebSocketConfig     WebSocketConfig   `toml:"web_socket"`
-	AddressPubKeyConfig PubkeyConfig      `toml:"address_pubkey_converter"`
+	SubscribedEvents       []SubscribedEvent        `toml:"subscribed_events"`
+	ShadowSubscribedEvents []SubscribedEvent        `toml:"shadow_subscribed_events"`
+	LogLevel               string                   `toml:"log_level"`
+	HasherType             string                   `toml:"hasher_type"`
+	ExtractionWorkers      uint32                   `toml:"extraction_workers"`
+	WebSocketConfig        WebSocketConfig          `toml:"web_socket"`
+	AddressPubKeyConfig    PubkeyConfig             `toml:"address_pubkey_converter"`
+	HeaderVerification     HeaderVerificationConfig `toml:"header_verification"`
+	Liveness               LivenessConfig           `toml:"liveness"`
+	OutportBlockCache      OutportBlockCacheConfig  `toml:"outport_block_cache"`
+	Sharding               ShardingConfig           `toml:"sharding"`
+	Sinks                  SinksConfig              `toml:"sinks"`
+}
+
+// SinksConfig holds the config of the built-in subscribers writing the notified incoming headers as NDJSON
+type SinksConfig struct {
+	Stdout StdoutSinkConfig `toml:"stdout"`
+	File   FileSinkConfig   `toml:"file"`
+}
+
+// StdoutSinkConfig holds the stdout sink config
+type StdoutSinkConfig struct {
+	Enabled bool `toml:"enabled"`
+}
+
+// FileSinkConfig holds the rotating file sink config
+type FileSinkConfig struct {
+	Enabled       bool   `toml:"enabled"`
+	Path          string `toml:"path"`
+	MaxFileSizeMb uint32 `toml:"max_file_size_mb"`
+	MaxBackups    uint32 `toml:"max_backups"`
+}
+
+// ShardingConfig holds the main chain sharding config
+type ShardingConfig struct {
+	NumShards            uint32   `toml:"num_shards"`
+	ObserverShardIDs     []uint32 `toml:"observer_shard_ids"`
+	AggregateShards      bool     `toml:"aggregate_shards"`
+	AggregationMaxWaitMs uint64   `toml:"aggregation_max_wait_ms"`
+}
+
+// OutportBlockCacheConfig holds the config of the cache storing blocks until they are finalized
+type OutportBlockCacheConfig struct {
+	Type               string `toml:"type"`
+	Path               string `toml:"path"`
+	CompactionInterval uint32 `toml:"compaction_interval"`
+	PreFilter          bool   `toml:"pre_filter"`
+}
+
+// LivenessConfig holds the main chain liveness tracking config
+type LivenessConfig struct {
+	CheckIntervalMs                  uint64 `toml:"check_interval_ms"`
+	StalledChainTimeoutMs            uint64 `toml:"stalled_chain_timeout_ms"`
+	FinalizationTimeoutMs            uint64 `toml:"finalization_timeout_ms"`
+	MaxConsecutiveRoundsWithoutBlock uint64 `toml:"max_consecutive_rounds_without_block"`
+	HeartbeatIntervalMs              uint64 `toml:"heartbeat_interval_ms"`
+}
+
+// HeaderVerificationConfig holds the incoming headers signature verification config
+type HeaderVerificationConfig struct {
+	Enabled                bool   `toml:"enabled"`
+	ValidatorsFilePath     string `toml:"validators_file_path"`
+	ConsensusGroupSize     uint32 `toml:"consensus_group_size"`
+	MetaConsensusGroupSize uint32 `toml:"meta_consensus_group_size"`
 }
 
 // SubscribedEvent holds subscribed events config
@@ -16,14 +76,19 @@ type SubscribedEvent struct {
 
 // WebSocketConfig holds web sockets config
 type WebSocketConfig struct {
-	Url                string `toml:"url"`
-	MarshallerType     string `toml:"marshaller_type"`
-	Mode               string `toml:"mode"`
-	RetryDuration      uint32 `toml:"retry_duration"`
-	WithAcknowledge    bool   `toml:"with_acknowledge"`
-	BlockingAckOnError bool   `toml:"blocking_ack_on_error"`
-	AcknowledgeTimeout int    `toml:"acknowledge_timeout"`
-	Version            uint32 `toml:"version"`
+	Url                        string   `toml:"url"`
+	MarshallerType             string   `toml:"marshaller_type"`
+	Mode                       string   `toml:"mode"`
+	RetryDuration              uint32   `toml:"retry_duration"`
+	WithAcknowledge            bool     `toml:"with_acknowledge"`
+	BlockingAckOnError         bool     `toml:"blocking_ack_on_error"`
+	AcknowledgeTimeout         int      `toml:"acknowledge_timeout"`
+	Version                    uint32   `toml:"version"`
+	UnknownTopicPolicy         string   `toml:"unknown_topic_policy"`
+	Urls                       []string `toml:"urls"`
+	SourceUnhealthyTimeout     uint32   `toml:"source_unhealthy_timeout"`
+	Quorum                     uint32   `toml:"quorum"`
+	PendingFinalizationTimeout uint32   `toml:"pending_finalization_timeout"`
 }
 
 // PubkeyConfig will map the public key configuration
@@ -31,3 +96,20 @@ type PubkeyConfig struct {
 	Length int
 	Hrp    string
 }
+
+// SimulatorConfig holds the outport observer simulator configuration
+type SimulatorConfig struct {
+	NumBlocks              uint64            `toml:"num_blocks"`
+	RoundDurationMs        uint64            `toml:"round_duration_ms"`
+	FinalityDelay          uint32            `toml:"finality_delay"`
+	ForkProbability        float64           `toml:"fork_probability"`
+	ReorderProbability     float64           `toml:"reorder_probability"`
+	NumDepositsPerBlock    uint32            `toml:"num_deposits_per_block"`
+	NumNoiseEventsPerBlock uint32            `toml:"num_noise_events_per_block"`
+	ShardID                uint32            `toml:"shard_id"`
+	Seed                   int64             `toml:"seed"`
+	SubscribedEvents       []SubscribedEvent `toml:"subscribed_events"`
+	HasherType             string            `toml:"hasher_type"`
+	WebSocketConfig        WebSocketConfig   `toml:"web_socket"`
+	AddressPubKeyConfig    PubkeyConfig      `toml:"address_pubkey_converter"`
+}
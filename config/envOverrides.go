@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the config fields
+const EnvPrefix = "SOVEREIGN_NOTIFIER"
+
+// ApplyEnvOverrides overrides the config fields with the values of the matching environment variables, returned by
+// the provided lookup function. Variables are named after the toml path of the fields, e.g.
+// SOVEREIGN_NOTIFIER_WEB_SOCKET_URL. Lists are provided comma separated, while lists of tables, like the subscribed
+// events, are provided as JSON. It returns the names of the applied variables
+func ApplyEnvOverrides(cfg *Config, lookupEnv func(key string) (string, bool)) ([]string, error) {
+	return applyEnvOverrides(reflect.ValueOf(cfg).Elem(), EnvPrefix, lookupEnv)
+}
+
+func applyEnvOverrides(structValue reflect.Value, prefix string, lookupEnv func(key string) (string, bool)) ([]string, error) {
+	appliedVariables := make([]string, 0)
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		envName := prefix + "_" + getEnvName(field)
+		fieldValue := structValue.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			nestedVariables, err := applyEnvOverrides(fieldValue, envName, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+
+			appliedVariables = append(appliedVariables, nestedVariables...)
+			continue
+		}
+
+		value, found := lookupEnv(envName)
+		if !found {
+			continue
+		}
+
+		err := setFieldValue(fieldValue, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of environment variable %s: %w", envName, err)
+		}
+
+		appliedVariables = append(appliedVariables, envName)
+	}
+
+	return appliedVariables, nil
+}
+
+// getEnvName returns the upper case toml name of the field, or its upper case name if it has no toml tag
+func getEnvName(field reflect.StructField) string {
+	name := field.Tag.Get("toml")
+	if len(name) == 0 {
+		name = field.Name
+	}
+
+	return strings.ToUpper(name)
+}
+
+func setFieldValue(fieldValue reflect.Value, value string) error {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, fieldValue.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(parsed)
+	case reflect.Slice:
+		return setSliceValue(fieldValue, value)
+	default:
+		return fmt.Errorf("unsupported field type %s", fieldValue.Type())
+	}
+
+	return nil
+}
+
+func setSliceValue(fieldValue reflect.Value, value string) error {
+	if fieldValue.Type().Elem().Kind() == reflect.Struct {
+		newSlice := reflect.New(fieldValue.Type())
+		err := json.Unmarshal([]byte(value), newSlice.Interface())
+		if err != nil {
+			return err
+		}
+
+		fieldValue.Set(newSlice.Elem())
+		return nil
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) != 0 {
+			items = append(items, item)
+		}
+	}
+
+	newSlice := reflect.MakeSlice(fieldValue.Type(), len(items), len(items))
+	for idx, item := range items {
+		err := setFieldValue(newSlice.Index(idx), item)
+		if err != nil {
+			return err
+		}
+	}
+
+	fieldValue.Set(newSlice)
+	return nil
+}
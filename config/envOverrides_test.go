@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createLookupEnv(variables map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, found := variables[key]
+		return value, found
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no variables should keep the config unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{HasherType: "blake2b", WebSocketConfig: WebSocketConfig{Url: "ws://localhost:22111"}}
+		appliedVariables, err := ApplyEnvOverrides(&cfg, createLookupEnv(nil))
+		require.Nil(t, err)
+		require.Empty(t, appliedVariables)
+		require.Equal(t, Config{HasherType: "blake2b", WebSocketConfig: WebSocketConfig{Url: "ws://localhost:22111"}}, cfg)
+	})
+
+	t.Run("should override all field types", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			HasherType: "blake2b",
+			SubscribedEvents: []SubscribedEvent{
+				{Identifier: "deposit", Addresses: []string{"erd1a"}},
+			},
+			WebSocketConfig: WebSocketConfig{Url: "ws://localhost:22111", Mode: "client"},
+		}
+		appliedVariables, err := ApplyEnvOverrides(&cfg, createLookupEnv(map[string]string{
+			"SOVEREIGN_NOTIFIER_HASHER_TYPE":                     "sha256",
+			"SOVEREIGN_NOTIFIER_SUBSCRIBED_EVENTS":               `[{"identifier":"execute","addresses":["erd1b","erd1c"]}]`,
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_URLS":                 "ws://observer0:22111, ws://observer1:22111",
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_WITH_ACKNOWLEDGE":     "true",
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_ACKNOWLEDGE_TIMEOUT":  "-1",
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_QUORUM":               "2",
+			"SOVEREIGN_NOTIFIER_ADDRESS_PUBKEY_CONVERTER_HRP":    "test",
+			"SOVEREIGN_NOTIFIER_SHARDING_OBSERVER_SHARD_IDS":     "0,1",
+			"SOVEREIGN_NOTIFIER_LIVENESS_HEARTBEAT_INTERVAL_MS":  "500",
+			"SOVEREIGN_NOTIFIER_OUTPORT_BLOCK_CACHE_PRE_FILTER":  "false",
+			"SOVEREIGN_NOTIFIER_NOT_A_CONFIG_FIELD":              "value",
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_MARSHALLER_TYPE_TYPO": "json",
+		}))
+		require.Nil(t, err)
+		require.Len(t, appliedVariables, 10)
+
+		require.Equal(t, "sha256", cfg.HasherType)
+		require.Equal(t, []SubscribedEvent{{Identifier: "execute", Addresses: []string{"erd1b", "erd1c"}}}, cfg.SubscribedEvents)
+		require.Equal(t, "ws://localhost:22111", cfg.WebSocketConfig.Url)
+		require.Equal(t, "client", cfg.WebSocketConfig.Mode)
+		require.Equal(t, []string{"ws://observer0:22111", "ws://observer1:22111"}, cfg.WebSocketConfig.Urls)
+		require.True(t, cfg.WebSocketConfig.WithAcknowledge)
+		require.Equal(t, -1, cfg.WebSocketConfig.AcknowledgeTimeout)
+		require.Equal(t, uint32(2), cfg.WebSocketConfig.Quorum)
+		require.Equal(t, "test", cfg.AddressPubKeyConfig.Hrp)
+		require.Equal(t, []uint32{0, 1}, cfg.Sharding.ObserverShardIDs)
+		require.Equal(t, uint64(500), cfg.Liveness.HeartbeatIntervalMs)
+		require.False(t, cfg.OutportBlockCache.PreFilter)
+	})
+
+	t.Run("invalid value should return error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidVariables := map[string]string{
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_QUORUM":               "-1",
+			"SOVEREIGN_NOTIFIER_WEB_SOCKET_WITH_ACKNOWLEDGE":     "maybe",
+			"SOVEREIGN_NOTIFIER_SUBSCRIBED_EVENTS":               "deposit",
+			"SOVEREIGN_NOTIFIER_SHARDING_OBSERVER_SHARD_IDS":     "0,a",
+			"SOVEREIGN_NOTIFIER_LIVENESS_CHECK_INTERVAL_MS":      "1.5",
+			"SOVEREIGN_NOTIFIER_ADDRESS_PUBKEY_CONVERTER_LENGTH": "",
+		}
+		for name, value := range invalidVariables {
+			cfg := Config{}
+			appliedVariables, err := ApplyEnvOverrides(&cfg, createLookupEnv(map[string]string{name: value}))
+			require.NotNil(t, err, name)
+			require.Contains(t, err.Error(), name)
+			require.Nil(t, appliedVariables)
+		}
+	})
+}
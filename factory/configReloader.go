@@ -0,0 +1,250 @@
+package factory
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+)
+
+// reloadableConfigFields are the config fields applied on reload, all other changes require a restart
+var reloadableConfigFields = map[string]struct{}{
+	"SubscribedEvents":       {},
+	"ShadowSubscribedEvents": {},
+	"Sinks":                  {},
+	"LogLevel":               {},
+}
+
+// ArgsConfigReloader is a struct placeholder for args needed to create a config reloader. The reloaded config is
+// applied under the write lock of ReloadLock, which the notification path should hold for reading. If no lock is
+// provided, the reload is not synchronized with the notification path
+type ArgsConfigReloader struct {
+	Config                 config.Config
+	AddressPubkeyConverter core.PubkeyConverter
+	SovereignNotifier      process.SovereignNotifier
+	OutportBlockFilter     process.OutportBlockFilter
+	AccountsTracker        process.AccountsTracker
+	HeaderSink             process.IncomingHeaderSink
+	ReloadLock             *sync.RWMutex
+}
+
+type configReloader struct {
+	addressPubkeyConverter core.PubkeyConverter
+	notifierUpdater        subscribedEventsUpdater
+	shadowUpdater          shadowEventsUpdater
+	filterUpdater          subscribedEventsUpdater
+	addressesUpdater       subscribedAddressesUpdater
+	sinksUpdater           headerSinksUpdater
+	mutReload              *sync.RWMutex
+
+	mutConfig     sync.Mutex
+	currentConfig config.Config
+}
+
+// NewConfigReloader creates a component which applies the subscribed events, the shadow subscribed events, the sinks and
+// the log level of a reloaded config to the running notifier, without recreating the websocket connections or the
+// outport blocks caches
+func NewConfigReloader(args ArgsConfigReloader) (*configReloader, error) {
+	if check.IfNil(args.AddressPubkeyConverter) {
+		return nil, errNilAddressPubkeyConverter
+	}
+	if check.IfNil(args.OutportBlockFilter) {
+		return nil, errNilOutportBlockFilter
+	}
+
+	notifierUpdater, ok := args.SovereignNotifier.(subscribedEventsUpdater)
+	if !ok || check.IfNil(args.SovereignNotifier) {
+		return nil, errSubscribedEventsNotUpdatable
+	}
+	shadowUpdater, ok := args.SovereignNotifier.(shadowEventsUpdater)
+	if !ok {
+		return nil, errSubscribedEventsNotUpdatable
+	}
+	addressesUpdater, ok := args.AccountsTracker.(subscribedAddressesUpdater)
+	if !ok || check.IfNil(args.AccountsTracker) {
+		return nil, errSubscribedAddressesNotUpdatable
+	}
+
+	// the disabled filter keeps the blocks unchanged, so it does not depend on the subscribed events
+	filterUpdater, _ := args.OutportBlockFilter.(subscribedEventsUpdater)
+	// no header sink is registered in dry run mode, so there are no sinks to replace
+	sinksUpdater, _ := args.HeaderSink.(headerSinksUpdater)
+	mutReload := args.ReloadLock
+	if mutReload == nil {
+		mutReload = &sync.RWMutex{}
+	}
+
+	return &configReloader{
+		addressPubkeyConverter: args.AddressPubkeyConverter,
+		notifierUpdater:        notifierUpdater,
+		shadowUpdater:          shadowUpdater,
+		filterUpdater:          filterUpdater,
+		addressesUpdater:       addressesUpdater,
+		sinksUpdater:           sinksUpdater,
+		mutReload:              mutReload,
+		currentConfig:          args.Config,
+	}, nil
+}
+
+// Reload will validate the provided config and apply its subscribed events, shadow subscribed events, sinks and log
+// level. Everything is validated and created before any update, then all the updates are applied under the reload lock,
+// so the running notifier is either left unchanged or fully updated. Changes of other fields are reported, since they
+// require a restart
+func (cr *configReloader) Reload(cfg config.Config) (err error) {
+	cr.mutConfig.Lock()
+	defer cr.mutConfig.Unlock()
+
+	if len(cfg.LogLevel) != 0 {
+		_, _, err = logger.ParseLogLevelAndMatchingString(cfg.LogLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	subscribedEvents, err := getSubscribedEvents(cfg.SubscribedEvents, cr.addressPubkeyConverter)
+	if err != nil {
+		return err
+	}
+	shadowSubscribedEvents, err := getSubscribedEvents(cfg.ShadowSubscribedEvents, cr.addressPubkeyConverter)
+	if err != nil {
+		return fmt.Errorf("%w for shadow subscriptions", err)
+	}
+
+	err = notifier.CheckSubscribedEvents(subscribedEvents)
+	if err != nil {
+		return err
+	}
+	err = notifier.CheckShadowSubscribedEvents(shadowSubscribedEvents)
+	if err != nil {
+		return err
+	}
+
+	subscribedAddresses := getSubscribedAddresses(cfg.SubscribedEvents)
+	if len(subscribedAddresses) == 0 {
+		return errNoSubscribedAddresses
+	}
+
+	// the new sinks are closed if the reload fails, so that the current sinks are only replaced by a fully applied
+	// config
+	var headerSinks []process.IncomingHeaderSink
+	sinksChanged := cr.sinksUpdater != nil && !reflect.DeepEqual(cfg.Sinks, cr.currentConfig.Sinks)
+	if sinksChanged {
+		headerSinks, err = cr.sinksUpdater.CreateSinks(cfg.Sinks)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err != nil {
+				closeSinks(headerSinks)
+			}
+		}()
+	}
+
+	err = cr.applyConfig(cfg, subscribedEvents, shadowSubscribedEvents, subscribedAddresses, headerSinks)
+	if err != nil {
+		return err
+	}
+
+	subscriptionsChanged := !reflect.DeepEqual(cfg.SubscribedEvents, cr.currentConfig.SubscribedEvents) ||
+		!reflect.DeepEqual(cfg.ShadowSubscribedEvents, cr.currentConfig.ShadowSubscribedEvents)
+	if cr.filterUpdater != nil && subscriptionsChanged {
+		log.Warn("the blocks cached before the reload were pre-filtered with the previous subscriptions, " +
+			"so their notifications do not contain the events, transactions and accounts of the added subscriptions")
+	}
+
+	for _, field := range getNotReloadableChanges(cr.currentConfig, cfg) {
+		log.Warn("config change can not be applied without a restart, ignoring it", "field", field)
+	}
+
+	cr.currentConfig.SubscribedEvents = cfg.SubscribedEvents
+	cr.currentConfig.ShadowSubscribedEvents = cfg.ShadowSubscribedEvents
+	cr.currentConfig.Sinks = cfg.Sinks
+	cr.currentConfig.LogLevel = cfg.LogLevel
+
+	log.Info("reloaded config",
+		"num subscribed events", len(subscribedEvents),
+		"num shadow subscribed events", len(shadowSubscribedEvents),
+		"log level", logger.GetLogLevelPattern())
+
+	return nil
+}
+
+// applyConfig applies the validated config under the reload lock, so that no block is filtered or notified with
+// partially reloaded subscriptions
+func (cr *configReloader) applyConfig(
+	cfg config.Config,
+	subscribedEvents []notifier.SubscribedEvent,
+	shadowSubscribedEvents []notifier.SubscribedEvent,
+	subscribedAddresses []string,
+	headerSinks []process.IncomingHeaderSink,
+) error {
+	cr.mutReload.Lock()
+	defer cr.mutReload.Unlock()
+
+	err := cr.notifierUpdater.UpdateSubscribedEvents(subscribedEvents)
+	if err != nil {
+		return err
+	}
+	if cr.filterUpdater != nil {
+		err = cr.filterUpdater.UpdateSubscribedEvents(append(subscribedEvents, shadowSubscribedEvents...))
+		if err != nil {
+			return err
+		}
+	}
+	err = cr.shadowUpdater.UpdateShadowSubscribedEvents(shadowSubscribedEvents)
+	if err != nil {
+		return err
+	}
+	err = cr.addressesUpdater.UpdateSubscribedAddresses(subscribedAddresses)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.LogLevel) != 0 {
+		err = logger.SetLogLevel(cfg.LogLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	if headerSinks != nil {
+		cr.sinksUpdater.ReplaceSinks(headerSinks)
+	}
+
+	return nil
+}
+
+// getNotReloadableChanges returns the toml names of the changed fields which are not applied on reload
+func getNotReloadableChanges(currentConfig config.Config, newConfig config.Config) []string {
+	currentValue := reflect.ValueOf(currentConfig)
+	newValue := reflect.ValueOf(newConfig)
+	configType := currentValue.Type()
+
+	changedFields := make([]string, 0)
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		_, isReloadable := reloadableConfigFields[field.Name]
+		if isReloadable {
+			continue
+		}
+
+		if !reflect.DeepEqual(currentValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changedFields = append(changedFields, field.Tag.Get("toml"))
+		}
+	}
+
+	return changedFields
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (cr *configReloader) IsInterfaceNil() bool {
+	return cr == nil
+}
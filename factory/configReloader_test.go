@@ -0,0 +1,349 @@
+package factory
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	aliceAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	bobAddress   = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+)
+
+type headerSinksUpdaterStub struct {
+	testscommon.HeaderSubscriberStub
+	createSinksCalled func(cfg config.SinksConfig) ([]process.IncomingHeaderSink, error)
+	replacedSinks     []process.IncomingHeaderSink
+}
+
+func (stub *headerSinksUpdaterStub) CreateSinks(cfg config.SinksConfig) ([]process.IncomingHeaderSink, error) {
+	return stub.createSinksCalled(cfg)
+}
+
+func (stub *headerSinksUpdaterStub) ReplaceSinks(headerSinks []process.IncomingHeaderSink) {
+	stub.replacedSinks = headerSinks
+}
+
+func (stub *headerSinksUpdaterStub) Close() error {
+	return nil
+}
+
+type headerSinkStub struct {
+	testscommon.HeaderSubscriberStub
+	closed bool
+}
+
+func (stub *headerSinkStub) Close() error {
+	stub.closed = true
+	return nil
+}
+
+func createConfigReloaderArgs(t *testing.T) ArgsConfigReloader {
+	cfg := config.Config{
+		SubscribedEvents: []config.SubscribedEvent{
+			{Identifier: "deposit", Addresses: []string{aliceAddress}},
+		},
+		HasherType: "blake2b",
+		WebSocketConfig: config.WebSocketConfig{
+			MarshallerType: "gogo protobuf",
+		},
+		Sharding: config.ShardingConfig{NumShards: 1},
+	}
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	accountsTracker, _ := accounts.NewAccountsTracker(getSubscribedAddresses(cfg.SubscribedEvents))
+	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         cfg.WebSocketConfig.MarshallerType,
+		HasherType:             cfg.HasherType,
+		SubscribedEvents:       cfg.SubscribedEvents,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validators.NewValidatorsTracker(),
+		NumShards:              cfg.Sharding.NumShards,
+	})
+	require.Nil(t, err)
+
+	outportBlockFilter, err := CreateOutportBlockFilter(cfg.SubscribedEvents, addressPubkeyConverter, true)
+	require.Nil(t, err)
+
+	return ArgsConfigReloader{
+		Config:                 cfg,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		SovereignNotifier:      sovereignNotifier,
+		OutportBlockFilter:     outportBlockFilter,
+		AccountsTracker:        accountsTracker,
+	}
+}
+
+func TestNewConfigReloader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		reloader, err := NewConfigReloader(createConfigReloaderArgs(t))
+		require.Nil(t, err)
+		require.False(t, check.IfNil(reloader))
+	})
+
+	t.Run("nil address pubkey converter, should return error", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.AddressPubkeyConverter = nil
+		reloader, err := NewConfigReloader(args)
+		require.Equal(t, errNilAddressPubkeyConverter, err)
+		require.Nil(t, reloader)
+	})
+
+	t.Run("nil outport block filter, should return error", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.OutportBlockFilter = nil
+		reloader, err := NewConfigReloader(args)
+		require.Equal(t, errNilOutportBlockFilter, err)
+		require.Nil(t, reloader)
+	})
+
+	t.Run("not updatable sovereign notifier, should return error", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.SovereignNotifier = &testscommon.SovereignNotifierStub{}
+		reloader, err := NewConfigReloader(args)
+		require.Equal(t, errSubscribedEventsNotUpdatable, err)
+		require.Nil(t, reloader)
+	})
+
+	t.Run("not updatable accounts tracker, should return error", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.AccountsTracker = &testscommon.AccountsTrackerStub{}
+		reloader, err := NewConfigReloader(args)
+		require.Equal(t, errSubscribedAddressesNotUpdatable, err)
+		require.Nil(t, reloader)
+	})
+
+	t.Run("disabled outport block filter should work", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.OutportBlockFilter, _ = CreateOutportBlockFilter(nil, args.AddressPubkeyConverter, false)
+		reloader, err := NewConfigReloader(args)
+		require.Nil(t, err)
+		require.Nil(t, reloader.filterUpdater)
+	})
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	t.Run("invalid config should not change the subscriptions", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{"invalid"}}}
+		err := reloader.Reload(cfg)
+		require.NotNil(t, err)
+
+		cfg.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+		cfg.LogLevel = "*:INVALID"
+		err = reloader.Reload(cfg)
+		require.NotNil(t, err)
+
+		args.AccountsTracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+			aliceAddress: {Address: aliceAddress},
+			bobAddress:   {Address: bobAddress},
+		})
+		require.Len(t, args.AccountsTracker.GetAccounts(), 1)
+		require.NotNil(t, args.AccountsTracker.GetAccounts()[aliceAddress])
+	})
+
+	t.Run("invalid shadow subscriptions should not change the active subscriptions", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+		cfg.ShadowSubscribedEvents = []config.SubscribedEvent{{Identifier: "", Addresses: []string{bobAddress}}}
+		err := reloader.Reload(cfg)
+		require.Contains(t, err.Error(), "for shadow subscriptions")
+		require.Equal(t, args.Config.SubscribedEvents, reloader.currentConfig.SubscribedEvents)
+
+		var notifiedHeader sovereign.IncomingHeaderHandler
+		_ = args.SovereignNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedHeader = header
+				return nil
+			},
+		})
+		decodedPayload, _ := DecodePayload("gogo protobuf", outport.TopicSaveBlock, createMarshalledOutportBlock(t, "gogo protobuf", bobAddress))
+		err = args.SovereignNotifier.Notify(decodedPayload.(*outport.OutportBlock))
+		require.Nil(t, err)
+		require.Empty(t, notifiedHeader.GetIncomingEventHandlers())
+
+		args.AccountsTracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+			aliceAddress: {Address: aliceAddress},
+			bobAddress:   {Address: bobAddress},
+		})
+		require.Len(t, args.AccountsTracker.GetAccounts(), 1)
+		require.NotNil(t, args.AccountsTracker.GetAccounts()[aliceAddress])
+	})
+
+	t.Run("should apply the subscriptions and the log level", func(t *testing.T) {
+		defer func() {
+			_ = logger.SetLogLevel("*:" + logger.LogInfo.String())
+		}()
+
+		args := createConfigReloaderArgs(t)
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+		cfg.LogLevel = "*:" + logger.LogWarning.String()
+		cfg.HasherType = "sha256"
+		err := reloader.Reload(cfg)
+		require.Nil(t, err)
+		require.Equal(t, cfg.LogLevel, logger.GetLogLevelPattern())
+
+		args.AccountsTracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+			aliceAddress: {Address: aliceAddress},
+			bobAddress:   {Address: bobAddress},
+		})
+		require.Len(t, args.AccountsTracker.GetAccounts(), 1)
+		require.NotNil(t, args.AccountsTracker.GetAccounts()[bobAddress])
+
+		// the hasher change was not applied, so it is still reported on the next reload
+		require.Equal(t, []string{"hasher_type"}, getNotReloadableChanges(reloader.currentConfig, cfg))
+		require.Equal(t, cfg.SubscribedEvents, reloader.currentConfig.SubscribedEvents)
+	})
+
+	t.Run("should apply the shadow subscriptions", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.ShadowSubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{"invalid"}}}
+		err := reloader.Reload(cfg)
+		require.Contains(t, err.Error(), "for shadow subscriptions")
+
+		cfg.ShadowSubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+		err = reloader.Reload(cfg)
+		require.Nil(t, err)
+		require.Equal(t, cfg.ShadowSubscribedEvents, reloader.currentConfig.ShadowSubscribedEvents)
+
+		decodedPayload, _ := DecodePayload("gogo protobuf", outport.TopicSaveBlock, createMarshalledOutportBlock(t, "gogo protobuf", bobAddress))
+		outportBlock := args.OutportBlockFilter.FilterOutportBlock(decodedPayload.(*outport.OutportBlock))
+		require.Len(t, outportBlock.TransactionPool.Logs, 1)
+
+		// the events of the shadow subscriptions are only compared, never delivered
+		var notifiedHeader sovereign.IncomingHeaderHandler
+		_ = args.SovereignNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedHeader = header
+				return nil
+			},
+		})
+		err = args.SovereignNotifier.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Empty(t, notifiedHeader.GetIncomingEventHandlers())
+	})
+
+	t.Run("should replace the sinks", func(t *testing.T) {
+		errCreateSinks := errors.New("create sinks error")
+		newSink := &headerSinkStub{}
+		headerSink := &headerSinksUpdaterStub{
+			createSinksCalled: func(cfg config.SinksConfig) ([]process.IncomingHeaderSink, error) {
+				if len(cfg.File.Path) == 0 {
+					return nil, errCreateSinks
+				}
+				return []process.IncomingHeaderSink{newSink}, nil
+			},
+		}
+		args := createConfigReloaderArgs(t)
+		args.HeaderSink = headerSink
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.Sinks.File = config.FileSinkConfig{Enabled: true}
+		err := reloader.Reload(cfg)
+		require.Equal(t, errCreateSinks, err)
+		require.Equal(t, args.Config.Sinks, reloader.currentConfig.Sinks)
+		require.Nil(t, headerSink.replacedSinks)
+
+		cfg.Sinks.File.Path = "headers.ndjson"
+		cfg.ShadowSubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{"invalid"}}}
+		err = reloader.Reload(cfg)
+		require.NotNil(t, err)
+		require.Nil(t, headerSink.replacedSinks)
+
+		cfg.ShadowSubscribedEvents = nil
+		err = reloader.Reload(cfg)
+		require.Nil(t, err)
+		require.Equal(t, cfg.Sinks, reloader.currentConfig.Sinks)
+		require.Equal(t, []process.IncomingHeaderSink{newSink}, headerSink.replacedSinks)
+		require.False(t, newSink.closed)
+	})
+	t.Run("should apply the config under the reload lock", func(t *testing.T) {
+		args := createConfigReloaderArgs(t)
+		args.ReloadLock = &sync.RWMutex{}
+		reloader, _ := NewConfigReloader(args)
+
+		cfg := args.Config
+		cfg.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+
+		args.ReloadLock.RLock()
+		reloaded := make(chan error, 1)
+		go func() {
+			reloaded <- reloader.Reload(cfg)
+		}()
+
+		select {
+		case <-reloaded:
+			require.Fail(t, "config reloaded while a block was being notified")
+		case <-time.After(100 * time.Millisecond):
+		}
+		require.Equal(t, getSubscribedAddresses(args.Config.SubscribedEvents), getTrackedAddresses(args.AccountsTracker))
+
+		args.ReloadLock.RUnlock()
+		require.Nil(t, <-reloaded)
+		require.Equal(t, getSubscribedAddresses(cfg.SubscribedEvents), getTrackedAddresses(args.AccountsTracker))
+	})
+}
+
+func getTrackedAddresses(accountsTracker process.AccountsTracker) []string {
+	accountsTracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		aliceAddress: {Address: aliceAddress},
+		bobAddress:   {Address: bobAddress},
+	})
+
+	addresses := make([]string, 0)
+	for address := range accountsTracker.GetAccounts() {
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+func TestGetNotReloadableChanges(t *testing.T) {
+	t.Parallel()
+
+	currentConfig := config.Config{
+		SubscribedEvents: []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{aliceAddress}}},
+		WebSocketConfig:  config.WebSocketConfig{Urls: []string{"ws://127.0.0.1:1"}},
+	}
+
+	newConfig := currentConfig
+	newConfig.SubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+	newConfig.LogLevel = "*:DEBUG"
+	newConfig.Sinks.Stdout.Enabled = true
+	require.Empty(t, getNotReloadableChanges(currentConfig, newConfig))
+
+	newConfig.WebSocketConfig.Urls = []string{"ws://127.0.0.1:2"}
+	newConfig.Sharding.NumShards = 3
+	require.Equal(t, []string{"web_socket", "sharding"}, getNotReloadableChanges(currentConfig, newConfig))
+}
@@ -0,0 +1,181 @@
+package factory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/multiversx/mx-chain-communication-go/websocket/data"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	hashingFactory "github.com/multiversx/mx-chain-core-go/hashing/factory"
+	"github.com/multiversx/mx-chain-core-go/marshal/factory"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/indexer"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/sharding"
+)
+
+// ConfigCheck holds the result of a config validation check
+type ConfigCheck struct {
+	Name string
+	Err  error
+}
+
+// ValidateConfig checks the provided config without creating any connection or cache, and returns the result of each
+// check. The config is valid if no check holds an error
+func ValidateConfig(cfg config.Config) []ConfigCheck {
+	return []ConfigCheck{
+		{Name: "subscribed events", Err: checkSubscribedEvents(cfg)},
+		{Name: "shadow subscribed events", Err: checkShadowSubscribedEvents(cfg)},
+		{Name: "log level", Err: checkLogLevel(cfg.LogLevel)},
+		{Name: "hasher type", Err: checkHasherType(cfg.HasherType)},
+		{Name: "marshaller type", Err: checkMarshallerType(cfg.WebSocketConfig.MarshallerType)},
+		{Name: "web socket", Err: checkWebSocketConfig(cfg.WebSocketConfig)},
+		{Name: "outport block cache", Err: checkOutportBlockCacheConfig(cfg.OutportBlockCache)},
+		{Name: "sharding", Err: checkShardingConfig(cfg.Sharding)},
+		{Name: "liveness", Err: checkLivenessConfig(cfg.Liveness)},
+		{Name: "sinks", Err: checkSinksConfig(cfg.Sinks)},
+		{Name: "header verification", Err: checkHeaderVerificationConfig(cfg.HeaderVerification)},
+	}
+}
+
+func checkSubscribedEvents(cfg config.Config) error {
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+	if err != nil {
+		return fmt.Errorf("invalid address pubkey converter: %w", err)
+	}
+
+	subscribedEvents, err := getSubscribedEvents(cfg.SubscribedEvents, addressPubkeyConverter)
+	if err != nil {
+		return err
+	}
+
+	return notifier.CheckSubscribedEvents(subscribedEvents)
+}
+
+func checkShadowSubscribedEvents(cfg config.Config) error {
+	if len(cfg.ShadowSubscribedEvents) == 0 {
+		return nil
+	}
+
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+	if err != nil {
+		return fmt.Errorf("invalid address pubkey converter: %w", err)
+	}
+
+	shadowSubscribedEvents, err := getSubscribedEvents(cfg.ShadowSubscribedEvents, addressPubkeyConverter)
+	if err != nil {
+		return err
+	}
+
+	return notifier.CheckShadowSubscribedEvents(shadowSubscribedEvents)
+}
+
+func checkLogLevel(logLevel string) error {
+	if len(logLevel) == 0 {
+		return nil
+	}
+
+	_, _, err := logger.ParseLogLevelAndMatchingString(logLevel)
+	return err
+}
+
+func checkHasherType(hasherType string) error {
+	_, err := hashingFactory.NewHasher(hasherType)
+	return err
+}
+
+func checkMarshallerType(marshallerType string) error {
+	_, err := factory.NewMarshalizer(marshallerType)
+	return err
+}
+
+func checkWebSocketConfig(cfg config.WebSocketConfig) error {
+	if cfg.Version != indexer.PayloadVersionV1 {
+		return fmt.Errorf("%w: %d", errUnsupportedPayloadVersion, cfg.Version)
+	}
+
+	switch cfg.UnknownTopicPolicy {
+	case "", indexer.UnknownTopicPolicyIgnore, indexer.UnknownTopicPolicyLog, indexer.UnknownTopicPolicyFail:
+	default:
+		return fmt.Errorf("%w: %s", errInvalidUnknownTopicPolicy, cfg.UnknownTopicPolicy)
+	}
+
+	urls := getObserversUrls(cfg)
+	switch cfg.Mode {
+	case data.ModeClient:
+		for _, url := range urls {
+			if !strings.HasPrefix(url, "ws://") && !strings.HasPrefix(url, "wss://") {
+				return fmt.Errorf("%w: %q should contain the ws:// or wss:// scheme in client mode", errInvalidWebSocketUrl, url)
+			}
+		}
+	case data.ModeServer:
+		if len(cfg.Urls) != 0 {
+			return fmt.Errorf("%w: urls are supported only in client mode", errInvalidWebSocketUrl)
+		}
+		if len(cfg.Url) == 0 {
+			return fmt.Errorf("%w: empty url", errInvalidWebSocketUrl)
+		}
+	default:
+		return fmt.Errorf("%w: %q, expected %s or %s", errInvalidWebSocketMode, cfg.Mode, data.ModeClient, data.ModeServer)
+	}
+
+	if int(cfg.Quorum) > len(urls) {
+		return fmt.Errorf("%w, quorum: %d, num observers: %d", errQuorumGreaterThanObservers, cfg.Quorum, len(urls))
+	}
+
+	return nil
+}
+
+func checkOutportBlockCacheConfig(cfg config.OutportBlockCacheConfig) error {
+	switch cfg.Type {
+	case "", memoryOutportBlockCacheType:
+		return nil
+	case levelDBOutportBlockCacheType:
+		if len(cfg.Path) == 0 {
+			return errEmptyOutportBlockCachePath
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errInvalidOutportBlockCacheType, cfg.Type)
+	}
+}
+
+func checkShardingConfig(cfg config.ShardingConfig) error {
+	_, err := sharding.NewMultiShardCoordinator(cfg.NumShards)
+	if err != nil {
+		return err
+	}
+	if cfg.AggregateShards && len(cfg.ObserverShardIDs) == 0 {
+		return errNoObserverShardsToAggregate
+	}
+
+	return nil
+}
+
+func checkLivenessConfig(cfg config.LivenessConfig) error {
+	if cfg.CheckIntervalMs == 0 {
+		return errInvalidLivenessCheckInterval
+	}
+
+	return nil
+}
+
+// checkHeaderVerificationConfig rejects the header verification, since the multi signature verifier it needs can only
+// be provided when the notifier is created with CreateWsSovereignNotifierWithMultiSigVerifier
+func checkHeaderVerificationConfig(cfg config.HeaderVerificationConfig) error {
+	if cfg.Enabled {
+		return errHeaderVerificationNotSupported
+	}
+
+	return nil
+}
+
+func checkSinksConfig(cfg config.SinksConfig) error {
+	if cfg.File.Enabled && len(cfg.File.Path) == 0 {
+		return errEmptySinkFilePath
+	}
+
+	return nil
+}
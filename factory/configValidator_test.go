@@ -0,0 +1,185 @@
+package factory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidConfig() config.Config {
+	return config.Config{
+		SubscribedEvents: []config.SubscribedEvent{
+			{Identifier: "deposit", Addresses: []string{aliceAddress, bobAddress}},
+		},
+		LogLevel:   "*:INFO",
+		HasherType: "blake2b",
+		WebSocketConfig: config.WebSocketConfig{
+			Url:                "ws://localhost:22111",
+			MarshallerType:     "gogo protobuf",
+			Mode:               "client",
+			Version:            1,
+			UnknownTopicPolicy: "log",
+		},
+		AddressPubKeyConfig: config.PubkeyConfig{Length: 32, Hrp: "erd"},
+		OutportBlockCache:   config.OutportBlockCacheConfig{Type: "leveldb", Path: "db"},
+		Sharding:            config.ShardingConfig{NumShards: 3},
+		Liveness:            config.LivenessConfig{CheckIntervalMs: 1000},
+	}
+}
+
+func getFailedChecks(checks []ConfigCheck) map[string]error {
+	failedChecks := make(map[string]error)
+	for _, configCheck := range checks {
+		if configCheck.Err != nil {
+			failedChecks[configCheck.Name] = configCheck.Err
+		}
+	}
+
+	return failedChecks
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid config should pass all checks", func(t *testing.T) {
+		t.Parallel()
+
+		checks := ValidateConfig(createValidConfig())
+		require.Len(t, checks, 11)
+		require.Empty(t, getFailedChecks(checks))
+	})
+
+	t.Run("quorum mode with observers urls should pass all checks", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.WebSocketConfig.Url = ""
+		cfg.WebSocketConfig.Urls = []string{"ws://observer0:22111", "wss://observer1:22111"}
+		cfg.WebSocketConfig.Quorum = 2
+		cfg.Sharding.AggregateShards = true
+		cfg.Sharding.ObserverShardIDs = []uint32{0, 1}
+		require.Empty(t, getFailedChecks(ValidateConfig(cfg)))
+	})
+
+	t.Run("invalid fields should fail their checks", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.SubscribedEvents[0].Addresses = []string{aliceAddress, "erd1invalid"}
+		cfg.LogLevel = "*:LOUD"
+		cfg.HasherType = "md5"
+		cfg.WebSocketConfig.MarshallerType = "xml"
+		cfg.WebSocketConfig.Mode = "peer"
+		cfg.OutportBlockCache.Type = "redis"
+		cfg.Sharding.NumShards = 0
+
+		failedChecks := getFailedChecks(ValidateConfig(cfg))
+		require.Len(t, failedChecks, 7)
+		require.ErrorIs(t, failedChecks["web socket"], errInvalidWebSocketMode)
+		require.ErrorIs(t, failedChecks["outport block cache"], errInvalidOutportBlockCacheType)
+		require.Contains(t, failedChecks["subscribed events"].Error(), "for event at index = 0")
+	})
+
+	t.Run("invalid subscribed events", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.SubscribedEvents = nil
+		require.Contains(t, getFailedChecks(ValidateConfig(cfg))["subscribed events"].Error(), "no subscribed event")
+
+		cfg = createValidConfig()
+		cfg.SubscribedEvents[0].Identifier = ""
+		require.Contains(t, getFailedChecks(ValidateConfig(cfg))["subscribed events"].Error(), "no subscribed identifier")
+
+		cfg = createValidConfig()
+		cfg.SubscribedEvents[0].Addresses = []string{aliceAddress, aliceAddress}
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["subscribed events"], errDuplicateSubscribedAddresses)
+
+		cfg = createValidConfig()
+		cfg.AddressPubKeyConfig.Length = 0
+		require.Contains(t, getFailedChecks(ValidateConfig(cfg))["subscribed events"].Error(), "invalid address pubkey converter")
+
+		cfg = createValidConfig()
+		cfg.ShadowSubscribedEvents = []config.SubscribedEvent{{Identifier: "deposit", Addresses: []string{bobAddress}}}
+		require.Empty(t, getFailedChecks(ValidateConfig(cfg)))
+
+		cfg.ShadowSubscribedEvents[0].Addresses = nil
+		require.Equal(t, map[string]error{
+			"shadow subscribed events": fmt.Errorf("%w for event at index = 0", errNoSubscribedAddresses),
+		}, getFailedChecks(ValidateConfig(cfg)))
+
+		cfg.ShadowSubscribedEvents[0].Addresses = []string{bobAddress}
+		cfg.ShadowSubscribedEvents[0].Identifier = ""
+		require.Contains(t, getFailedChecks(ValidateConfig(cfg))["shadow subscribed events"].Error(), "for shadow subscriptions")
+	})
+
+	t.Run("invalid web socket settings", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.WebSocketConfig.Url = "localhost:22111"
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["web socket"], errInvalidWebSocketUrl)
+
+		cfg = createValidConfig()
+		cfg.WebSocketConfig.Mode = "server"
+		cfg.WebSocketConfig.Url = ""
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["web socket"], errInvalidWebSocketUrl)
+
+		cfg = createValidConfig()
+		cfg.WebSocketConfig.Version = 2
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["web socket"], errUnsupportedPayloadVersion)
+
+		cfg = createValidConfig()
+		cfg.WebSocketConfig.UnknownTopicPolicy = "drop"
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["web socket"], errInvalidUnknownTopicPolicy)
+
+		cfg = createValidConfig()
+		cfg.WebSocketConfig.Quorum = 2
+		require.ErrorIs(t, getFailedChecks(ValidateConfig(cfg))["web socket"], errQuorumGreaterThanObservers)
+	})
+
+	t.Run("zero liveness check interval should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.Liveness.CheckIntervalMs = 0
+		require.Equal(t, map[string]error{
+			"liveness": errInvalidLivenessCheckInterval,
+		}, getFailedChecks(ValidateConfig(cfg)))
+	})
+
+	t.Run("invalid outport block cache and sharding settings", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.OutportBlockCache.Path = ""
+		require.Equal(t, errEmptyOutportBlockCachePath, getFailedChecks(ValidateConfig(cfg))["outport block cache"])
+
+		cfg = createValidConfig()
+		cfg.Sharding.AggregateShards = true
+		require.Equal(t, errNoObserverShardsToAggregate, getFailedChecks(ValidateConfig(cfg))["sharding"])
+	})
+
+	t.Run("enabled header verification should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.HeaderVerification.Enabled = true
+		require.Equal(t, map[string]error{
+			"header verification": errHeaderVerificationNotSupported,
+		}, getFailedChecks(ValidateConfig(cfg)))
+	})
+
+	t.Run("file sink without path should fail", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := createValidConfig()
+		cfg.Sinks.File.Enabled = true
+		require.Equal(t, errEmptySinkFilePath, getFailedChecks(ValidateConfig(cfg))["sinks"])
+
+		cfg.Sinks.File.Path = "output/incoming-headers.ndjson"
+		require.Empty(t, getFailedChecks(ValidateConfig(cfg)))
+	})
+}
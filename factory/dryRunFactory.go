@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/dryrun"
+)
+
+type dryRunOptions struct {
+	baselineConfig *config.Config
+}
+
+// CreateDryRunWsSovereignNotifier will create a ws sovereign shard notifier which runs the whole pipeline, but logs the
+// incoming headers instead of delivering them. If a baseline config, usually the currently running one, is provided,
+// the notified events are compared with the ones which the baseline config would notify
+func CreateDryRunWsSovereignNotifier(cfg config.Config, baselineCfg *config.Config) (process.WSClient, ConfigReloader, error) {
+	return createReloadableWsSovereignNotifier(cfg, nil, &dryRunOptions{baselineConfig: baselineCfg})
+}
+
+func createDryRunNotifier(
+	sovereignNotifier process.SovereignNotifier,
+	addressPubkeyConverter core.PubkeyConverter,
+	hasherType string,
+	baselineCfg *config.Config,
+) (process.SovereignNotifier, error) {
+	headerFormatter, err := createIncomingHeaderFormatter(hasherType, addressPubkeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	args := dryrun.ArgsDryRunNotifier{
+		Notifier:                sovereignNotifier,
+		IncomingHeaderFormatter: headerFormatter,
+	}
+
+	if baselineCfg != nil {
+		baselineNotifier, err := createOfflineSovereignNotifier(*baselineCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		args.BaselineNotifier = baselineNotifier
+	}
+
+	log.Info("running in dry run mode, incoming headers will not be delivered", "with baseline", baselineCfg != nil)
+
+	return dryrun.NewDryRunNotifier(args)
+}
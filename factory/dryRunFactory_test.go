@@ -0,0 +1,53 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDryRunNotifier(t *testing.T) {
+	t.Parallel()
+
+	cfg := createValidConfig()
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+
+	t.Run("invalid baseline config should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sovereignNotifier, err := createOfflineSovereignNotifier(cfg)
+		require.Nil(t, err)
+
+		baselineCfg := createValidConfig()
+		baselineCfg.HasherType = "md5"
+		dryRunNotifier, err := createDryRunNotifier(sovereignNotifier, addressPubkeyConverter, cfg.HasherType, &baselineCfg)
+		require.NotNil(t, err)
+		require.Nil(t, dryRunNotifier)
+	})
+
+	t.Run("should run the notifiers without delivering the incoming headers", func(t *testing.T) {
+		t.Parallel()
+
+		sovereignNotifier, err := createOfflineSovereignNotifier(cfg)
+		require.Nil(t, err)
+
+		baselineCfg := createValidConfig()
+		baselineCfg.SubscribedEvents[0].Addresses = []string{bobAddress}
+		dryRunNotifier, err := createDryRunNotifier(sovereignNotifier, addressPubkeyConverter, cfg.HasherType, &baselineCfg)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(dryRunNotifier))
+
+		err = dryRunNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{})
+		require.NotNil(t, err)
+
+		decodedPayload, err := DecodePayload("gogo protobuf", outport.TopicSaveBlock, createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress))
+		require.Nil(t, err)
+
+		err = dryRunNotifier.Notify(decodedPayload.(*outport.OutportBlock))
+		require.Nil(t, err)
+	})
+}
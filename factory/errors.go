@@ -5,3 +5,41 @@ import "errors"
 var errNoSubscribedAddresses = errors.New("no subscribed addresses provided")
 
 var errDuplicateSubscribedAddresses = errors.New("duplicate subscribed addresses provided")
+
+var errQuorumGreaterThanObservers = errors.New("quorum is greater than the number of observers")
+
+var errInvalidOutportBlockCacheType = errors.New("invalid outport block cache type")
+
+var errNilAddressPubkeyConverter = errors.New("nil address pubkey converter provided")
+
+var errNilOutportBlockFilter = errors.New("nil outport block filter provided")
+
+var errSubscribedEventsNotUpdatable = errors.New("sovereign notifier does not support updating the subscribed events")
+
+var errSubscribedAddressesNotUpdatable = errors.New("accounts tracker does not support updating the subscribed addresses")
+
+var errInvalidWebSocketMode = errors.New("invalid web socket mode")
+
+var errInvalidWebSocketUrl = errors.New("invalid web socket url")
+
+var errUnsupportedPayloadVersion = errors.New("unsupported payload version")
+
+var errInvalidUnknownTopicPolicy = errors.New("invalid unknown topic policy")
+
+var errEmptyOutportBlockCachePath = errors.New("empty outport block cache path")
+
+var errNoObserverShardsToAggregate = errors.New("no observer shards to aggregate")
+
+var errUnknownPayloadTopic = errors.New("unknown payload topic")
+
+var errEmptySinkFilePath = errors.New("empty sink file path")
+
+var errInvalidLivenessCheckInterval = errors.New("invalid liveness check interval")
+
+var errHeaderVerificationNotSupported = errors.New("header verification requires a multi signature verifier, which is not available when the notifier is created from config only")
+
+var errNotHeartbeatNotifier = errors.New("sovereign notifier does not notify heartbeats")
+
+var errNotIncomingHeaderHashComputer = errors.New("sovereign notifier does not compute incoming header hashes")
+
+var errNotPolicyHandlerRegisterer = errors.New("sovereign notifier does not register handlers with notification policies")
@@ -0,0 +1,51 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/quorum"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/sinks"
+)
+
+// ConfigReloader should apply a reloaded config to a running notifier
+type ConfigReloader interface {
+	Reload(cfg config.Config) error
+	IsInterfaceNil() bool
+}
+
+type incomingHeaderFormatter interface {
+	sinks.IncomingHeaderFormatter
+	FormatEvent(event data.EventHandler) *formatter.Event
+	FormatIncomingHeaderJSON(incomingHeaderHash []byte, incomingHeader sovereign.IncomingHeaderHandler) ([]byte, error)
+}
+
+type quorumSourceComponentsProvider interface {
+	SourceComponents(source string) (*quorum.SourceComponents, error)
+}
+
+type subscribedEventsUpdater interface {
+	UpdateSubscribedEvents(subscribedEvents []notifier.SubscribedEvent) error
+}
+
+type shadowEventsUpdater interface {
+	UpdateShadowSubscribedEvents(shadowSubscribedEvents []notifier.SubscribedEvent) error
+}
+
+type subscribedAddressesUpdater interface {
+	UpdateSubscribedAddresses(subscribedAddresses []string) error
+}
+
+type sinksReplacer interface {
+	process.IncomingHeaderSink
+	ReplaceSinks(headerSinks []process.IncomingHeaderSink)
+}
+
+type headerSinksUpdater interface {
+	CreateSinks(cfg config.SinksConfig) ([]process.IncomingHeaderSink, error)
+	ReplaceSinks(headerSinks []process.IncomingHeaderSink)
+}
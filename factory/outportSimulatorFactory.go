@@ -0,0 +1,76 @@
+package factory
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	hashingFactory "github.com/multiversx/mx-chain-core-go/hashing/factory"
+	"github.com/multiversx/mx-chain-core-go/marshal/factory"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/simulator"
+)
+
+// CreateOutportSimulator creates an outport simulator which mimics an observer connected through the websocket
+// outport driver. The websocket mode should be the opposite of the one configured in the notifier.
+func CreateOutportSimulator(cfg config.SimulatorConfig) (simulator.OutportSimulator, error) {
+	marshaller, err := factory.NewMarshalizer(cfg.WebSocketConfig.MarshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := hashingFactory.NewHasher(cfg.HasherType)
+	if err != nil {
+		return nil, err
+	}
+
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribedEvents, err := getSubscribedEvents(cfg.SubscribedEvents, addressPubkeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	log.Info("creating outport simulator", "seed", seed)
+	randomness := rand.New(rand.NewSource(seed))
+
+	blockGenerator, err := simulator.NewBlockGenerator(simulator.ArgsBlockGenerator{
+		Marshaller:             marshaller,
+		Hasher:                 hasher,
+		Randomness:             randomness,
+		SubscribedEvents:       subscribedEvents,
+		ShardID:                cfg.ShardID,
+		NumDepositsPerBlock:    cfg.NumDepositsPerBlock,
+		NumNoiseEventsPerBlock: cfg.NumNoiseEventsPerBlock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wsHost, err := createWsHost(marshaller, cfg.WebSocketConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulator.NewOutportSimulator(simulator.ArgsOutportSimulator{
+		Sender:             wsHost,
+		Marshaller:         marshaller,
+		BlockGenerator:     blockGenerator,
+		Randomness:         randomness,
+		ShardID:            cfg.ShardID,
+		NumBlocks:          cfg.NumBlocks,
+		FinalityDelay:      cfg.FinalityDelay,
+		ForkProbability:    cfg.ForkProbability,
+		ReorderProbability: cfg.ReorderProbability,
+		RoundDuration:      time.Duration(cfg.RoundDurationMs) * time.Millisecond,
+		RetryDuration:      time.Duration(cfg.WebSocketConfig.RetryDuration) * time.Second,
+	})
+}
@@ -0,0 +1,125 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/observers"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func getFreePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func createSimulatorConfig(port int) config.SimulatorConfig {
+	return config.SimulatorConfig{
+		NumBlocks:              5,
+		FinalityDelay:          1,
+		ForkProbability:        0.5,
+		NumDepositsPerBlock:    2,
+		NumNoiseEventsPerBlock: 3,
+		Seed:                   1,
+		SubscribedEvents: []config.SubscribedEvent{
+			{
+				Identifier: "deposit",
+				Addresses:  []string{"erd1qqqqqqqqqqqqqpgqeel2kumf0r8ffyhth7pqdujjat9nx0862jpsg2pqaq"},
+			},
+		},
+		HasherType: "blake2b",
+		WebSocketConfig: config.WebSocketConfig{
+			Url:                fmt.Sprintf("127.0.0.1:%d", port),
+			MarshallerType:     "gogo protobuf",
+			Mode:               "server",
+			RetryDuration:      1,
+			WithAcknowledge:    true,
+			AcknowledgeTimeout: 10,
+			Version:            1,
+		},
+		AddressPubKeyConfig: config.PubkeyConfig{
+			Length: 32,
+			Hrp:    "erd",
+		},
+	}
+}
+
+func TestCreateOutportSimulator_EndToEndWithSovereignNotifier(t *testing.T) {
+	simulatorCfg := createSimulatorConfig(getFreePort(t))
+	outportSimulator, err := CreateOutportSimulator(simulatorCfg)
+	require.Nil(t, err)
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	accountsTracker, _ := accounts.NewAccountsTracker(getSubscribedAddresses(simulatorCfg.SubscribedEvents))
+	validatorsTracker := validators.NewValidatorsTracker()
+	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         simulatorCfg.WebSocketConfig.MarshallerType,
+		HasherType:             simulatorCfg.HasherType,
+		SubscribedEvents:       simulatorCfg.SubscribedEvents,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validatorsTracker,
+		NumShards:              1,
+	})
+	require.Nil(t, err)
+
+	mut := sync.Mutex{}
+	notifiedNonces := make([]uint64, 0)
+	err = sovereignNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			mut.Lock()
+			notifiedNonces = append(notifiedNonces, header.GetHeaderHandler().GetNonce())
+			mut.Unlock()
+
+			require.Len(t, header.GetIncomingEventHandlers(), int(simulatorCfg.NumDepositsPerBlock))
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	livenessTracker, err := CreateLivenessTracker(config.LivenessConfig{CheckIntervalMs: 100}, sovereignNotifier.(process.HeartbeatNotifier))
+	require.Nil(t, err)
+
+	outportBlockFilter, err := CreateOutportBlockFilter(simulatorCfg.SubscribedEvents, addressPubkeyConverter, true)
+	require.Nil(t, err)
+
+	notifierWsCfg := simulatorCfg.WebSocketConfig
+	notifierWsCfg.Url = "ws://" + simulatorCfg.WebSocketConfig.Url
+	notifierWsCfg.Mode = "client"
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:      notifierWsCfg,
+		HasherType:           simulatorCfg.HasherType,
+		SovereignNotifier:    sovereignNotifier,
+		AccountsTracker:      accountsTracker,
+		ValidatorsTracker:    validatorsTracker,
+		LivenessTracker:      livenessTracker,
+		SourcesHealthTracker: observers.NewSourcesHealthTracker(0),
+		OutportBlockFilter:   outportBlockFilter,
+	})
+	require.Nil(t, err)
+
+	err = outportSimulator.Run(context.Background())
+	require.Nil(t, err)
+
+	_ = wsClient.Close()
+	_ = outportSimulator.Close()
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, notifiedNonces)
+}
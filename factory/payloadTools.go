@@ -0,0 +1,160 @@
+package factory
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-core-go/marshal/factory"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
+)
+
+// DecodePayload decodes an outport payload of the provided topic, as sent by the observers, with the provided
+// marshaller type
+func DecodePayload(marshallerType string, topic string, payload []byte) (interface{}, error) {
+	marshaller, err := factory.NewMarshalizer(marshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedPayload, err := createPayloadObject(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	err = marshaller.Unmarshal(decodedPayload, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodedPayload, nil
+}
+
+func createPayloadObject(topic string) (interface{}, error) {
+	switch topic {
+	case outport.TopicSaveBlock:
+		return &outport.OutportBlock{}, nil
+	case outport.TopicRevertIndexedBlock:
+		return &outport.BlockData{}, nil
+	case outport.TopicFinalizedBlock:
+		return &outport.FinalizedBlock{}, nil
+	case outport.TopicSaveRoundsInfo:
+		return &outport.RoundsInfo{}, nil
+	case outport.TopicSaveValidatorsRating:
+		return &outport.ValidatorsRating{}, nil
+	case outport.TopicSaveValidatorsPubKeys:
+		return &outport.ValidatorsPubKeys{}, nil
+	case outport.TopicSaveAccounts:
+		return &outport.Accounts{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownPayloadTopic, topic)
+	}
+}
+
+// ComputeIncomingHeaderHash decodes the provided outport block, marshalled with the configured marshaller, and
+// computes the hash of the incoming header which would be notified for it with the configured subscribed events
+func ComputeIncomingHeaderHash(cfg config.Config, outportBlockBytes []byte) ([]byte, error) {
+	decodedPayload, err := DecodePayload(cfg.WebSocketConfig.MarshallerType, outport.TopicSaveBlock, outportBlockBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	sovereignNotifier, err := createOfflineSovereignNotifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hashComputer, ok := sovereignNotifier.(process.IncomingHeaderHashComputer)
+	if !ok {
+		return nil, errNotIncomingHeaderHashComputer
+	}
+
+	return hashComputer.ComputeIncomingHeaderHash(decodedPayload.(*outport.OutportBlock))
+}
+
+// FormatIncomingHeader decodes the provided outport block, marshalled with the configured marshaller, and returns the
+// human-readable JSON of the incoming header which would be notified for it with the configured subscribed events
+func FormatIncomingHeader(cfg config.Config, outportBlockBytes []byte) ([]byte, error) {
+	decodedPayload, err := DecodePayload(cfg.WebSocketConfig.MarshallerType, outport.TopicSaveBlock, outportBlockBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	sovereignNotifier, err := createOfflineSovereignNotifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+	if err != nil {
+		return nil, err
+	}
+
+	headerFormatter, err := createIncomingHeaderFormatter(cfg.HasherType, addressPubkeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &incomingHeaderRecorder{}
+	err = sovereignNotifier.RegisterHandler(recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sovereignNotifier.Notify(decodedPayload.(*outport.OutportBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	return headerFormatter.FormatIncomingHeaderJSON(recorder.headerHash, recorder.header)
+}
+
+// incomingHeaderRecorder keeps the last notified incoming header
+type incomingHeaderRecorder struct {
+	headerHash []byte
+	header     sovereign.IncomingHeaderHandler
+}
+
+// AddHeader keeps the notified incoming header
+func (ihr *incomingHeaderRecorder) AddHeader(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+	ihr.headerHash = headerHash
+	ihr.header = header
+
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (ihr *incomingHeaderRecorder) IsInterfaceNil() bool {
+	return ihr == nil
+}
+
+// createOfflineSovereignNotifier creates a notifier which is not connected to any observer, used only to compute the
+// incoming headers of the provided config. Headers signatures are not verified
+func createOfflineSovereignNotifier(cfg config.Config) (process.SovereignNotifier, error) {
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
+	if err != nil {
+		return nil, err
+	}
+
+	accountsTracker, err := accounts.NewAccountsTracker(getSubscribedAddresses(cfg.SubscribedEvents))
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         cfg.WebSocketConfig.MarshallerType,
+		SubscribedEvents:       cfg.SubscribedEvents,
+		HasherType:             cfg.HasherType,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validators.NewValidatorsTracker(),
+		NumExtractionWorkers:   cfg.ExtractionWorkers,
+		NumShards:              cfg.Sharding.NumShards,
+		ObserverShardIDs:       cfg.Sharding.ObserverShardIDs,
+	})
+}
@@ -0,0 +1,170 @@
+package factory
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/hashing/blake2b"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-core-go/marshal/factory"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+	"github.com/stretchr/testify/require"
+)
+
+func createMarshalledOutportBlock(t *testing.T, marshallerType string, eventAddress string) []byte {
+	marshaller, err := factory.NewMarshalizer(marshallerType)
+	require.Nil(t, err)
+
+	headerBytes, err := marshaller.Marshal(&block.HeaderV2{Header: &block.Header{Nonce: 4}})
+	require.Nil(t, err)
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	decodedAddress, err := addressPubkeyConverter.Decode(eventAddress)
+	require.Nil(t, err)
+
+	outportBlockBytes, err := marshaller.Marshal(&outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderBytes: headerBytes,
+			HeaderType:  string(core.ShardHeaderV2),
+			HeaderHash:  []byte("headerHash"),
+		},
+		TransactionPool: &outport.TransactionPool{
+			Logs: []*outport.LogData{
+				{
+					TxHash: "txHash",
+					Log: &transaction.Log{Events: []*transaction.Event{
+						{Address: decodedAddress, Identifier: []byte("deposit")},
+					}},
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	return outportBlockBytes
+}
+
+func TestDecodePayload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid marshaller type should return error", func(t *testing.T) {
+		t.Parallel()
+
+		decodedPayload, err := DecodePayload("xml", outport.TopicSaveBlock, nil)
+		require.NotNil(t, err)
+		require.Nil(t, decodedPayload)
+	})
+
+	t.Run("unknown topic should return error", func(t *testing.T) {
+		t.Parallel()
+
+		decodedPayload, err := DecodePayload("json", "SaveTransactions", nil)
+		require.ErrorIs(t, err, errUnknownPayloadTopic)
+		require.Nil(t, decodedPayload)
+	})
+
+	t.Run("invalid payload should return error", func(t *testing.T) {
+		t.Parallel()
+
+		decodedPayload, err := DecodePayload("json", outport.TopicFinalizedBlock, []byte("not json"))
+		require.NotNil(t, err)
+		require.Nil(t, decodedPayload)
+	})
+
+	t.Run("should decode the payload of the topic", func(t *testing.T) {
+		t.Parallel()
+
+		payload := createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress)
+		decodedPayload, err := DecodePayload("gogo protobuf", outport.TopicSaveBlock, payload)
+		require.Nil(t, err)
+		require.Equal(t, []byte("headerHash"), decodedPayload.(*outport.OutportBlock).BlockData.HeaderHash)
+
+		decodedPayload, err = DecodePayload("json", outport.TopicFinalizedBlock, []byte(`{"headerHash":"aGFzaA=="}`))
+		require.Nil(t, err)
+		require.Equal(t, &outport.FinalizedBlock{HeaderHash: []byte("hash")}, decodedPayload)
+	})
+}
+
+func TestComputeIncomingHeaderHash(t *testing.T) {
+	t.Parallel()
+
+	cfg := createValidConfig()
+	cfg.SubscribedEvents[0].Addresses = []string{aliceAddress}
+
+	t.Run("invalid outport block should return error", func(t *testing.T) {
+		t.Parallel()
+
+		hash, err := ComputeIncomingHeaderHash(cfg, []byte("invalid"))
+		require.NotNil(t, err)
+		require.Nil(t, hash)
+	})
+
+	t.Run("invalid config should return error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidCfg := createValidConfig()
+		invalidCfg.HasherType = "invalid"
+		hash, err := ComputeIncomingHeaderHash(invalidCfg, createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress))
+		require.NotNil(t, err)
+		require.Nil(t, hash)
+	})
+
+	t.Run("hash should depend on the subscribed events", func(t *testing.T) {
+		t.Parallel()
+
+		subscribedBlockHash, err := ComputeIncomingHeaderHash(cfg, createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress))
+		require.Nil(t, err)
+		require.Len(t, subscribedBlockHash, 32)
+
+		sameBlockHash, err := ComputeIncomingHeaderHash(cfg, createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress))
+		require.Nil(t, err)
+		require.Equal(t, subscribedBlockHash, sameBlockHash)
+
+		notSubscribedBlockHash, err := ComputeIncomingHeaderHash(cfg, createMarshalledOutportBlock(t, "gogo protobuf", bobAddress))
+		require.Nil(t, err)
+		require.NotEqual(t, subscribedBlockHash, notSubscribedBlockHash)
+	})
+}
+
+func TestFormatIncomingHeader(t *testing.T) {
+	t.Parallel()
+
+	cfg := createValidConfig()
+	cfg.SubscribedEvents[0].Addresses = []string{aliceAddress}
+
+	t.Run("invalid outport block should return error", func(t *testing.T) {
+		t.Parallel()
+
+		jsonHeader, err := FormatIncomingHeader(cfg, []byte("invalid"))
+		require.NotNil(t, err)
+		require.Nil(t, jsonHeader)
+	})
+
+	t.Run("should format the incoming header with the subscribed events", func(t *testing.T) {
+		t.Parallel()
+
+		outportBlockBytes := createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress)
+		jsonHeader, err := FormatIncomingHeader(cfg, outportBlockBytes)
+		require.Nil(t, err)
+
+		incomingHeaderHash, err := ComputeIncomingHeaderHash(cfg, outportBlockBytes)
+		require.Nil(t, err)
+
+		formattedHeader := &formatter.IncomingHeader{}
+		err = json.Unmarshal(jsonHeader, formattedHeader)
+		require.Nil(t, err)
+		require.Equal(t, hex.EncodeToString(incomingHeaderHash), formattedHeader.IncomingHeaderHash)
+		headerBytes, _ := (&marshal.GogoProtoMarshalizer{}).Marshal(&block.HeaderV2{Header: &block.Header{Nonce: 4}})
+		require.Equal(t, hex.EncodeToString(blake2b.NewBlake2b().Compute(string(headerBytes))), formattedHeader.HeaderHash)
+		require.Equal(t, uint64(4), formattedHeader.Nonce)
+		require.Len(t, formattedHeader.Events, 1)
+		require.Equal(t, aliceAddress, formattedHeader.Events[0].Address)
+		require.Equal(t, "deposit", formattedHeader.Events[0].Identifier)
+	})
+}
@@ -0,0 +1,97 @@
+package factory
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// guardedNotifier notifies the finalized blocks under the read lock of the config reload, so that a block is never
+// notified with partially reloaded subscriptions
+type guardedNotifier struct {
+	process.SovereignNotifier
+	mutReload *sync.RWMutex
+}
+
+func newGuardedNotifier(notifier process.SovereignNotifier, mutReload *sync.RWMutex) *guardedNotifier {
+	return &guardedNotifier{
+		SovereignNotifier: notifier,
+		mutReload:         mutReload,
+	}
+}
+
+// Notify will notify the finalized block with the underlying notifier, under the read lock of the config reload
+func (gn *guardedNotifier) Notify(finalizedBlock *outport.OutportBlock) error {
+	gn.mutReload.RLock()
+	defer gn.mutReload.RUnlock()
+
+	return gn.SovereignNotifier.Notify(finalizedBlock)
+}
+
+// ComputeIncomingHeaderHash will compute the incoming header hash with the underlying notifier, if it computes them,
+// under the read lock of the config reload
+func (gn *guardedNotifier) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	hashComputer, ok := gn.SovereignNotifier.(process.IncomingHeaderHashComputer)
+	if !ok {
+		return nil, errNotIncomingHeaderHashComputer
+	}
+
+	gn.mutReload.RLock()
+	defer gn.mutReload.RUnlock()
+
+	return hashComputer.ComputeIncomingHeaderHash(outportBlock)
+}
+
+// RegisterHandlerWithPolicy will register the handler in the underlying notifier, if it supports notification policies
+func (gn *guardedNotifier) RegisterHandlerWithPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	registerer, ok := gn.SovereignNotifier.(process.PolicyHandlerRegisterer)
+	if !ok {
+		return errNotPolicyHandlerRegisterer
+	}
+
+	return registerer.RegisterHandlerWithPolicy(handler, policy)
+}
+
+// NotifyHeartbeat will notify the heartbeat through the underlying notifier, if it notifies heartbeats
+func (gn *guardedNotifier) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	heartbeatNotifier, ok := gn.SovereignNotifier.(process.HeartbeatNotifier)
+	if !ok {
+		return errNotHeartbeatNotifier
+	}
+
+	return heartbeatNotifier.NotifyHeartbeat(heartbeat)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (gn *guardedNotifier) IsInterfaceNil() bool {
+	return gn == nil
+}
+
+// guardedOutportBlockFilter filters the outport blocks under the read lock of the config reload, so that a block is
+// never filtered with partially reloaded subscriptions
+type guardedOutportBlockFilter struct {
+	process.OutportBlockFilter
+	mutReload *sync.RWMutex
+}
+
+func newGuardedOutportBlockFilter(outportBlockFilter process.OutportBlockFilter, mutReload *sync.RWMutex) *guardedOutportBlockFilter {
+	return &guardedOutportBlockFilter{
+		OutportBlockFilter: outportBlockFilter,
+		mutReload:          mutReload,
+	}
+}
+
+// FilterOutportBlock will filter the outport block with the underlying filter, under the read lock of the config reload
+func (gobf *guardedOutportBlockFilter) FilterOutportBlock(outportBlock *outport.OutportBlock) *outport.OutportBlock {
+	gobf.mutReload.RLock()
+	defer gobf.mutReload.RUnlock()
+
+	return gobf.OutportBlockFilter.FilterOutportBlock(outportBlock)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (gobf *guardedOutportBlockFilter) IsInterfaceNil() bool {
+	return gobf == nil
+}
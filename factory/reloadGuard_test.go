@@ -0,0 +1,106 @@
+package factory
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardedNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should notify under the reload lock", func(t *testing.T) {
+		t.Parallel()
+
+		mutReload := &sync.RWMutex{}
+		outportBlock := &outport.OutportBlock{}
+		notified := false
+		guard := newGuardedNotifier(&testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				require.True(t, outportBlock == finalizedBlock)
+				require.False(t, mutReload.TryLock())
+				notified = true
+				return nil
+			},
+			ComputeIncomingHeaderHashCalled: func(_ *outport.OutportBlock) ([]byte, error) {
+				require.False(t, mutReload.TryLock())
+				return []byte("hash"), nil
+			},
+		}, mutReload)
+		require.False(t, check.IfNil(guard))
+
+		err := guard.Notify(outportBlock)
+		require.Nil(t, err)
+		require.True(t, notified)
+
+		hash, err := guard.ComputeIncomingHeaderHash(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, []byte("hash"), hash)
+		require.True(t, mutReload.TryLock())
+	})
+
+	t.Run("should forward the optional methods", func(t *testing.T) {
+		t.Parallel()
+
+		registered := false
+		heartbeatNotified := false
+		guard := newGuardedNotifier(&testscommon.SovereignNotifierStub{
+			RegisterHandlerWithPolicyCalled: func(_ process.IncomingHeaderSubscriber, _ process.NotificationPolicy) error {
+				registered = true
+				return nil
+			},
+			NotifyHeartbeatCalled: func(_ *process.Heartbeat) error {
+				heartbeatNotified = true
+				return nil
+			},
+		}, &sync.RWMutex{})
+
+		err := guard.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{})
+		require.Nil(t, err)
+		require.True(t, registered)
+
+		err = guard.NotifyHeartbeat(&process.Heartbeat{})
+		require.Nil(t, err)
+		require.True(t, heartbeatNotified)
+	})
+
+	t.Run("notifier without optional methods should return errors", func(t *testing.T) {
+		t.Parallel()
+
+		guard := newGuardedNotifier(struct{ process.SovereignNotifier }{&testscommon.SovereignNotifierStub{}}, &sync.RWMutex{})
+
+		hash, err := guard.ComputeIncomingHeaderHash(&outport.OutportBlock{})
+		require.Equal(t, errNotIncomingHeaderHashComputer, err)
+		require.Nil(t, hash)
+
+		err = guard.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{})
+		require.Equal(t, errNotPolicyHandlerRegisterer, err)
+
+		err = guard.NotifyHeartbeat(&process.Heartbeat{})
+		require.Equal(t, errNotHeartbeatNotifier, err)
+	})
+}
+
+func TestGuardedOutportBlockFilter_FilterOutportBlock(t *testing.T) {
+	t.Parallel()
+
+	mutReload := &sync.RWMutex{}
+	outportBlock := &outport.OutportBlock{}
+	filteredBlock := &outport.OutportBlock{}
+	guard := newGuardedOutportBlockFilter(&testscommon.OutportBlockFilterStub{
+		FilterOutportBlockCalled: func(block *outport.OutportBlock) *outport.OutportBlock {
+			require.True(t, outportBlock == block)
+			require.False(t, mutReload.TryLock())
+			return filteredBlock
+		},
+	}, mutReload)
+	require.False(t, check.IfNil(guard))
+
+	require.True(t, filteredBlock == guard.FilterOutportBlock(outportBlock))
+	require.True(t, mutReload.TryLock())
+}
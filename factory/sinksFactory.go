@@ -0,0 +1,122 @@
+package factory
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core"
+	hashingFactory "github.com/multiversx/mx-chain-core-go/hashing/factory"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/sinks"
+)
+
+// CreateIncomingHeaderSinks creates the enabled built-in subscribers writing the notified incoming headers as NDJSON
+func CreateIncomingHeaderSinks(
+	cfg config.SinksConfig,
+	hasherType string,
+	addressPubkeyConverter core.PubkeyConverter,
+) ([]process.IncomingHeaderSink, error) {
+	headerFormatter, err := createIncomingHeaderFormatter(hasherType, addressPubkeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSinks := make([]process.IncomingHeaderSink, 0)
+	if cfg.Stdout.Enabled {
+		stdoutSink, errCreate := sinks.NewStdoutSink(headerFormatter)
+		if errCreate != nil {
+			return nil, errCreate
+		}
+
+		headerSinks = append(headerSinks, stdoutSink)
+	}
+
+	if cfg.File.Enabled {
+		fileSink, errCreate := sinks.NewFileSink(sinks.ArgsFileSink{
+			Path:                    cfg.File.Path,
+			MaxFileSizeMb:           cfg.File.MaxFileSizeMb,
+			MaxBackups:              cfg.File.MaxBackups,
+			IncomingHeaderFormatter: headerFormatter,
+		})
+		if errCreate != nil {
+			closeSinks(headerSinks)
+			return nil, errCreate
+		}
+
+		headerSinks = append(headerSinks, fileSink)
+	}
+
+	return headerSinks, nil
+}
+
+// createIncomingHeaderFormatter creates the incoming header formatter. The main chain headers are always marshalled
+// with gogo protobuf, while the hasher is the configured one
+func createIncomingHeaderFormatter(hasherType string, addressPubkeyConverter core.PubkeyConverter) (incomingHeaderFormatter, error) {
+	hasher, err := hashingFactory.NewHasher(hasherType)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatter.NewIncomingHeaderFormatterWithArgs(formatter.ArgsIncomingHeaderFormatter{
+		AddressPubkeyConverter: addressPubkeyConverter,
+		Marshaller:             &marshal.GogoProtoMarshalizer{},
+		Hasher:                 hasher,
+	})
+}
+
+// reloadableHeaderSinks forwards the notified incoming headers to the current sinks, which are created again from the
+// reloaded sinks config
+type reloadableHeaderSinks struct {
+	sinksReplacer
+	hasherType             string
+	addressPubkeyConverter core.PubkeyConverter
+}
+
+func newReloadableHeaderSinks(
+	headerSinks []process.IncomingHeaderSink,
+	hasherType string,
+	addressPubkeyConverter core.PubkeyConverter,
+) *reloadableHeaderSinks {
+	return &reloadableHeaderSinks{
+		sinksReplacer:          sinks.NewReloadableSink(headerSinks),
+		hasherType:             hasherType,
+		addressPubkeyConverter: addressPubkeyConverter,
+	}
+}
+
+// CreateSinks creates the enabled sinks from the provided config
+func (rhs *reloadableHeaderSinks) CreateSinks(cfg config.SinksConfig) ([]process.IncomingHeaderSink, error) {
+	return CreateIncomingHeaderSinks(cfg, rhs.hasherType, rhs.addressPubkeyConverter)
+}
+
+func registerSinks(sovereignNotifier process.SovereignNotifier, headerSinks []process.IncomingHeaderSink) error {
+	for _, sink := range headerSinks {
+		err := sovereignNotifier.RegisterHandler(sink)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func closeSinks(headerSinks []process.IncomingHeaderSink) {
+	for _, sink := range headerSinks {
+		log.LogIfError(sink.Close())
+	}
+}
+
+// wsClientWithSinks closes the sinks once the client is closed, so that no incoming header is notified afterwards
+type wsClientWithSinks struct {
+	process.WSClient
+	sinks []process.IncomingHeaderSink
+}
+
+// Close will close the client, then the sinks
+func (client *wsClientWithSinks) Close() error {
+	err := client.WSClient.Close()
+	closeSinks(client.sinks)
+
+	return err
+}
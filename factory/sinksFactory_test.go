@@ -0,0 +1,120 @@
+package factory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+	"github.com/stretchr/testify/require"
+)
+
+type wsClientMock struct {
+	closed bool
+}
+
+func (client *wsClientMock) Close() error {
+	client.closed = true
+	return errors.New("close error")
+}
+
+func TestCreateIncomingHeaderSinks(t *testing.T) {
+	t.Parallel()
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+
+	t.Run("nil address pubkey converter should return error", func(t *testing.T) {
+		t.Parallel()
+
+		headerSinks, err := CreateIncomingHeaderSinks(config.SinksConfig{}, "blake2b", nil)
+		require.NotNil(t, err)
+		require.Nil(t, headerSinks)
+	})
+
+	t.Run("disabled sinks should not be created", func(t *testing.T) {
+		t.Parallel()
+
+		headerSinks, err := CreateIncomingHeaderSinks(config.SinksConfig{}, "blake2b", addressPubkeyConverter)
+		require.Nil(t, err)
+		require.Empty(t, headerSinks)
+	})
+
+	t.Run("file sink without path should return error", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.SinksConfig{
+			Stdout: config.StdoutSinkConfig{Enabled: true},
+			File:   config.FileSinkConfig{Enabled: true},
+		}
+		headerSinks, err := CreateIncomingHeaderSinks(cfg, "blake2b", addressPubkeyConverter)
+		require.NotNil(t, err)
+		require.Nil(t, headerSinks)
+	})
+
+	t.Run("should write the notified incoming headers", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "incoming-headers.ndjson")
+		cfg := config.SinksConfig{
+			Stdout: config.StdoutSinkConfig{Enabled: true},
+			File:   config.FileSinkConfig{Enabled: true, Path: path, MaxFileSizeMb: 1, MaxBackups: 1},
+		}
+		headerSinks, err := CreateIncomingHeaderSinks(cfg, "blake2b", addressPubkeyConverter)
+		require.Nil(t, err)
+		require.Len(t, headerSinks, 2)
+
+		notifierCfg := createValidConfig()
+		notifierCfg.SubscribedEvents[0].Addresses = []string{aliceAddress}
+		sovereignNotifier, err := createOfflineSovereignNotifier(notifierCfg)
+		require.Nil(t, err)
+
+		// only the file sink is registered, to keep the test output clean
+		err = registerSinks(sovereignNotifier, headerSinks[1:])
+		require.Nil(t, err)
+
+		outportBlock, err := DecodePayload("gogo protobuf", outport.TopicSaveBlock, createMarshalledOutportBlock(t, "gogo protobuf", aliceAddress))
+		require.Nil(t, err)
+		err = sovereignNotifier.Notify(outportBlock.(*outport.OutportBlock))
+		require.Nil(t, err)
+
+		wsClient := &wsClientMock{}
+		client := &wsClientWithSinks{WSClient: wsClient, sinks: headerSinks}
+		require.NotNil(t, client.Close())
+		require.True(t, wsClient.closed)
+
+		content, err := os.ReadFile(path)
+		require.Nil(t, err)
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		require.Len(t, lines, 1)
+
+		formattedHeader := &formatter.IncomingHeader{}
+		err = json.Unmarshal([]byte(lines[0]), formattedHeader)
+		require.Nil(t, err)
+		require.Equal(t, uint64(4), formattedHeader.Nonce)
+		require.Len(t, formattedHeader.Events, 1)
+		require.Equal(t, aliceAddress, formattedHeader.Events[0].Address)
+	})
+}
+
+func TestReloadableHeaderSinks_CreateSinks(t *testing.T) {
+	t.Parallel()
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	reloadableSinks := newReloadableHeaderSinks(nil, "blake2b", addressPubkeyConverter)
+
+	path := filepath.Join(t.TempDir(), "incoming-headers.ndjson")
+	headerSinks, err := reloadableSinks.CreateSinks(config.SinksConfig{
+		File: config.FileSinkConfig{Enabled: true, Path: path},
+	})
+	require.Nil(t, err)
+	require.Len(t, headerSinks, 1)
+
+	reloadableSinks.ReplaceSinks(headerSinks)
+	require.Nil(t, reloadableSinks.Close())
+}
@@ -2,11 +2,18 @@ package factory
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/multiversx/mx-chain-communication-go/websocket/data"
 	factoryHost "github.com/multiversx/mx-chain-communication-go/websocket/factory"
 	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/hashing"
 	hashingFactory "github.com/multiversx/mx-chain-core-go/hashing/factory"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-core-go/marshal/factory"
@@ -14,18 +21,50 @@ import (
 
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/aggregation"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/indexer"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/liveness"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/observers"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/quorum"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/sharding"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
 )
 
 var log = logger.GetOrCreate("ws-sovereign-notifier")
 
-// ArgsCreateSovereignNotifier is a struct placeholder for sovereign notifier args
+const (
+	memoryOutportBlockCacheType  = "memory"
+	levelDBOutportBlockCacheType = "leveldb"
+)
+
+const sourcesHealthCheckInterval = time.Second
+
+// defaultHasherType is the hasher of the main chain, used by the receiver if none is provided
+const defaultHasherType = "blake2b"
+
+var nonAlphanumericRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// ArgsCreateSovereignNotifier is a struct placeholder for sovereign notifier args. The subscribed accounts are not
+// tracked if no accounts tracker is provided. The headers are verified only if VerifyHeaders is set, in which case the
+// validators tracker and the multi signature verifier are required. A single shard is assumed if no number of shards
+// is provided
 type ArgsCreateSovereignNotifier struct {
 	MarshallerType         string
 	HasherType             string
 	SubscribedEvents       []config.SubscribedEvent
+	ShadowSubscribedEvents []config.SubscribedEvent
 	AddressPubkeyConverter core.PubkeyConverter
+	AccountsTracker        process.AccountsTracker
+	ValidatorsTracker      process.ValidatorsTracker
+	MultiSigVerifier       process.MultiSigVerifier
+	VerifyHeaders          bool
+	ConsensusGroupSize     uint32
+	MetaConsensusGroupSize uint32
+	NumExtractionWorkers   uint32
+	NumShards              uint32
+	ObserverShardIDs       []uint32
 }
 
 // CreateSovereignNotifier creates a sovereign notifier which will notify subscribed handlers about incoming headers
@@ -45,74 +84,660 @@ func CreateSovereignNotifier(args ArgsCreateSovereignNotifier) (process.Sovereig
 		return nil, err
 	}
 
+	shadowSubscribedEvents, err := getSubscribedEvents(args.ShadowSubscribedEvents, args.AddressPubkeyConverter)
+	if err != nil {
+		return nil, fmt.Errorf("%w for shadow subscriptions", err)
+	}
+
+	headerVerifier, err := createHeaderVerifier(args, marshaller, hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	shardCoordinator, err := sharding.NewMultiShardCoordinator(getNumShards(args.NumShards))
+	if err != nil {
+		return nil, err
+	}
+
 	argsSovereignNotifier := notifier.ArgsSovereignNotifier{
-		Marshaller:       marshaller,
-		Hasher:           hasher,
-		SubscribedEvents: subscribedEvents,
+		Marshaller:             marshaller,
+		Hasher:                 hasher,
+		SubscribedEvents:       subscribedEvents,
+		ShadowSubscribedEvents: shadowSubscribedEvents,
+		AccountsTracker:        getAccountsTracker(args.AccountsTracker),
+		HeaderVerifier:         headerVerifier,
+		ShardCoordinator:       shardCoordinator,
+		ObserverShardIDs:       args.ObserverShardIDs,
+		NumExtractionWorkers:   args.NumExtractionWorkers,
 	}
 	return notifier.NewSovereignNotifier(argsSovereignNotifier)
 }
 
-// ArgsWsClientReceiverNotifier is a struct placeholder for ws client receiver args
+func createHeaderVerifier(
+	args ArgsCreateSovereignNotifier,
+	marshaller marshal.Marshalizer,
+	hasher hashing.Hasher,
+) (process.HeaderVerifier, error) {
+	if !args.VerifyHeaders {
+		return validators.NewDisabledHeaderVerifier(), nil
+	}
+
+	return validators.NewHeaderSignatureVerifier(validators.ArgsHeaderSignatureVerifier{
+		Marshaller:             marshaller,
+		Hasher:                 hasher,
+		MultiSigVerifier:       args.MultiSigVerifier,
+		ValidatorsTracker:      args.ValidatorsTracker,
+		ConsensusGroupSize:     args.ConsensusGroupSize,
+		MetaConsensusGroupSize: args.MetaConsensusGroupSize,
+	})
+}
+
+func getAccountsTracker(accountsTracker process.AccountsTracker) process.AccountsTracker {
+	if check.IfNil(accountsTracker) {
+		return accounts.NewDisabledAccountsTracker()
+	}
+
+	return accountsTracker
+}
+
+func getHasherType(hasherType string) string {
+	if len(hasherType) == 0 {
+		return defaultHasherType
+	}
+
+	return hasherType
+}
+
+func getLivenessTracker(livenessTracker process.LivenessTracker) process.LivenessTracker {
+	if check.IfNil(livenessTracker) {
+		return liveness.NewDisabledLivenessTracker()
+	}
+
+	return livenessTracker
+}
+
+func getNumShards(numShards uint32) uint32 {
+	if numShards == 0 {
+		return 1
+	}
+
+	return numShards
+}
+
+func getOutportBlockFilter(outportBlockFilter process.OutportBlockFilter) process.OutportBlockFilter {
+	if check.IfNil(outportBlockFilter) {
+		return notifier.NewDisabledOutportBlockFilter()
+	}
+
+	return outportBlockFilter
+}
+
+func getSourcesHealthTracker(sourcesHealthTracker process.SourcesHealthTracker) process.SourcesHealthTracker {
+	if check.IfNil(sourcesHealthTracker) {
+		return observers.NewSourcesHealthTracker(0)
+	}
+
+	return sourcesHealthTracker
+}
+
+func getValidatorsTracker(validatorsTracker process.ValidatorsTracker) process.ValidatorsTracker {
+	if check.IfNil(validatorsTracker) {
+		return validators.NewValidatorsTracker()
+	}
+
+	return validatorsTracker
+}
+
+func createValidatorsTracker(cfg config.HeaderVerificationConfig) (process.ValidatorsTracker, error) {
+	if len(cfg.ValidatorsFilePath) == 0 {
+		return validators.NewValidatorsTracker(), nil
+	}
+
+	return validators.NewValidatorsTrackerWithStorage(cfg.ValidatorsFilePath)
+}
+
+// ArgsWsClientReceiverNotifier is a struct placeholder for ws client receiver args. The subscribed accounts are not
+// tracked if no accounts tracker is provided, while the validators sets are kept only in memory if no validators
+// tracker is provided. The main chain liveness is not checked if no liveness tracker is provided, and the observers
+// health is not monitored if no sources health tracker is provided. The received blocks are compared with the main
+// chain's blake2b hasher if no hasher type is provided, and are cached unfiltered if no outport block filter is provided
 type ArgsWsClientReceiverNotifier struct {
-	WebSocketConfig   config.WebSocketConfig
-	SovereignNotifier process.SovereignNotifier
+	WebSocketConfig         config.WebSocketConfig
+	OutportBlockCacheConfig config.OutportBlockCacheConfig
+	HasherType              string
+	SovereignNotifier       process.SovereignNotifier
+	AccountsTracker         process.AccountsTracker
+	ValidatorsTracker       process.ValidatorsTracker
+	LivenessTracker         process.LivenessTracker
+	SourcesHealthTracker    process.SourcesHealthTracker
+	OutportBlockFilter      process.OutportBlockFilter
+	TopicHandlers           []indexer.TopicHandler
 }
 
-// CreateWsClientReceiverNotifier creates a ws client receiver for incoming outport blocks
+// CreateWsClientReceiverNotifier creates a ws client receiver for incoming outport blocks. If a quorum is configured,
+// each observer feeds its own indexer and finalized blocks are notified only once the quorum of observers agrees
 func CreateWsClientReceiverNotifier(args ArgsWsClientReceiverNotifier) (process.WSClient, error) {
 	marshaller, err := factory.NewMarshalizer(args.WebSocketConfig.MarshallerType)
 	if err != nil {
 		return nil, err
 	}
 
-	cache := indexer.NewOutportBlockCache()
-	dataIndexer, err := indexer.NewIndexer(args.SovereignNotifier, cache)
+	hasher, err := hashingFactory.NewHasher(getHasherType(args.HasherType))
+	if err != nil {
+		return nil, err
+	}
+
+	args.AccountsTracker = getAccountsTracker(args.AccountsTracker)
+	args.ValidatorsTracker = getValidatorsTracker(args.ValidatorsTracker)
+	args.LivenessTracker = getLivenessTracker(args.LivenessTracker)
+	args.SourcesHealthTracker = getSourcesHealthTracker(args.SourcesHealthTracker)
+	args.OutportBlockFilter = getOutportBlockFilter(args.OutportBlockFilter)
+
+	urls := getObserversUrls(args.WebSocketConfig)
+	if args.WebSocketConfig.Quorum > 0 {
+		return createQuorumWsClient(marshaller, hasher, args, urls)
+	}
+
+	payloadProcessor, err := createPayloadProcessor(marshaller, hasher, args, args.SovereignNotifier, args.OutportBlockCacheConfig.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	payloadProcessor, err := indexer.NewPayloadProcessor(dataIndexer, marshaller)
+	payloadHandlers := []process.PayloadHandler{payloadProcessor}
+	clients := make([]process.WSClient, 0)
+	for _, url := range urls {
+		client, errCreate := createSourceWsClient(marshaller, args, url, payloadProcessor)
+		if errCreate != nil {
+			closeComponents(clients, payloadHandlers)
+			return nil, errCreate
+		}
+
+		clients = append(clients, client)
+	}
+
+	return observers.NewMultiSourceClient(clients, payloadHandlers)
+}
+
+func createQuorumWsClient(
+	marshaller marshal.Marshalizer,
+	hasher hashing.Hasher,
+	args ArgsWsClientReceiverNotifier,
+	urls []string,
+) (process.WSClient, error) {
+	if int(args.WebSocketConfig.Quorum) > len(urls) {
+		return nil, fmt.Errorf("%w, quorum: %d, num observers: %d", errQuorumGreaterThanObservers, args.WebSocketConfig.Quorum, len(urls))
+	}
+
+	quorumNotifier, err := quorum.NewQuorumNotifier(quorum.ArgsQuorumNotifier{
+		Notifier:          args.SovereignNotifier,
+		AccountsTracker:   args.AccountsTracker,
+		ValidatorsTracker: args.ValidatorsTracker,
+		LivenessTracker:   args.LivenessTracker,
+		Quorum:            args.WebSocketConfig.Quorum,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payloadHandlers := make([]process.PayloadHandler, 0)
+	clients := make([]process.WSClient, 0)
+	for _, url := range urls {
+		client, payloadProcessor, errCreate := createQuorumSourceWsClient(marshaller, hasher, args, url, quorumNotifier)
+		if errCreate != nil {
+			closeComponents(clients, payloadHandlers)
+			return nil, errCreate
+		}
+
+		clients = append(clients, client)
+		payloadHandlers = append(payloadHandlers, payloadProcessor)
+	}
+
+	log.Info("created observers clients in quorum mode", "num observers", len(urls), "quorum", args.WebSocketConfig.Quorum)
+
+	return observers.NewMultiSourceClient(clients, payloadHandlers)
+}
+
+func createQuorumSourceWsClient(
+	marshaller marshal.Marshalizer,
+	hasher hashing.Hasher,
+	args ArgsWsClientReceiverNotifier,
+	url string,
+	quorumNotifier quorumSourceComponentsProvider,
+) (process.WSClient, process.PayloadHandler, error) {
+	sourceComponents, err := quorumNotifier.SourceComponents(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the observer keeps its own state, while the shared trackers are updated only with the data agreed by the quorum
+	sourceArgs := args
+	sourceArgs.AccountsTracker = sourceComponents.AccountsTracker
+	sourceArgs.ValidatorsTracker = sourceComponents.ValidatorsTracker
+	sourceArgs.LivenessTracker = sourceComponents.LivenessTracker
+
+	payloadProcessor, err := createPayloadProcessor(marshaller, hasher, sourceArgs, sourceComponents.Notifier, getSourceCachePath(args.OutportBlockCacheConfig.Path, url))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := createSourceWsClient(marshaller, args, url, payloadProcessor)
+	if err != nil {
+		_ = payloadProcessor.Close()
+		return nil, nil, err
+	}
+
+	return client, payloadProcessor, nil
+}
+
+func createPayloadProcessor(
+	marshaller marshal.Marshalizer,
+	hasher hashing.Hasher,
+	args ArgsWsClientReceiverNotifier,
+	sovereignNotifier process.SovereignNotifier,
+	cachePath string,
+) (indexer.DataProcessor, error) {
+	outportBlockCache, err := createOutportBlockCache(args.OutportBlockCacheConfig, marshaller, hasher, cachePath)
 	if err != nil {
 		return nil, err
 	}
 
-	wsHost, err := createWsHost(marshaller, args.WebSocketConfig)
+	dataIndexer, err := indexer.NewIndexerWithArgs(indexer.ArgsIndexer{
+		Notifier:          sovereignNotifier,
+		Cache:             outportBlockCache,
+		AccountsTracker:   args.AccountsTracker,
+		ValidatorsTracker: args.ValidatorsTracker,
+		LivenessTracker:   args.LivenessTracker,
+
+		OutportBlockFilter:         args.OutportBlockFilter,
+		Marshaller:                 marshaller,
+		PendingFinalizationTimeout: time.Duration(args.WebSocketConfig.PendingFinalizationTimeout) * time.Second,
+	})
 	if err != nil {
+		_ = outportBlockCache.Close()
 		return nil, err
 	}
 
-	err = wsHost.SetPayloadHandler(payloadProcessor)
+	payloadProcessor, err := indexer.NewPayloadProcessorWithArgs(indexer.ArgsPayloadProcessor{
+		Indexer:            dataIndexer,
+		Marshaller:         marshaller,
+		UnknownTopicPolicy: args.WebSocketConfig.UnknownTopicPolicy,
+	})
 	if err != nil {
+		_ = outportBlockCache.Close()
 		return nil, err
 	}
 
+	for _, topicHandler := range args.TopicHandlers {
+		err = payloadProcessor.RegisterHandler(topicHandler.Topic, topicHandler.Version, topicHandler.Handler)
+		if err != nil {
+			_ = payloadProcessor.Close()
+			return nil, err
+		}
+	}
+
+	return payloadProcessor, nil
+}
+
+func createOutportBlockCache(
+	cfg config.OutportBlockCacheConfig,
+	marshaller marshal.Marshalizer,
+	hasher hashing.Hasher,
+	path string,
+) (indexer.OutportBlockCache, error) {
+	switch cfg.Type {
+	case "", memoryOutportBlockCacheType:
+		return indexer.NewOutportBlockCacheWithArgs(indexer.ArgsOutportBlockCache{
+			Marshaller: marshaller,
+			Hasher:     hasher,
+		})
+	case levelDBOutportBlockCacheType:
+		return indexer.NewLevelDBOutportBlockCache(indexer.ArgsLevelDBOutportBlockCache{
+			Path:               path,
+			Marshaller:         marshaller,
+			Hasher:             hasher,
+			CompactionInterval: time.Duration(cfg.CompactionInterval) * time.Second,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", errInvalidOutportBlockCacheType, cfg.Type)
+	}
+}
+
+// getSourceCachePath returns the cache directory of an observer in quorum mode, derived from its url
+func getSourceCachePath(basePath string, url string) string {
+	return filepath.Join(basePath, nonAlphanumericRegex.ReplaceAllString(url, "_"))
+}
+
+func getObserversUrls(cfg config.WebSocketConfig) []string {
+	if len(cfg.Urls) == 0 {
+		return []string{cfg.Url}
+	}
+
+	return cfg.Urls
+}
+
+func createSourceWsClient(
+	marshaller marshal.Marshalizer,
+	args ArgsWsClientReceiverNotifier,
+	url string,
+	payloadHandler process.PayloadHandler,
+) (process.WSClient, error) {
+	sourcePayloadHandler, err := observers.NewSourcePayloadHandler(observers.ArgsSourcePayloadHandler{
+		Source:               url,
+		PayloadHandler:       payloadHandler,
+		SourcesHealthTracker: args.SourcesHealthTracker,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wsCfg := args.WebSocketConfig
+	wsCfg.Url = url
+	wsHost, err := createWsHost(marshaller, wsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wsHost.SetPayloadHandler(sourcePayloadHandler)
+	if err != nil {
+		_ = wsHost.Close()
+		return nil, err
+	}
+
+	log.Debug("created observer client", "url", url)
+
 	return wsHost, nil
 }
 
+func closeComponents(clients []process.WSClient, payloadHandlers []process.PayloadHandler) {
+	for _, client := range clients {
+		log.LogIfError(client.Close())
+	}
+	for _, payloadHandler := range payloadHandlers {
+		log.LogIfError(payloadHandler.Close())
+	}
+}
+
 // CreateWsSovereignNotifier will create a ws sovereign shard notifier
 func CreateWsSovereignNotifier(cfg config.Config) (process.WSClient, error) {
+	return CreateWsSovereignNotifierWithMultiSigVerifier(cfg, nil)
+}
+
+// CreateWsSovereignNotifierWithMultiSigVerifier will create a ws sovereign shard notifier which uses the provided
+// multi signature verifier to check the incoming headers signatures, if header verification is enabled. Each header
+// should be signed by 2/3+1 of the consensus group of its round, sized by the header verification config
+func CreateWsSovereignNotifierWithMultiSigVerifier(cfg config.Config, multiSigVerifier process.MultiSigVerifier) (process.WSClient, error) {
+	wsClient, _, err := CreateReloadableWsSovereignNotifier(cfg, multiSigVerifier)
+	return wsClient, err
+}
+
+// CreateReloadableWsSovereignNotifier will create a ws sovereign shard notifier, together with the component which
+// applies reloaded configs to it
+func CreateReloadableWsSovereignNotifier(
+	cfg config.Config,
+	multiSigVerifier process.MultiSigVerifier,
+) (process.WSClient, ConfigReloader, error) {
+	return createReloadableWsSovereignNotifier(cfg, multiSigVerifier, nil)
+}
+
+// createReloadableWsSovereignNotifier creates the ws sovereign shard notifier, which runs in dry run mode if dry run
+// options are provided
+func createReloadableWsSovereignNotifier(
+	cfg config.Config,
+	multiSigVerifier process.MultiSigVerifier,
+	dryRun *dryRunOptions,
+) (process.WSClient, ConfigReloader, error) {
 	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.AddressPubKeyConfig.Length, cfg.AddressPubKeyConfig.Hrp)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	accountsTracker, err := accounts.NewAccountsTracker(getSubscribedAddresses(cfg.SubscribedEvents))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validatorsTracker, err := createValidatorsTracker(cfg.HeaderVerification)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
 		MarshallerType:         cfg.WebSocketConfig.MarshallerType,
 		SubscribedEvents:       cfg.SubscribedEvents,
+		ShadowSubscribedEvents: cfg.ShadowSubscribedEvents,
 		HasherType:             cfg.HasherType,
 		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validatorsTracker,
+		MultiSigVerifier:       multiSigVerifier,
+		VerifyHeaders:          cfg.HeaderVerification.Enabled,
+		ConsensusGroupSize:     cfg.HeaderVerification.ConsensusGroupSize,
+		MetaConsensusGroupSize: cfg.HeaderVerification.MetaConsensusGroupSize,
+		NumExtractionWorkers:   cfg.ExtractionWorkers,
+		NumShards:              cfg.Sharding.NumShards,
+		ObserverShardIDs:       cfg.Sharding.ObserverShardIDs,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	heartbeatNotifier, ok := sovereignNotifier.(process.HeartbeatNotifier)
+	if !ok {
+		return nil, nil, errNotHeartbeatNotifier
+	}
+
+	deliveryNotifier := sovereignNotifier
+	if dryRun != nil {
+		deliveryNotifier, err = createDryRunNotifier(sovereignNotifier, addressPubkeyConverter, cfg.HasherType, dryRun.baselineConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// the cached blocks should keep the data of the shadow subscriptions as well
+	filteredEvents := append(append([]config.SubscribedEvent{}, cfg.SubscribedEvents...), cfg.ShadowSubscribedEvents...)
+	outportBlockFilter, err := CreateOutportBlockFilter(filteredEvents, addressPubkeyConverter, cfg.OutportBlockCache.PreFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// no incoming header is delivered in dry run mode, so the sinks are not created
+	headerSinks := make([]process.IncomingHeaderSink, 0)
+	var reloadableSinks process.IncomingHeaderSink
+	if dryRun == nil {
+		createdSinks, errCreate := CreateIncomingHeaderSinks(cfg.Sinks, cfg.HasherType, addressPubkeyConverter)
+		if errCreate != nil {
+			return nil, nil, errCreate
+		}
+
+		reloadableSinks = newReloadableHeaderSinks(createdSinks, cfg.HasherType, addressPubkeyConverter)
+		headerSinks = append(headerSinks, reloadableSinks)
+		err = registerSinks(deliveryNotifier, headerSinks)
+		if err != nil {
+			closeSinks(headerSinks)
+			return nil, nil, err
+		}
+	}
+
+	// the blocks are filtered and notified under the read lock, so a reload is applied between blocks
+	mutReload := &sync.RWMutex{}
+	configReloader, err := NewConfigReloader(ArgsConfigReloader{
+		Config:                 cfg,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		SovereignNotifier:      sovereignNotifier,
+		OutportBlockFilter:     outportBlockFilter,
+		AccountsTracker:        accountsTracker,
+		HeaderSink:             reloadableSinks,
+		ReloadLock:             mutReload,
+	})
+	if err != nil {
+		closeSinks(headerSinks)
+		return nil, nil, err
+	}
+
+	sourcesHealthTracker, err := observers.NewMonitoredSourcesHealthTracker(
+		time.Duration(cfg.WebSocketConfig.SourceUnhealthyTimeout)*time.Second,
+		sourcesHealthCheckInterval,
+	)
+	if err != nil {
+		closeSinks(headerSinks)
+		return nil, nil, err
+	}
+
+	// the liveness tracker is created last, since its checks run in background
+	livenessTracker, err := CreateLivenessTracker(cfg.Liveness, heartbeatNotifier)
+	if err != nil {
+		log.LogIfError(sourcesHealthTracker.Close())
+		closeSinks(headerSinks)
+		return nil, nil, err
+	}
+
+	receiverNotifier, err := CreateShardsAggregator(cfg.Sharding, cfg.WebSocketConfig.MarshallerType, newGuardedNotifier(deliveryNotifier, mutReload))
+	if err != nil {
+		log.LogIfError(livenessTracker.Close())
+		log.LogIfError(sourcesHealthTracker.Close())
+		closeSinks(headerSinks)
+		return nil, nil, err
+	}
+
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:         cfg.WebSocketConfig,
+		OutportBlockCacheConfig: cfg.OutportBlockCache,
+		HasherType:              cfg.HasherType,
+		SovereignNotifier:       receiverNotifier,
+		AccountsTracker:         accountsTracker,
+		ValidatorsTracker:       validatorsTracker,
+		LivenessTracker:         livenessTracker,
+		SourcesHealthTracker:    sourcesHealthTracker,
+		OutportBlockFilter:      newGuardedOutportBlockFilter(outportBlockFilter, mutReload),
+	})
+	if err != nil {
+		for _, closer := range getClosers(receiverNotifier) {
+			log.LogIfError(closer.Close())
+		}
+		log.LogIfError(livenessTracker.Close())
+		log.LogIfError(sourcesHealthTracker.Close())
+		closeSinks(headerSinks)
+		return nil, nil, err
+	}
+
+	return &wsClientWithSinks{
+		WSClient: &wsClientWithClosers{
+			WSClient: wsClient,
+			// the aggregated blocks are notified before the sovereign notifier flushes its batches
+			closers: getClosers(receiverNotifier, sovereignNotifier, sourcesHealthTracker),
+		},
+		sinks: headerSinks,
+	}, configReloader, nil
+}
+
+// wsClientWithClosers closes the notifier components once the client is closed, so that the pending notifications
+// are delivered
+type wsClientWithClosers struct {
+	process.WSClient
+	closers []io.Closer
+}
+
+// Close will close the client, then the notifier components, in order
+func (client *wsClientWithClosers) Close() error {
+	err := client.WSClient.Close()
+	for _, closer := range client.closers {
+		log.LogIfError(closer.Close())
+	}
+
+	return err
+}
+
+// getClosers returns the provided components which can be closed, in order. A component provided several times is
+// returned once
+func getClosers(components ...interface{}) []io.Closer {
+	closers := make([]io.Closer, 0, len(components))
+	for _, component := range components {
+		closer, ok := component.(io.Closer)
+		if ok && !containsCloser(closers, closer) {
+			closers = append(closers, closer)
+		}
+	}
+
+	return closers
+}
+
+func containsCloser(closers []io.Closer, closer io.Closer) bool {
+	for _, existing := range closers {
+		if existing == closer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateShardsAggregator creates the notifier which merges the finalized blocks of the observers shards into a single
+// stream ordered by round. If shards aggregation is not enabled, the provided notifier is returned
+func CreateShardsAggregator(
+	cfg config.ShardingConfig,
+	marshallerType string,
+	sovereignNotifier process.SovereignNotifier,
+) (process.SovereignNotifier, error) {
+	if !cfg.AggregateShards {
+		return sovereignNotifier, nil
+	}
+
+	marshaller, err := factory.NewMarshalizer(marshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("aggregating the finalized blocks of shards", "shards", cfg.ObserverShardIDs)
+
+	return aggregation.NewShardsAggregator(aggregation.ArgsShardsAggregator{
+		Notifier:    sovereignNotifier,
+		Marshaller:  marshaller,
+		ShardIDs:    cfg.ObserverShardIDs,
+		MaxWaitTime: time.Duration(cfg.AggregationMaxWaitMs) * time.Millisecond,
+	})
+}
+
+// CreateOutportBlockFilter creates the filter applied to the outport blocks before being cached. If pre-filtering is
+// not enabled, blocks are cached unchanged
+func CreateOutportBlockFilter(
+	events []config.SubscribedEvent,
+	addressPubkeyConverter core.PubkeyConverter,
+	preFilter bool,
+) (process.OutportBlockFilter, error) {
+	if !preFilter {
+		return notifier.NewDisabledOutportBlockFilter(), nil
+	}
+
+	subscribedEvents, err := getSubscribedEvents(events, addressPubkeyConverter)
 	if err != nil {
 		return nil, err
 	}
 
-	return CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
-		WebSocketConfig:   cfg.WebSocketConfig,
-		SovereignNotifier: sovereignNotifier,
+	return notifier.NewOutportBlockFilter(subscribedEvents)
+}
+
+// CreateLivenessTracker creates a main chain liveness tracker which notifies heartbeats through the provided notifier
+func CreateLivenessTracker(cfg config.LivenessConfig, heartbeatNotifier process.HeartbeatNotifier) (process.LivenessTracker, error) {
+	return liveness.NewLivenessTracker(liveness.ArgsLivenessTracker{
+		HeartbeatNotifier:                heartbeatNotifier,
+		CheckInterval:                    time.Duration(cfg.CheckIntervalMs) * time.Millisecond,
+		StalledChainTimeout:              time.Duration(cfg.StalledChainTimeoutMs) * time.Millisecond,
+		FinalizationTimeout:              time.Duration(cfg.FinalizationTimeoutMs) * time.Millisecond,
+		MaxConsecutiveRoundsWithoutBlock: cfg.MaxConsecutiveRoundsWithoutBlock,
+		HeartbeatInterval:                time.Duration(cfg.HeartbeatIntervalMs) * time.Millisecond,
 	})
 }
 
+func getSubscribedAddresses(events []config.SubscribedEvent) []string {
+	addresses := make([]string, 0)
+	for _, event := range events {
+		addresses = append(addresses, event.Addresses...)
+	}
+
+	return addresses
+}
+
 func getSubscribedEvents(events []config.SubscribedEvent, pubKeyConv core.PubkeyConverter) ([]notifier.SubscribedEvent, error) {
 	ret := make([]notifier.SubscribedEvent, len(events))
 	for idx, event := range events {
@@ -0,0 +1,440 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/config"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/liveness"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/observers"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/simulator"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func runTwoObserversWithNotifier(t *testing.T, quorum uint32) ([]uint64, map[string]observers.SourceHealth, []string) {
+	simulatorCfg1 := createSimulatorConfig(getFreePort(t))
+	simulatorCfg2 := createSimulatorConfig(getFreePort(t))
+	outportSimulator1, err := CreateOutportSimulator(simulatorCfg1)
+	require.Nil(t, err)
+	outportSimulator2, err := CreateOutportSimulator(simulatorCfg2)
+	require.Nil(t, err)
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	accountsTracker, _ := accounts.NewAccountsTracker(getSubscribedAddresses(simulatorCfg1.SubscribedEvents))
+	validatorsTracker := validators.NewValidatorsTracker()
+	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         simulatorCfg1.WebSocketConfig.MarshallerType,
+		HasherType:             simulatorCfg1.HasherType,
+		SubscribedEvents:       simulatorCfg1.SubscribedEvents,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validatorsTracker,
+		NumShards:              1,
+	})
+	require.Nil(t, err)
+
+	mut := sync.Mutex{}
+	notifiedNonces := make([]uint64, 0)
+	err = sovereignNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			mut.Lock()
+			notifiedNonces = append(notifiedNonces, header.GetHeaderHandler().GetNonce())
+			mut.Unlock()
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	livenessTracker, err := CreateLivenessTracker(config.LivenessConfig{CheckIntervalMs: 100}, sovereignNotifier.(process.HeartbeatNotifier))
+	require.Nil(t, err)
+
+	outportBlockFilter, err := CreateOutportBlockFilter(simulatorCfg1.SubscribedEvents, addressPubkeyConverter, true)
+	require.Nil(t, err)
+
+	notifierWsCfg := simulatorCfg1.WebSocketConfig
+	notifierWsCfg.Urls = []string{
+		"ws://" + simulatorCfg1.WebSocketConfig.Url,
+		"ws://" + simulatorCfg2.WebSocketConfig.Url,
+	}
+	notifierWsCfg.Mode = "client"
+	notifierWsCfg.Quorum = quorum
+	sourcesHealthTracker := observers.NewSourcesHealthTracker(0)
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:      notifierWsCfg,
+		HasherType:           simulatorCfg1.HasherType,
+		SovereignNotifier:    sovereignNotifier,
+		AccountsTracker:      accountsTracker,
+		ValidatorsTracker:    validatorsTracker,
+		LivenessTracker:      livenessTracker,
+		SourcesHealthTracker: sourcesHealthTracker,
+		OutportBlockFilter:   outportBlockFilter,
+	})
+	require.Nil(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	for _, outportSimulator := range []simulator.OutportSimulator{outportSimulator1, outportSimulator2} {
+		go func(sim simulator.OutportSimulator) {
+			defer wg.Done()
+
+			errRun := sim.Run(context.Background())
+			require.Nil(t, errRun)
+		}(outportSimulator)
+	}
+	wg.Wait()
+
+	_ = wsClient.Close()
+	_ = outportSimulator1.Close()
+	_ = outportSimulator2.Close()
+
+	mut.Lock()
+	defer mut.Unlock()
+
+	return notifiedNonces, sourcesHealthTracker.GetSourcesHealth(), notifierWsCfg.Urls
+}
+
+func TestCreateWsClientReceiverNotifier_MultipleObserversAreDeduplicated(t *testing.T) {
+	notifiedNonces, sourcesHealth, urls := runTwoObserversWithNotifier(t, 0)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, notifiedNonces)
+	require.Len(t, sourcesHealth, 2)
+	for _, url := range urls {
+		require.True(t, sourcesHealth[url].IsHealthy)
+		require.Zero(t, sourcesHealth[url].NumErrors)
+	}
+}
+
+func TestCreateWsClientReceiverNotifier_QuorumOfObservers(t *testing.T) {
+	notifiedNonces, sourcesHealth, urls := runTwoObserversWithNotifier(t, 2)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, notifiedNonces)
+	require.Len(t, sourcesHealth, 2)
+	for _, url := range urls {
+		require.True(t, sourcesHealth[url].IsHealthy)
+		require.Zero(t, sourcesHealth[url].NumErrors)
+	}
+}
+
+func TestCreateWsClientReceiverNotifier_QuorumGreaterThanObservers(t *testing.T) {
+	t.Parallel()
+
+	wsCfg := createSimulatorConfig(getFreePort(t)).WebSocketConfig
+	wsCfg.Mode = "client"
+	wsCfg.Urls = []string{"ws://127.0.0.1:1", "ws://127.0.0.1:2"}
+	wsCfg.Quorum = 3
+
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:      wsCfg,
+		HasherType:           "blake2b",
+		SovereignNotifier:    &testscommon.SovereignNotifierStub{},
+		AccountsTracker:      &testscommon.AccountsTrackerStub{},
+		ValidatorsTracker:    &testscommon.ValidatorsTrackerStub{},
+		LivenessTracker:      &testscommon.LivenessTrackerStub{},
+		SourcesHealthTracker: observers.NewSourcesHealthTracker(0),
+		OutportBlockFilter:   &testscommon.OutportBlockFilterStub{},
+	})
+	require.ErrorIs(t, err, errQuorumGreaterThanObservers)
+	require.Nil(t, wsClient)
+}
+
+func TestCreateSovereignNotifier_OldArgsShouldWork(t *testing.T) {
+	t.Parallel()
+
+	simulatorCfg := createSimulatorConfig(getFreePort(t))
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         simulatorCfg.WebSocketConfig.MarshallerType,
+		HasherType:             simulatorCfg.HasherType,
+		SubscribedEvents:       simulatorCfg.SubscribedEvents,
+		AddressPubkeyConverter: addressPubkeyConverter,
+	})
+	require.Nil(t, err)
+	require.False(t, check.IfNil(sovereignNotifier))
+}
+
+func TestCreateWsClientReceiverNotifier_OldArgsShouldWork(t *testing.T) {
+	t.Parallel()
+
+	wsCfg := createSimulatorConfig(getFreePort(t)).WebSocketConfig
+	wsCfg.Mode = "client"
+	wsCfg.Url = "ws://127.0.0.1:1"
+
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:   wsCfg,
+		SovereignNotifier: &testscommon.SovereignNotifierStub{},
+	})
+	require.Nil(t, err)
+	require.NotNil(t, wsClient)
+	_ = wsClient.Close()
+}
+
+func TestCreateWsClientReceiverNotifier_OutportBlockCache(t *testing.T) {
+	t.Parallel()
+
+	createArgs := func(cacheCfg config.OutportBlockCacheConfig, quorum uint32) ArgsWsClientReceiverNotifier {
+		wsCfg := createSimulatorConfig(getFreePort(t)).WebSocketConfig
+		wsCfg.Mode = "client"
+		wsCfg.Urls = []string{"ws://127.0.0.1:1", "ws://127.0.0.1:2"}
+		wsCfg.Quorum = quorum
+
+		return ArgsWsClientReceiverNotifier{
+			WebSocketConfig:         wsCfg,
+			OutportBlockCacheConfig: cacheCfg,
+			HasherType:              "blake2b",
+			SovereignNotifier:       &testscommon.SovereignNotifierStub{},
+			AccountsTracker:         &testscommon.AccountsTrackerStub{},
+			ValidatorsTracker:       &testscommon.ValidatorsTrackerStub{},
+			LivenessTracker:         &testscommon.LivenessTrackerStub{},
+			SourcesHealthTracker:    observers.NewSourcesHealthTracker(0),
+			OutportBlockFilter:      &testscommon.OutportBlockFilterStub{},
+		}
+	}
+
+	t.Run("invalid type, should return error", func(t *testing.T) {
+		wsClient, err := CreateWsClientReceiverNotifier(createArgs(config.OutportBlockCacheConfig{Type: "type"}, 0))
+		require.ErrorIs(t, err, errInvalidOutportBlockCacheType)
+		require.Nil(t, wsClient)
+	})
+
+	t.Run("leveldb cache should work", func(t *testing.T) {
+		cacheCfg := config.OutportBlockCacheConfig{
+			Type: levelDBOutportBlockCacheType,
+			Path: t.TempDir(),
+		}
+		wsClient, err := CreateWsClientReceiverNotifier(createArgs(cacheCfg, 0))
+		require.Nil(t, err)
+		_ = wsClient.Close()
+	})
+
+	t.Run("leveldb cache in quorum mode should create a cache for each observer", func(t *testing.T) {
+		cacheCfg := config.OutportBlockCacheConfig{
+			Type: levelDBOutportBlockCacheType,
+			Path: t.TempDir(),
+		}
+		wsClient, err := CreateWsClientReceiverNotifier(createArgs(cacheCfg, 2))
+		require.Nil(t, err)
+		require.DirExists(t, getSourceCachePath(cacheCfg.Path, "ws://127.0.0.1:1"))
+		require.DirExists(t, getSourceCachePath(cacheCfg.Path, "ws://127.0.0.1:2"))
+		_ = wsClient.Close()
+	})
+}
+
+func TestCreateShardsAggregator_EndToEndWithTwoShards(t *testing.T) {
+	shardIDs := []uint32{0, 1}
+	simulatorsCfg := make([]config.SimulatorConfig, 0, len(shardIDs))
+	outportSimulators := make([]simulator.OutportSimulator, 0, len(shardIDs))
+	urls := make([]string, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		simulatorCfg := createSimulatorConfig(getFreePort(t))
+		simulatorCfg.ShardID = shardID
+		simulatorCfg.Seed = int64(shardID) + 1
+		outportSimulator, err := CreateOutportSimulator(simulatorCfg)
+		require.Nil(t, err)
+
+		simulatorsCfg = append(simulatorsCfg, simulatorCfg)
+		outportSimulators = append(outportSimulators, outportSimulator)
+		urls = append(urls, "ws://"+simulatorCfg.WebSocketConfig.Url)
+	}
+
+	simulatorCfg := simulatorsCfg[0]
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	accountsTracker, _ := accounts.NewAccountsTracker(getSubscribedAddresses(simulatorCfg.SubscribedEvents))
+	validatorsTracker := validators.NewValidatorsTracker()
+	sovereignNotifier, err := CreateSovereignNotifier(ArgsCreateSovereignNotifier{
+		MarshallerType:         simulatorCfg.WebSocketConfig.MarshallerType,
+		HasherType:             simulatorCfg.HasherType,
+		SubscribedEvents:       simulatorCfg.SubscribedEvents,
+		AddressPubkeyConverter: addressPubkeyConverter,
+		AccountsTracker:        accountsTracker,
+		ValidatorsTracker:      validatorsTracker,
+		NumShards:              2,
+	})
+	require.Nil(t, err)
+
+	type notifiedHeader struct {
+		round   uint64
+		shardID uint32
+	}
+	mut := sync.Mutex{}
+	notifiedHeaders := make([]notifiedHeader, 0)
+	err = sovereignNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			mut.Lock()
+			notifiedHeaders = append(notifiedHeaders, notifiedHeader{
+				round:   header.GetHeaderHandler().GetRound(),
+				shardID: header.GetHeaderHandler().GetShardID(),
+			})
+			mut.Unlock()
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	shardingCfg := config.ShardingConfig{
+		NumShards:            2,
+		ObserverShardIDs:     shardIDs,
+		AggregateShards:      true,
+		AggregationMaxWaitMs: 3000,
+	}
+	aggregator, err := CreateShardsAggregator(shardingCfg, simulatorCfg.WebSocketConfig.MarshallerType, sovereignNotifier)
+	require.Nil(t, err)
+
+	livenessTracker, err := CreateLivenessTracker(config.LivenessConfig{CheckIntervalMs: 100}, sovereignNotifier.(process.HeartbeatNotifier))
+	require.Nil(t, err)
+
+	outportBlockFilter, err := CreateOutportBlockFilter(simulatorCfg.SubscribedEvents, addressPubkeyConverter, true)
+	require.Nil(t, err)
+
+	notifierWsCfg := simulatorCfg.WebSocketConfig
+	notifierWsCfg.Urls = urls
+	notifierWsCfg.Mode = "client"
+	wsClient, err := CreateWsClientReceiverNotifier(ArgsWsClientReceiverNotifier{
+		WebSocketConfig:      notifierWsCfg,
+		HasherType:           simulatorCfg.HasherType,
+		SovereignNotifier:    aggregator,
+		AccountsTracker:      accountsTracker,
+		ValidatorsTracker:    validatorsTracker,
+		LivenessTracker:      livenessTracker,
+		SourcesHealthTracker: observers.NewSourcesHealthTracker(0),
+		OutportBlockFilter:   outportBlockFilter,
+	})
+	require.Nil(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(outportSimulators))
+	for _, outportSimulator := range outportSimulators {
+		go func(sim simulator.OutportSimulator) {
+			defer wg.Done()
+
+			errRun := sim.Run(context.Background())
+			require.Nil(t, errRun)
+		}(outportSimulator)
+	}
+	wg.Wait()
+
+	// the last blocks wait for the max wait time, since the other shard does not finalize later rounds
+	numBlocks := len(shardIDs) * int(simulatorCfg.NumBlocks)
+	require.Eventually(t, func() bool {
+		mut.Lock()
+		defer mut.Unlock()
+
+		return len(notifiedHeaders) == numBlocks
+	}, 10*time.Second, 50*time.Millisecond)
+
+	_ = wsClient.Close()
+	for _, outportSimulator := range outportSimulators {
+		_ = outportSimulator.Close()
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+
+	isOrdered := sort.SliceIsSorted(notifiedHeaders, func(i, j int) bool {
+		if notifiedHeaders[i].round != notifiedHeaders[j].round {
+			return notifiedHeaders[i].round < notifiedHeaders[j].round
+		}
+
+		return notifiedHeaders[i].shardID < notifiedHeaders[j].shardID
+	})
+	require.True(t, isOrdered, "headers should be ordered by round, then by shard: %v", notifiedHeaders)
+}
+
+type closerStub struct {
+	closeCalled func() error
+}
+
+func (stub *closerStub) Close() error {
+	return stub.closeCalled()
+}
+
+func TestWsClientWithClosers_Close(t *testing.T) {
+	t.Parallel()
+
+	closed := make([]string, 0)
+	wsClient := &wsClientMock{}
+	firstCloser := &closerStub{closeCalled: func() error {
+		require.True(t, wsClient.closed)
+		closed = append(closed, "first")
+		return errors.New("first close error")
+	}}
+	secondCloser := &closerStub{closeCalled: func() error {
+		closed = append(closed, "second")
+		return nil
+	}}
+
+	client := &wsClientWithClosers{
+		WSClient: wsClient,
+		closers:  getClosers(firstCloser, "not a closer", secondCloser, firstCloser),
+	}
+	require.Equal(t, "close error", client.Close().Error())
+	require.Equal(t, []string{"first", "second"}, closed)
+}
+
+func TestGetAccountsTracker(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, accounts.NewDisabledAccountsTracker(), getAccountsTracker(nil))
+
+	accountsTracker, _ := accounts.NewAccountsTracker([]string{aliceAddress})
+	require.True(t, accountsTracker == getAccountsTracker(accountsTracker))
+}
+
+func TestGetValidatorsTracker(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, check.IfNil(getValidatorsTracker(nil)))
+
+	validatorsTracker := validators.NewValidatorsTracker()
+	require.True(t, validatorsTracker == getValidatorsTracker(validatorsTracker))
+}
+
+func TestGetLivenessTracker(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, liveness.NewDisabledLivenessTracker(), getLivenessTracker(nil))
+
+	livenessTracker := &testscommon.LivenessTrackerStub{}
+	require.True(t, livenessTracker == getLivenessTracker(livenessTracker))
+}
+
+func TestGetHasherType(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, defaultHasherType, getHasherType(""))
+	require.Equal(t, "sha256", getHasherType("sha256"))
+}
+
+func TestGetNumShards(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, uint32(1), getNumShards(0))
+	require.Equal(t, uint32(3), getNumShards(3))
+}
+
+func TestGetOutportBlockFilter(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, check.IfNil(getOutportBlockFilter(nil)))
+
+	outportBlockFilter := notifier.NewDisabledOutportBlockFilter()
+	require.True(t, outportBlockFilter == getOutportBlockFilter(outportBlockFilter))
+}
+
+func TestGetSourcesHealthTracker(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, check.IfNil(getSourcesHealthTracker(nil)))
+
+	sourcesHealthTracker := observers.NewSourcesHealthTracker(0)
+	require.True(t, sourcesHealthTracker == getSourcesHealthTracker(sourcesHealthTracker))
+}
@@ -9,6 +9,7 @@ require (
 	github.com/multiversx/mx-chain-core-go v1.2.21
 	github.com/multiversx/mx-chain-logger-go v1.0.15
 	github.com/stretchr/testify v1.8.4
+	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
 	github.com/urfave/cli v1.22.9
 )
 
@@ -19,6 +20,7 @@ require (
 	github.com/denisbrodbeck/machineid v1.0.1 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/golang/snappy v0.0.4 // indirect
 	github.com/gorilla/mux v1.8.0 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/mr-tron/base58 v1.2.0 // indirect
@@ -0,0 +1,194 @@
+package accounts
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("notifier-accounts-process")
+
+const zeroBalance = "0"
+
+type accountsTracker struct {
+	mutAccounts         sync.RWMutex
+	subscribedAddresses map[string]struct{}
+	accounts            map[string]*alteredAccount.AlteredAccount
+}
+
+// NewAccountsTracker creates a tracker which keeps the latest state of the provided bech32 encoded addresses
+func NewAccountsTracker(subscribedAddresses []string) (*accountsTracker, error) {
+	if len(subscribedAddresses) == 0 {
+		return nil, errNoSubscribedAddresses
+	}
+
+	return &accountsTracker{
+		subscribedAddresses: getAddressesSet(subscribedAddresses),
+		accounts:            make(map[string]*alteredAccount.AlteredAccount),
+	}, nil
+}
+
+func getAddressesSet(addresses []string) map[string]struct{} {
+	addressesSet := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		addressesSet[address] = struct{}{}
+	}
+
+	return addressesSet
+}
+
+// UpdateSubscribedAddresses will replace the tracked addresses. The state of the addresses which are no longer
+// subscribed is dropped, while the state of the new addresses is saved from the next altered accounts
+func (at *accountsTracker) UpdateSubscribedAddresses(subscribedAddresses []string) error {
+	if len(subscribedAddresses) == 0 {
+		return errNoSubscribedAddresses
+	}
+
+	addresses := getAddressesSet(subscribedAddresses)
+
+	at.mutAccounts.Lock()
+	defer at.mutAccounts.Unlock()
+
+	at.subscribedAddresses = addresses
+	for encodedAddr := range at.accounts {
+		_, isSubscribed := addresses[encodedAddr]
+		if !isSubscribed {
+			delete(at.accounts, encodedAddr)
+		}
+	}
+
+	return nil
+}
+
+// UpdateAccounts will save the state of the altered accounts which are subscribed. The account fields are overwritten,
+// while the tokens are merged by identifier and nonce with the previously saved ones, since an altered account only
+// holds the tokens altered in its block. Tokens left with a zero balance are dropped
+func (at *accountsTracker) UpdateAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount) {
+	at.mutAccounts.Lock()
+	defer at.mutAccounts.Unlock()
+
+	updatedAddresses := at.applyAccounts(at.accounts, alteredAccounts)
+	for _, encodedAddr := range updatedAddresses {
+		account := at.accounts[encodedAddr]
+		log.Debug("updated subscribed account", "address", encodedAddr, "nonce", account.Nonce, "balance", account.Balance)
+	}
+}
+
+// GetUpdatedAccounts returns the state of the subscribed accounts after applying the altered accounts, without saving it
+func (at *accountsTracker) GetUpdatedAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount {
+	at.mutAccounts.RLock()
+	defer at.mutAccounts.RUnlock()
+
+	accounts := at.copyAccounts()
+	at.applyAccounts(accounts, alteredAccounts)
+
+	return accounts
+}
+
+// applyAccounts merges the subscribed altered accounts into the provided accounts and returns their addresses
+func (at *accountsTracker) applyAccounts(
+	accounts map[string]*alteredAccount.AlteredAccount,
+	alteredAccounts map[string]*alteredAccount.AlteredAccount,
+) []string {
+	updatedAddresses := make([]string, 0, len(alteredAccounts))
+	for encodedAddr, account := range alteredAccounts {
+		if account == nil {
+			continue
+		}
+		if len(account.Address) != 0 {
+			encodedAddr = account.Address
+		}
+
+		_, isSubscribed := at.subscribedAddresses[encodedAddr]
+		if !isSubscribed {
+			continue
+		}
+
+		accounts[encodedAddr] = mergeAccounts(accounts[encodedAddr], account)
+		updatedAddresses = append(updatedAddresses, encodedAddr)
+	}
+
+	return updatedAddresses
+}
+
+func mergeAccounts(
+	savedAccount *alteredAccount.AlteredAccount,
+	updatedAccount *alteredAccount.AlteredAccount,
+) *alteredAccount.AlteredAccount {
+	mergedAccount := &alteredAccount.AlteredAccount{
+		Address:        updatedAccount.Address,
+		Nonce:          updatedAccount.Nonce,
+		Balance:        updatedAccount.Balance,
+		AdditionalData: updatedAccount.AdditionalData,
+	}
+	if savedAccount == nil {
+		mergedAccount.Tokens = mergeTokens(nil, updatedAccount.Tokens)
+		return mergedAccount
+	}
+
+	if mergedAccount.AdditionalData == nil {
+		mergedAccount.AdditionalData = savedAccount.AdditionalData
+	}
+	mergedAccount.Tokens = mergeTokens(savedAccount.Tokens, updatedAccount.Tokens)
+
+	return mergedAccount
+}
+
+// mergeTokens keeps the order of the saved tokens, replacing the updated ones in place and appending the new ones
+func mergeTokens(savedTokens []*alteredAccount.AccountTokenData, updatedTokens []*alteredAccount.AccountTokenData) []*alteredAccount.AccountTokenData {
+	tokensIndexes := make(map[string]int, len(savedTokens)+len(updatedTokens))
+	mergedTokens := make([]*alteredAccount.AccountTokenData, 0, len(savedTokens)+len(updatedTokens))
+	for _, tokens := range [][]*alteredAccount.AccountTokenData{savedTokens, updatedTokens} {
+		for _, token := range tokens {
+			if token == nil {
+				continue
+			}
+
+			key := fmt.Sprintf("%s-%d", token.Identifier, token.Nonce)
+			idx, found := tokensIndexes[key]
+			if found {
+				mergedTokens[idx] = token
+				continue
+			}
+
+			tokensIndexes[key] = len(mergedTokens)
+			mergedTokens = append(mergedTokens, token)
+		}
+	}
+
+	nonZeroTokens := make([]*alteredAccount.AccountTokenData, 0, len(mergedTokens))
+	for _, token := range mergedTokens {
+		if token.Balance != zeroBalance {
+			nonZeroTokens = append(nonZeroTokens, token)
+		}
+	}
+	if len(nonZeroTokens) == 0 {
+		return nil
+	}
+
+	return nonZeroTokens
+}
+
+// GetAccounts returns the latest state of the subscribed accounts, mapped by their bech32 encoded address
+func (at *accountsTracker) GetAccounts() map[string]*alteredAccount.AlteredAccount {
+	at.mutAccounts.RLock()
+	defer at.mutAccounts.RUnlock()
+
+	return at.copyAccounts()
+}
+
+func (at *accountsTracker) copyAccounts() map[string]*alteredAccount.AlteredAccount {
+	accounts := make(map[string]*alteredAccount.AlteredAccount, len(at.accounts))
+	for encodedAddr, account := range at.accounts {
+		accounts[encodedAddr] = account
+	}
+
+	return accounts
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (at *accountsTracker) IsInterfaceNil() bool {
+	return at == nil
+}
@@ -0,0 +1,169 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountsTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		tracker, err := NewAccountsTracker([]string{"erd1a"})
+		require.Nil(t, err)
+		require.False(t, check.IfNil(tracker))
+		require.Empty(t, tracker.GetAccounts())
+	})
+
+	t.Run("no subscribed addresses, should return error", func(t *testing.T) {
+		tracker, err := NewAccountsTracker(nil)
+		require.Equal(t, errNoSubscribedAddresses, err)
+		require.Nil(t, tracker)
+	})
+}
+
+func TestAccountsTracker_UpdateAccounts(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewAccountsTracker([]string{"erd1a", "erd1b"})
+
+	acc1 := &alteredAccount.AlteredAccount{Address: "erd1a", Balance: "10"}
+	acc2 := &alteredAccount.AlteredAccount{Balance: "20"}
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": acc1,
+		"erd1b": acc2,
+		"erd1c": {Address: "erd1c", Balance: "30"},
+		"erd1d": nil,
+	})
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{
+		"erd1a": acc1,
+		"erd1b": acc2,
+	}, tracker.GetAccounts())
+
+	acc1Updated := &alteredAccount.AlteredAccount{
+		Address: "erd1a",
+		Balance: "5",
+		Tokens: []*alteredAccount.AccountTokenData{
+			{
+				Identifier: "TKN-123456",
+				Balance:    "100",
+			},
+		},
+	}
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": acc1Updated,
+	})
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{
+		"erd1a": acc1Updated,
+		"erd1b": acc2,
+	}, tracker.GetAccounts())
+}
+
+func TestAccountsTracker_UpdateAccountsMergesTokens(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewAccountsTracker([]string{"erd1a"})
+
+	additionalData := &alteredAccount.AdditionalAccountData{DeveloperRewards: "1"}
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {
+			Address:        "erd1a",
+			Balance:        "10",
+			AdditionalData: additionalData,
+			Tokens: []*alteredAccount.AccountTokenData{
+				{Identifier: "TKN-123456", Balance: "100"},
+				{Identifier: "NFT-123456", Nonce: 1, Balance: "1"},
+				{Identifier: "NFT-123456", Nonce: 2, Balance: "1"},
+			},
+		},
+	})
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {
+			Address: "erd1a",
+			Nonce:   1,
+			Balance: "9",
+			Tokens: []*alteredAccount.AccountTokenData{
+				{Identifier: "NFT-123456", Nonce: 1, Balance: "0"},
+				{Identifier: "TKN-123456", Balance: "150"},
+				{Identifier: "OTHER-123456", Balance: "5"},
+			},
+		},
+	})
+
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {
+			Address:        "erd1a",
+			Nonce:          1,
+			Balance:        "9",
+			AdditionalData: additionalData,
+			Tokens: []*alteredAccount.AccountTokenData{
+				{Identifier: "TKN-123456", Balance: "150"},
+				{Identifier: "NFT-123456", Nonce: 2, Balance: "1"},
+				{Identifier: "OTHER-123456", Balance: "5"},
+			},
+		},
+	}, tracker.GetAccounts())
+}
+
+func TestAccountsTracker_GetAccountsReturnsCopy(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewAccountsTracker([]string{"erd1a"})
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a"},
+	})
+
+	accounts := tracker.GetAccounts()
+	delete(accounts, "erd1a")
+	require.Len(t, tracker.GetAccounts(), 1)
+}
+
+func TestAccountsTracker_GetUpdatedAccounts(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewAccountsTracker([]string{"erd1a", "erd1b"})
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
+	})
+
+	updatedAccounts := tracker.GetUpdatedAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1b": {Address: "erd1b", Balance: "2"},
+		"erd1c": {Address: "erd1c", Balance: "3"},
+	})
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
+		"erd1b": {Address: "erd1b", Balance: "2"},
+	}, updatedAccounts)
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
+	}, tracker.GetAccounts())
+}
+
+func TestAccountsTracker_UpdateSubscribedAddresses(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewAccountsTracker([]string{"erd1a", "erd1b"})
+	accA := &alteredAccount.AlteredAccount{Address: "erd1a", Balance: "10"}
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1a": accA,
+		"erd1b": {Address: "erd1b", Balance: "20"},
+	})
+
+	err := tracker.UpdateSubscribedAddresses(nil)
+	require.Equal(t, errNoSubscribedAddresses, err)
+	require.Len(t, tracker.GetAccounts(), 2)
+
+	err = tracker.UpdateSubscribedAddresses([]string{"erd1a", "erd1c"})
+	require.Nil(t, err)
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{"erd1a": accA}, tracker.GetAccounts())
+
+	accC := &alteredAccount.AlteredAccount{Address: "erd1c", Balance: "30"}
+	tracker.UpdateAccounts(map[string]*alteredAccount.AlteredAccount{
+		"erd1b": {Address: "erd1b", Balance: "21"},
+		"erd1c": accC,
+	})
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{"erd1a": accA, "erd1c": accC}, tracker.GetAccounts())
+}
@@ -0,0 +1,30 @@
+package accounts
+
+import "github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+
+type disabledAccountsTracker struct {
+}
+
+// NewDisabledAccountsTracker creates an accounts tracker which keeps no state, used when no account is tracked
+func NewDisabledAccountsTracker() *disabledAccountsTracker {
+	return &disabledAccountsTracker{}
+}
+
+// UpdateAccounts does nothing
+func (dat *disabledAccountsTracker) UpdateAccounts(_ map[string]*alteredAccount.AlteredAccount) {
+}
+
+// GetAccounts returns an empty map
+func (dat *disabledAccountsTracker) GetAccounts() map[string]*alteredAccount.AlteredAccount {
+	return make(map[string]*alteredAccount.AlteredAccount)
+}
+
+// GetUpdatedAccounts returns an empty map
+func (dat *disabledAccountsTracker) GetUpdatedAccounts(_ map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount {
+	return make(map[string]*alteredAccount.AlteredAccount)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (dat *disabledAccountsTracker) IsInterfaceNil() bool {
+	return dat == nil
+}
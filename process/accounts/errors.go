@@ -0,0 +1,5 @@
+package accounts
+
+import "errors"
+
+var errNoSubscribedAddresses = errors.New("no subscribed addresses provided")
@@ -0,0 +1,25 @@
+package aggregation
+
+import "errors"
+
+var errNilSovereignNotifier = errors.New("nil sovereign notifier provided")
+
+var errNilMarshaller = errors.New("nil marshaller provided")
+
+var errNoShardsProvided = errors.New("no shards provided")
+
+var errInvalidMaxWaitTime = errors.New("invalid max wait time provided")
+
+var errNilBlockData = errors.New("nil block data provided")
+
+var errInvalidHeaderType = errors.New("invalid header type received")
+
+var errUnknownShard = errors.New("received block of a not aggregated shard")
+
+var errNotIncomingHeaderHashComputer = errors.New("sovereign notifier does not compute incoming header hashes")
+
+var errNotPolicyHandlerRegisterer = errors.New("sovereign notifier does not support notification policies")
+
+var errNotHeartbeatNotifier = errors.New("sovereign notifier does not notify heartbeats")
+
+var errAggregatorClosed = errors.New("shards aggregator is closed")
@@ -0,0 +1,354 @@
+package aggregation
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+var log = logger.GetOrCreate("notifier-aggregation-process")
+
+// ArgsShardsAggregator is a struct placeholder for args needed to create a shards aggregator. A zero max wait time
+// makes the aggregator wait indefinitely for lagging shards
+type ArgsShardsAggregator struct {
+	Notifier    process.SovereignNotifier
+	Marshaller  marshal.Marshalizer
+	ShardIDs    []uint32
+	MaxWaitTime time.Duration
+}
+
+type pendingBlock struct {
+	outportBlock     *outport.OutportBlock
+	shardID          uint32
+	round            uint64
+	receivedAt       time.Time
+	deliveryHandlers []func()
+}
+
+type shardsAggregator struct {
+	notifier        process.SovereignNotifier
+	marshaller      marshal.Marshalizer
+	headerV2Creator block.EmptyBlockCreator
+	shardIDs        []uint32
+	maxWaitTime     time.Duration
+
+	mutPending        sync.Mutex
+	pendingBlocks     []*pendingBlock
+	lastRounds        map[uint32]uint64
+	lastNotifiedRound uint64
+	timer             *time.Timer
+	isClosed          bool
+}
+
+// NewShardsAggregator creates a notifier which merges the finalized blocks of several shards into a single stream
+// ordered by round, then by shard id. Since rounds are common to all shards, a block is forwarded once every other
+// aggregated shard finalized a block of the same or a later round, or once it waited for the max wait time. The blocks
+// are queued until forwarded, so the callers should be told about their delivery through NotifyWithDeliveryHandler
+func NewShardsAggregator(args ArgsShardsAggregator) (*shardsAggregator, error) {
+	if check.IfNil(args.Notifier) {
+		return nil, errNilSovereignNotifier
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if len(args.ShardIDs) == 0 {
+		return nil, errNoShardsProvided
+	}
+	if args.MaxWaitTime < 0 {
+		return nil, errInvalidMaxWaitTime
+	}
+
+	return &shardsAggregator{
+		notifier:        args.Notifier,
+		marshaller:      args.Marshaller,
+		headerV2Creator: block.NewEmptyHeaderV2Creator(),
+		shardIDs:        args.ShardIDs,
+		maxWaitTime:     args.MaxWaitTime,
+		lastRounds:      make(map[uint32]uint64),
+	}, nil
+}
+
+// Notify will add the finalized block to the merged stream and forward all the blocks which are ready, in order
+func (sa *shardsAggregator) Notify(finalizedBlock *outport.OutportBlock) error {
+	return sa.NotifyWithDeliveryHandler(finalizedBlock, nil)
+}
+
+// NotifyWithDeliveryHandler will queue the finalized block in the merged stream and forward all the blocks which are
+// ready, in order. A nil error means the block was queued, while the delivery handler, if provided, is called once it
+// was delivered. The failed deliveries are logged and retried on the next notification, on timeout or on close, while
+// the blocks which can never be delivered are dropped
+func (sa *shardsAggregator) NotifyWithDeliveryHandler(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error {
+	header, err := sa.getHeader(finalizedBlock)
+	if err != nil {
+		return fmt.Errorf("%w: %w", process.ErrInvalidOutportBlock, err)
+	}
+
+	shardID := header.GetShardID()
+	if !sa.isAggregatedShard(shardID) {
+		return fmt.Errorf("%w: %w: %d", process.ErrInvalidOutportBlock, errUnknownShard, shardID)
+	}
+
+	sa.mutPending.Lock()
+	defer sa.mutPending.Unlock()
+
+	if sa.isClosed {
+		return errAggregatorClosed
+	}
+
+	sa.addPendingBlock(&pendingBlock{
+		outportBlock: finalizedBlock,
+		shardID:      shardID,
+		round:        header.GetRound(),
+		receivedAt:   time.Now(),
+	}, deliveryHandler)
+	lastRound, found := sa.lastRounds[shardID]
+	if !found || header.GetRound() > lastRound {
+		sa.lastRounds[shardID] = header.GetRound()
+	}
+
+	err = sa.notifyReadyBlocks()
+	if err != nil {
+		log.Warn("could not notify aggregated blocks, will retry", "error", err)
+	}
+
+	return nil
+}
+
+func (sa *shardsAggregator) getHeader(outportBlock *outport.OutportBlock) (*block.HeaderV2, error) {
+	if outportBlock == nil || outportBlock.BlockData == nil {
+		return nil, errNilBlockData
+	}
+
+	headerType := core.HeaderType(outportBlock.BlockData.HeaderType)
+	if headerType != core.ShardHeaderV2 {
+		return nil, fmt.Errorf("%w : %s, expected: %s", errInvalidHeaderType, headerType, core.ShardHeaderV2)
+	}
+
+	headerHandler, err := block.GetHeaderFromBytes(sa.marshaller, sa.headerV2Creator, outportBlock.BlockData.HeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return headerHandler.(*block.HeaderV2), nil
+}
+
+func (sa *shardsAggregator) isAggregatedShard(shardID uint32) bool {
+	for _, id := range sa.shardIDs {
+		if id == shardID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addPendingBlock keeps the pending blocks sorted by round, then by shard id. A block which is already pending, such as
+// a block re-sent by another observer, is not added again, only its delivery handler
+func (sa *shardsAggregator) addPendingBlock(pending *pendingBlock, deliveryHandler func()) {
+	for _, current := range sa.pendingBlocks {
+		if bytes.Equal(current.outportBlock.BlockData.HeaderHash, pending.outportBlock.BlockData.HeaderHash) {
+			current.addDeliveryHandler(deliveryHandler)
+			return
+		}
+	}
+
+	pending.addDeliveryHandler(deliveryHandler)
+
+	idx := sort.Search(len(sa.pendingBlocks), func(i int) bool {
+		current := sa.pendingBlocks[i]
+		if current.round != pending.round {
+			return current.round > pending.round
+		}
+
+		return current.shardID > pending.shardID
+	})
+
+	sa.pendingBlocks = append(sa.pendingBlocks, nil)
+	copy(sa.pendingBlocks[idx+1:], sa.pendingBlocks[idx:])
+	sa.pendingBlocks[idx] = pending
+}
+
+// notifyReadyBlocks should be called under mutPending, so that blocks are forwarded in order
+func (sa *shardsAggregator) notifyReadyBlocks() error {
+	defer sa.scheduleTimeout()
+
+	for len(sa.pendingBlocks) > 0 {
+		first := sa.pendingBlocks[0]
+		isReady := sa.wasRoundReachedByAllShards(first.round)
+		hasExpired := sa.maxWaitTime > 0 && time.Since(first.receivedAt) >= sa.maxWaitTime
+		if !isReady && !hasExpired {
+			return nil
+		}
+
+		if !isReady {
+			log.Warn("notifying block without waiting for the lagging shards",
+				"shard", first.shardID, "round", first.round, "max wait time", sa.maxWaitTime)
+		}
+
+		err := sa.notifyFirstPendingBlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pending *pendingBlock) addDeliveryHandler(deliveryHandler func()) {
+	if deliveryHandler != nil {
+		pending.deliveryHandlers = append(pending.deliveryHandlers, deliveryHandler)
+	}
+}
+
+// notifyFirstPendingBlock should be called under mutPending. The block is removed from the pending blocks only once
+// notified, so that a failed notification is retried, unless it can never be notified
+func (sa *shardsAggregator) notifyFirstPendingBlock() error {
+	first := sa.pendingBlocks[0]
+	log.Debug("notifying aggregated block", "shard", first.shardID, "round", first.round,
+		"hash", hex.EncodeToString(first.outportBlock.BlockData.HeaderHash))
+
+	err := sa.notifier.Notify(first.outportBlock)
+	if errors.Is(err, process.ErrInvalidOutportBlock) {
+		log.Error("dropped aggregated block which can not be notified", "shard", first.shardID, "round", first.round,
+			"hash", hex.EncodeToString(first.outportBlock.BlockData.HeaderHash), "error", err.Error())
+		sa.pendingBlocks = sa.pendingBlocks[1:]
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sa.pendingBlocks = sa.pendingBlocks[1:]
+	for _, deliveryHandler := range first.deliveryHandlers {
+		deliveryHandler()
+	}
+	if first.round < sa.lastNotifiedRound {
+		log.Warn("notified block out of order, its shard was lagging",
+			"shard", first.shardID, "round", first.round, "last notified round", sa.lastNotifiedRound)
+	} else {
+		sa.lastNotifiedRound = first.round
+	}
+
+	return nil
+}
+
+func (sa *shardsAggregator) wasRoundReachedByAllShards(round uint64) bool {
+	for _, shardID := range sa.shardIDs {
+		lastRound, found := sa.lastRounds[shardID]
+		if !found || lastRound < round {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scheduleTimeout should be called under mutPending. It schedules the forwarding of the first pending block once
+// its max wait time passes
+func (sa *shardsAggregator) scheduleTimeout() {
+	sa.stopTimer()
+	if sa.isClosed || sa.maxWaitTime == 0 || len(sa.pendingBlocks) == 0 {
+		return
+	}
+
+	remainingTime := sa.maxWaitTime - time.Since(sa.pendingBlocks[0].receivedAt)
+	sa.timer = time.AfterFunc(remainingTime, sa.notifyReadyBlocksOnTimeout)
+}
+
+func (sa *shardsAggregator) stopTimer() {
+	if sa.timer != nil {
+		sa.timer.Stop()
+		sa.timer = nil
+	}
+}
+
+func (sa *shardsAggregator) notifyReadyBlocksOnTimeout() {
+	sa.mutPending.Lock()
+	defer sa.mutPending.Unlock()
+
+	if sa.isClosed {
+		return
+	}
+
+	err := sa.notifyReadyBlocks()
+	if err != nil {
+		log.Error("could not notify aggregated blocks on timeout, will retry", "error", err)
+	}
+}
+
+// ComputeIncomingHeaderHash will compute the incoming header hash with the underlying notifier, if it computes them
+func (sa *shardsAggregator) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	hashComputer, ok := sa.notifier.(process.IncomingHeaderHashComputer)
+	if !ok {
+		return nil, errNotIncomingHeaderHashComputer
+	}
+
+	return hashComputer.ComputeIncomingHeaderHash(outportBlock)
+}
+
+// RegisterHandler will register the handler in the underlying notifier
+func (sa *shardsAggregator) RegisterHandler(handler process.IncomingHeaderSubscriber) error {
+	return sa.notifier.RegisterHandler(handler)
+}
+
+// RegisterHandlerWithPolicy will register the handler in the underlying notifier, if it supports notification policies
+func (sa *shardsAggregator) RegisterHandlerWithPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	registerer, ok := sa.notifier.(process.PolicyHandlerRegisterer)
+	if !ok {
+		return errNotPolicyHandlerRegisterer
+	}
+
+	return registerer.RegisterHandlerWithPolicy(handler, policy)
+}
+
+// NotifyHeartbeat will notify the heartbeat through the underlying notifier, if it notifies heartbeats
+func (sa *shardsAggregator) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	heartbeatNotifier, ok := sa.notifier.(process.HeartbeatNotifier)
+	if !ok {
+		return errNotHeartbeatNotifier
+	}
+
+	return heartbeatNotifier.NotifyHeartbeat(heartbeat)
+}
+
+// Close will stop the max wait time timer and notify all the pending blocks, without waiting for the lagging shards.
+// The pending blocks were already handed over by the indexers, so they would otherwise be lost on restart
+func (sa *shardsAggregator) Close() error {
+	sa.mutPending.Lock()
+	defer sa.mutPending.Unlock()
+
+	sa.isClosed = true
+	sa.stopTimer()
+
+	if len(sa.pendingBlocks) > 0 {
+		log.Info("notifying the pending aggregated blocks on close", "num blocks", len(sa.pendingBlocks))
+	}
+	for len(sa.pendingBlocks) > 0 {
+		err := sa.notifyFirstPendingBlock()
+		if err != nil {
+			log.Error("could not notify the pending aggregated blocks on close",
+				"num lost blocks", len(sa.pendingBlocks), "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sa *shardsAggregator) IsInterfaceNil() bool {
+	return sa == nil
+}
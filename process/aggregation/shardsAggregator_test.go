@@ -0,0 +1,413 @@
+package aggregation
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgs() ArgsShardsAggregator {
+	return ArgsShardsAggregator{
+		Notifier:   &testscommon.SovereignNotifierStub{},
+		Marshaller: &testscommon.MarshallerMock{},
+		ShardIDs:   []uint32{0, 1},
+	}
+}
+
+func createOutportBlock(shardID uint32, round uint64) *outport.OutportBlock {
+	headerV2 := &block.HeaderV2{
+		Header: &block.Header{ShardID: shardID, Round: round},
+	}
+	headerBytes, _ := (&testscommon.MarshallerMock{}).Marshal(headerV2)
+
+	return &outport.OutportBlock{
+		ShardID: shardID,
+		BlockData: &outport.BlockData{
+			ShardID:     shardID,
+			HeaderBytes: headerBytes,
+			HeaderType:  string(core.ShardHeaderV2),
+			HeaderHash:  []byte(fmt.Sprintf("hash-%d-%d", shardID, round)),
+		},
+	}
+}
+
+// createNotifierStub records the hashes of the notified blocks
+func createNotifierStub(mut *sync.Mutex, notified *[]string) *testscommon.SovereignNotifierStub {
+	return &testscommon.SovereignNotifierStub{
+		NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+			mut.Lock()
+			*notified = append(*notified, string(finalizedBlock.BlockData.HeaderHash))
+			mut.Unlock()
+			return nil
+		},
+	}
+}
+
+func TestNewShardsAggregator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		aggregator, err := NewShardsAggregator(createArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(aggregator))
+	})
+
+	t.Run("nil notifier, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Notifier = nil
+		aggregator, err := NewShardsAggregator(args)
+		require.Equal(t, errNilSovereignNotifier, err)
+		require.Nil(t, aggregator)
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Marshaller = nil
+		aggregator, err := NewShardsAggregator(args)
+		require.Equal(t, errNilMarshaller, err)
+		require.Nil(t, aggregator)
+	})
+
+	t.Run("no shards, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ShardIDs = nil
+		aggregator, err := NewShardsAggregator(args)
+		require.Equal(t, errNoShardsProvided, err)
+		require.Nil(t, aggregator)
+	})
+
+	t.Run("negative max wait time, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.MaxWaitTime = -time.Second
+		aggregator, err := NewShardsAggregator(args)
+		require.Equal(t, errInvalidMaxWaitTime, err)
+		require.Nil(t, aggregator)
+	})
+}
+
+func TestShardsAggregator_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should merge the shards streams by round", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		notified := make([]string, 0)
+		args := createArgs()
+		args.ShardIDs = []uint32{0, 1, 2}
+		args.Notifier = createNotifierStub(&mut, &notified)
+		aggregator, _ := NewShardsAggregator(args)
+
+		blocks := []*outport.OutportBlock{
+			createOutportBlock(1, 10),
+			createOutportBlock(1, 11),
+			createOutportBlock(0, 11),
+			createOutportBlock(2, 9),
+			createOutportBlock(2, 12),
+			createOutportBlock(0, 13),
+			createOutportBlock(1, 13),
+		}
+		for _, outportBlock := range blocks {
+			err := aggregator.Notify(outportBlock)
+			require.Nil(t, err)
+		}
+
+		require.Equal(t, []string{"hash-2-9", "hash-1-10", "hash-0-11", "hash-1-11", "hash-2-12"}, notified)
+	})
+
+	t.Run("lagging shard should be skipped after max wait time", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		notified := make([]string, 0)
+		args := createArgs()
+		args.MaxWaitTime = 50 * time.Millisecond
+		args.Notifier = createNotifierStub(&mut, &notified)
+		aggregator, _ := NewShardsAggregator(args)
+
+		err := aggregator.Notify(createOutportBlock(0, 10))
+		require.Nil(t, err)
+		err = aggregator.Notify(createOutportBlock(0, 11))
+		require.Nil(t, err)
+
+		mut.Lock()
+		require.Empty(t, notified)
+		mut.Unlock()
+
+		require.Eventually(t, func() bool {
+			mut.Lock()
+			defer mut.Unlock()
+
+			return len(notified) == 2
+		}, time.Second, 10*time.Millisecond)
+
+		// the lagging shard block is notified right away, out of order
+		err = aggregator.Notify(createOutportBlock(1, 9))
+		require.Nil(t, err)
+
+		mut.Lock()
+		require.Equal(t, []string{"hash-0-10", "hash-0-11", "hash-1-9"}, notified)
+		mut.Unlock()
+	})
+
+	t.Run("not aggregated shard, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		aggregator, _ := NewShardsAggregator(createArgs())
+		err := aggregator.Notify(createOutportBlock(2, 10))
+		require.ErrorIs(t, err, errUnknownShard)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
+	})
+
+	t.Run("invalid block, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		aggregator, _ := NewShardsAggregator(createArgs())
+		err := aggregator.Notify(nil)
+		require.ErrorIs(t, err, errNilBlockData)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
+
+		outportBlock := createOutportBlock(0, 10)
+		outportBlock.BlockData.HeaderType = string(core.ShardHeaderV1)
+		err = aggregator.Notify(outportBlock)
+		require.ErrorIs(t, err, errInvalidHeaderType)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
+	})
+
+	t.Run("delivery handlers should be called once the block is delivered", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		notified := make([]string, 0)
+		args := createArgs()
+		args.Notifier = createNotifierStub(&mut, &notified)
+		aggregator, _ := NewShardsAggregator(args)
+
+		delivered := make([]string, 0)
+		err := aggregator.NotifyWithDeliveryHandler(createOutportBlock(0, 10), func() {
+			delivered = append(delivered, "first")
+		})
+		require.Nil(t, err)
+
+		// the queued block re-sent by another observer keeps both delivery handlers
+		err = aggregator.NotifyWithDeliveryHandler(createOutportBlock(0, 10), func() {
+			delivered = append(delivered, "second")
+		})
+		require.Nil(t, err)
+		require.Empty(t, delivered)
+
+		err = aggregator.Notify(createOutportBlock(1, 10))
+		require.Nil(t, err)
+		require.Equal(t, []string{"first", "second"}, delivered)
+		mut.Lock()
+		require.Equal(t, []string{"hash-0-10", "hash-1-10"}, notified)
+		mut.Unlock()
+	})
+
+	t.Run("notify error should keep the block queued", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("notify error")
+		notifyErr := errNotify
+		args := createArgs()
+		args.ShardIDs = []uint32{0}
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				return notifyErr
+			},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		delivered := make([]string, 0)
+		err := aggregator.NotifyWithDeliveryHandler(createOutportBlock(0, 10), func() {
+			delivered = append(delivered, "hash-0-10")
+		})
+		require.Nil(t, err)
+		require.Empty(t, delivered)
+
+		notifyErr = nil
+		err = aggregator.Notify(createOutportBlock(0, 11))
+		require.Nil(t, err)
+		require.Equal(t, []string{"hash-0-10"}, delivered)
+	})
+
+	t.Run("block which can not be notified should be dropped", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]string, 0)
+		args := createArgs()
+		args.ShardIDs = []uint32{0}
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				if string(finalizedBlock.BlockData.HeaderHash) == "hash-0-10" {
+					return fmt.Errorf("%w: invalid header", process.ErrInvalidOutportBlock)
+				}
+
+				notified = append(notified, string(finalizedBlock.BlockData.HeaderHash))
+				return nil
+			},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		wasDelivered := false
+		err := aggregator.NotifyWithDeliveryHandler(createOutportBlock(0, 10), func() {
+			wasDelivered = true
+		})
+		require.Nil(t, err)
+		err = aggregator.Notify(createOutportBlock(0, 11))
+		require.Nil(t, err)
+		require.False(t, wasDelivered)
+		require.Equal(t, []string{"hash-0-11"}, notified)
+	})
+
+	t.Run("block should be kept until notified", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("notify error")
+		notifyErr := errNotify
+		notified := make([]string, 0)
+		args := createArgs()
+		args.ShardIDs = []uint32{0}
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				if notifyErr != nil {
+					return notifyErr
+				}
+
+				notified = append(notified, string(finalizedBlock.BlockData.HeaderHash))
+				return nil
+			},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		err := aggregator.Notify(createOutportBlock(0, 10))
+		require.Nil(t, err)
+		err = aggregator.Notify(createOutportBlock(0, 11))
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		// the block re-sent after the failed notification is not duplicated
+		notifyErr = nil
+		err = aggregator.Notify(createOutportBlock(0, 10))
+		require.Nil(t, err)
+		require.Equal(t, []string{"hash-0-10", "hash-0-11"}, notified)
+	})
+
+	t.Run("closed aggregator, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		aggregator, _ := NewShardsAggregator(createArgs())
+		_ = aggregator.Close()
+
+		err := aggregator.Notify(createOutportBlock(0, 10))
+		require.Equal(t, errAggregatorClosed, err)
+	})
+}
+
+func TestShardsAggregator_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should notify the pending blocks and stop the timer", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		notified := make([]string, 0)
+		args := createArgs()
+		args.MaxWaitTime = 50 * time.Millisecond
+		args.Notifier = createNotifierStub(&mut, &notified)
+		aggregator, _ := NewShardsAggregator(args)
+
+		err := aggregator.Notify(createOutportBlock(0, 11))
+		require.Nil(t, err)
+		err = aggregator.Notify(createOutportBlock(0, 10))
+		require.Nil(t, err)
+
+		err = aggregator.Close()
+		require.Nil(t, err)
+
+		time.Sleep(2 * args.MaxWaitTime)
+		mut.Lock()
+		require.Equal(t, []string{"hash-0-10", "hash-0-11"}, notified)
+		mut.Unlock()
+	})
+
+	t.Run("notify error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("notify error")
+		args := createArgs()
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				return errNotify
+			},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		err := aggregator.Notify(createOutportBlock(0, 10))
+		require.Nil(t, err)
+
+		err = aggregator.Close()
+		require.Equal(t, errNotify, err)
+	})
+}
+
+func TestShardsAggregator_ForwardedMethods(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should forward to the notifier", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]string, 0)
+		args := createArgs()
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			ComputeIncomingHeaderHashCalled: func(outportBlock *outport.OutportBlock) ([]byte, error) {
+				calls = append(calls, "compute")
+				return nil, nil
+			},
+			RegisterHandlerWithPolicyCalled: func(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+				calls = append(calls, "register with policy")
+				return nil
+			},
+			NotifyHeartbeatCalled: func(heartbeat *process.Heartbeat) error {
+				calls = append(calls, "heartbeat")
+				return nil
+			},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		_, err := aggregator.ComputeIncomingHeaderHash(&outport.OutportBlock{})
+		require.Nil(t, err)
+		require.Nil(t, aggregator.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{}))
+		require.Nil(t, aggregator.NotifyHeartbeat(&process.Heartbeat{}))
+		require.Equal(t, []string{"compute", "register with policy", "heartbeat"}, calls)
+	})
+
+	t.Run("notifier without optional methods, should return errors", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.Notifier = struct {
+			process.SovereignNotifier
+		}{
+			SovereignNotifier: &testscommon.SovereignNotifierStub{},
+		}
+		aggregator, _ := NewShardsAggregator(args)
+
+		_, err := aggregator.ComputeIncomingHeaderHash(&outport.OutportBlock{})
+		require.Equal(t, errNotIncomingHeaderHashComputer, err)
+		err = aggregator.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{})
+		require.Equal(t, errNotPolicyHandlerRegisterer, err)
+		err = aggregator.NotifyHeartbeat(&process.Heartbeat{})
+		require.Equal(t, errNotHeartbeatNotifier, err)
+	})
+}
@@ -0,0 +1,195 @@
+package dryrun
+
+import (
+	"encoding/hex"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+)
+
+var log = logger.GetOrCreate("notifier-dry-run-process")
+
+// ArgsDryRunNotifier is a struct placeholder for args needed to create a dry run notifier. The baseline notifier is
+// optional and should be created with the currently running config
+type ArgsDryRunNotifier struct {
+	Notifier                process.SovereignNotifier
+	BaselineNotifier        process.SovereignNotifier
+	IncomingHeaderFormatter IncomingHeaderFormatter
+}
+
+type dryRunNotifier struct {
+	notifier         process.SovereignNotifier
+	baselineNotifier process.SovereignNotifier
+	headerFormatter  IncomingHeaderFormatter
+	recorder         *headersRecorder
+	baselineRecorder *headersRecorder
+}
+
+// NewDryRunNotifier creates a notifier which runs the provided notifier without delivering the incoming headers to
+// any subscriber. Each incoming header and its events are logged in human-readable form instead. If a baseline notifier
+// is provided, the events are compared with the ones notified by the baseline for the same block
+func NewDryRunNotifier(args ArgsDryRunNotifier) (*dryRunNotifier, error) {
+	if check.IfNil(args.Notifier) {
+		return nil, errNilSovereignNotifier
+	}
+	if check.IfNil(args.IncomingHeaderFormatter) {
+		return nil, errNilIncomingHeaderFormatter
+	}
+
+	drn := &dryRunNotifier{
+		notifier:        args.Notifier,
+		headerFormatter: args.IncomingHeaderFormatter,
+		recorder:        newHeadersRecorder(),
+	}
+	err := drn.notifier.RegisterHandler(drn.recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	if check.IfNil(args.BaselineNotifier) {
+		return drn, nil
+	}
+
+	drn.baselineNotifier = args.BaselineNotifier
+	drn.baselineRecorder = newHeadersRecorder()
+	err = drn.baselineNotifier.RegisterHandler(drn.baselineRecorder)
+	if err != nil {
+		return nil, err
+	}
+
+	return drn, nil
+}
+
+// Notify runs the notifier for the finalized block and logs the incoming header which would be delivered. Failures of
+// the baseline notifier are only logged
+func (drn *dryRunNotifier) Notify(finalizedBlock *outport.OutportBlock) error {
+	err := drn.notifier.Notify(finalizedBlock)
+	if err != nil {
+		return err
+	}
+
+	events := drn.logHeaders(drn.recorder.popHeaders())
+	if check.IfNil(drn.baselineNotifier) {
+		return nil
+	}
+
+	err = drn.baselineNotifier.Notify(finalizedBlock)
+	if err != nil {
+		log.Warn("dry run: baseline notifier failed", "header hash", hex.EncodeToString(finalizedBlock.BlockData.HeaderHash), "error", err)
+		return nil
+	}
+
+	baselineEvents := getEvents(drn.baselineRecorder.popHeaders())
+	drn.logDiff(hex.EncodeToString(finalizedBlock.BlockData.HeaderHash), events, baselineEvents)
+
+	return nil
+}
+
+// logHeaders logs the recorded headers and returns their events
+func (drn *dryRunNotifier) logHeaders(headers []*recordedHeader) []*transaction.Event {
+	for _, recorded := range headers {
+		formattedHeader, err := drn.headerFormatter.FormatIncomingHeader(recorded.headerHash, recorded.header)
+		if err != nil {
+			log.Warn("dry run: could not format incoming header", "incoming header hash", hex.EncodeToString(recorded.headerHash), "error", err)
+			continue
+		}
+
+		log.Info("dry run: would notify incoming header",
+			"incoming header hash", formattedHeader.IncomingHeaderHash,
+			"header hash", formattedHeader.HeaderHash,
+			"shard", formattedHeader.ShardID,
+			"nonce", formattedHeader.Nonce,
+			"round", formattedHeader.Round,
+			"num events", len(formattedHeader.Events))
+
+		for _, event := range formattedHeader.Events {
+			formatted := newFormattedEvent(event)
+			log.Info("dry run: incoming event",
+				"identifier", formatted.identifier,
+				"address", formatted.address,
+				"topics", formatted.topics,
+				"data", formatted.data)
+		}
+	}
+
+	return getEvents(headers)
+}
+
+func getEvents(headers []*recordedHeader) []*transaction.Event {
+	events := make([]*transaction.Event, 0)
+	for _, recorded := range headers {
+		for _, eventHandler := range recorded.header.GetIncomingEventHandlers() {
+			events = append(events, &transaction.Event{
+				Address:    eventHandler.GetAddress(),
+				Identifier: eventHandler.GetIdentifier(),
+				Topics:     eventHandler.GetTopics(),
+				Data:       eventHandler.GetData(),
+			})
+		}
+	}
+
+	return events
+}
+
+func (drn *dryRunNotifier) logDiff(headerHash string, events []*transaction.Event, baselineEvents []*transaction.Event) {
+	addedEvents, removedEvents := notifier.DiffEvents(events, baselineEvents)
+	if len(addedEvents) == 0 && len(removedEvents) == 0 {
+		log.Debug("dry run: same events as the baseline", "header hash", headerHash, "num events", len(events))
+		return
+	}
+
+	log.Info("dry run: events differ from the baseline",
+		"header hash", headerHash,
+		"num added events", len(addedEvents),
+		"num removed events", len(removedEvents))
+	for _, event := range addedEvents {
+		formatted := newFormattedEvent(drn.headerFormatter.FormatEvent(event))
+		log.Info("dry run: event notified only with the new config",
+			"identifier", formatted.identifier, "address", formatted.address, "topics", formatted.topics, "data", formatted.data)
+	}
+	for _, event := range removedEvents {
+		formatted := newFormattedEvent(drn.headerFormatter.FormatEvent(event))
+		log.Info("dry run: event notified only with the baseline config",
+			"identifier", formatted.identifier, "address", formatted.address, "topics", formatted.topics, "data", formatted.data)
+	}
+}
+
+// ComputeIncomingHeaderHash computes the incoming header hash with the notifier, if it computes them
+func (drn *dryRunNotifier) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	hashComputer, ok := drn.notifier.(process.IncomingHeaderHashComputer)
+	if !ok {
+		return nil, errNotIncomingHeaderHashComputer
+	}
+
+	return hashComputer.ComputeIncomingHeaderHash(outportBlock)
+}
+
+// RegisterHandler returns an error, since no incoming header is delivered in dry run mode
+func (drn *dryRunNotifier) RegisterHandler(_ process.IncomingHeaderSubscriber) error {
+	return errHandlersNotSupported
+}
+
+// RegisterHandlerWithPolicy returns an error, since no incoming header is delivered in dry run mode
+func (drn *dryRunNotifier) RegisterHandlerWithPolicy(_ process.IncomingHeaderSubscriber, _ process.NotificationPolicy) error {
+	return errHandlersNotSupported
+}
+
+// NotifyHeartbeat forwards the heartbeat to the notifier, if it notifies heartbeats
+func (drn *dryRunNotifier) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	heartbeatNotifier, ok := drn.notifier.(process.HeartbeatNotifier)
+	if !ok {
+		return errNotHeartbeatNotifier
+	}
+
+	return heartbeatNotifier.NotifyHeartbeat(heartbeat)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (drn *dryRunNotifier) IsInterfaceNil() bool {
+	return drn == nil
+}
@@ -0,0 +1,185 @@
+package dryrun
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createNotifierStub(events []*transaction.Event, notifyErr error) *testscommon.SovereignNotifierStub {
+	var handler process.IncomingHeaderSubscriber
+	return &testscommon.SovereignNotifierStub{
+		RegisterHandlerCalled: func(subscriber process.IncomingHeaderSubscriber) error {
+			handler = subscriber
+			return nil
+		},
+		NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+			if notifyErr != nil {
+				return notifyErr
+			}
+
+			return handler.AddHeader([]byte("incomingHeaderHash"), &sovereign.IncomingHeader{
+				Header:         &block.HeaderV2{Header: &block.Header{Nonce: 4, Round: 5}},
+				IncomingEvents: events,
+			})
+		},
+	}
+}
+
+func createArgs() ArgsDryRunNotifier {
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	headerFormatter, _ := formatter.NewIncomingHeaderFormatter(addressPubkeyConverter)
+
+	return ArgsDryRunNotifier{
+		Notifier:                createNotifierStub(nil, nil),
+		IncomingHeaderFormatter: headerFormatter,
+	}
+}
+
+func createFinalizedBlock() *outport.OutportBlock {
+	return &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: []byte("headerHash")}}
+}
+
+func TestNewDryRunNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		drn, err := NewDryRunNotifier(createArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(drn))
+		require.Nil(t, drn.baselineRecorder)
+	})
+
+	t.Run("with baseline should register both recorders", func(t *testing.T) {
+		args := createArgs()
+		args.BaselineNotifier = createNotifierStub(nil, nil)
+		drn, err := NewDryRunNotifier(args)
+		require.Nil(t, err)
+		require.NotNil(t, drn.baselineRecorder)
+	})
+
+	t.Run("nil notifier, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.Notifier = nil
+		drn, err := NewDryRunNotifier(args)
+		require.Equal(t, errNilSovereignNotifier, err)
+		require.Nil(t, drn)
+	})
+
+	t.Run("nil incoming header formatter, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.IncomingHeaderFormatter = nil
+		drn, err := NewDryRunNotifier(args)
+		require.Equal(t, errNilIncomingHeaderFormatter, err)
+		require.Nil(t, drn)
+	})
+
+	t.Run("register error, should return error", func(t *testing.T) {
+		errRegister := errors.New("register error")
+		args := createArgs()
+		args.BaselineNotifier = &testscommon.SovereignNotifierStub{
+			RegisterHandlerCalled: func(handler process.IncomingHeaderSubscriber) error {
+				return errRegister
+			},
+		}
+		drn, err := NewDryRunNotifier(args)
+		require.Equal(t, errRegister, err)
+		require.Nil(t, drn)
+	})
+}
+
+func TestDryRunNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	address := make([]byte, 32)
+	depositEvent := &transaction.Event{Address: address, Identifier: []byte("deposit"), Topics: [][]byte{[]byte("token")}}
+	otherEvent := &transaction.Event{Address: address, Identifier: []byte("execute")}
+
+	t.Run("notifier error should be returned", func(t *testing.T) {
+		errNotify := errors.New("notify error")
+		args := createArgs()
+		args.Notifier = createNotifierStub(nil, errNotify)
+		drn, _ := NewDryRunNotifier(args)
+
+		err := drn.Notify(createFinalizedBlock())
+		require.Equal(t, errNotify, err)
+	})
+
+	t.Run("should record and format the notified headers", func(t *testing.T) {
+		args := createArgs()
+		args.Notifier = createNotifierStub([]*transaction.Event{depositEvent}, nil)
+		drn, _ := NewDryRunNotifier(args)
+
+		err := drn.Notify(createFinalizedBlock())
+		require.Nil(t, err)
+		require.Empty(t, drn.recorder.popHeaders())
+	})
+
+	t.Run("baseline error should only be logged", func(t *testing.T) {
+		args := createArgs()
+		args.BaselineNotifier = createNotifierStub(nil, errors.New("baseline error"))
+		drn, _ := NewDryRunNotifier(args)
+
+		err := drn.Notify(createFinalizedBlock())
+		require.Nil(t, err)
+	})
+
+	t.Run("with baseline should record both notifiers headers", func(t *testing.T) {
+		args := createArgs()
+		args.Notifier = createNotifierStub([]*transaction.Event{depositEvent, otherEvent}, nil)
+		args.BaselineNotifier = createNotifierStub([]*transaction.Event{depositEvent}, nil)
+		drn, _ := NewDryRunNotifier(args)
+
+		err := drn.Notify(createFinalizedBlock())
+		require.Nil(t, err)
+		require.Empty(t, drn.recorder.popHeaders())
+		require.Empty(t, drn.baselineRecorder.popHeaders())
+	})
+
+	t.Run("registering handlers should fail", func(t *testing.T) {
+		args := createArgs()
+		args.Notifier = createNotifierStub([]*transaction.Event{depositEvent}, nil)
+		drn, _ := NewDryRunNotifier(args)
+
+		handler := &testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				require.Fail(t, "should not deliver headers in dry run mode")
+				return nil
+			},
+		}
+		require.Equal(t, errHandlersNotSupported, drn.RegisterHandler(handler))
+		require.Equal(t, errHandlersNotSupported, drn.RegisterHandlerWithPolicy(handler, process.NotificationPolicy{Mode: process.NotifyAllHeaders}))
+
+		err := drn.Notify(createFinalizedBlock())
+		require.Nil(t, err)
+	})
+
+	t.Run("events should be formatted in human-readable form", func(t *testing.T) {
+		event := &transaction.Event{
+			Address:    address,
+			Identifier: []byte("deposit"),
+			Topics:     [][]byte{address, []byte("WEGLD-bd4d79"), {0x0d, 0xe0, 0xb6}},
+			Data:       nil,
+		}
+		encodedAddress, _ := addressPubkeyConverter.Encode(address)
+		drn, _ := NewDryRunNotifier(createArgs())
+
+		require.Equal(t, &formattedEvent{
+			identifier: "deposit",
+			address:    encodedAddress,
+			topics:     "[" + encodedAddress + ", WEGLD-bd4d79, 909494]",
+			data:       "",
+		}, newFormattedEvent(drn.headerFormatter.FormatEvent(event)))
+	})
+}
@@ -0,0 +1,15 @@
+package dryrun
+
+import "errors"
+
+var errNilSovereignNotifier = errors.New("nil sovereign notifier provided")
+
+var errNilIncomingHeaderFormatter = errors.New("nil incoming header formatter provided")
+
+var errNilIncomingHeader = errors.New("nil incoming header provided")
+
+var errNotIncomingHeaderHashComputer = errors.New("sovereign notifier does not compute incoming header hashes")
+
+var errNotHeartbeatNotifier = errors.New("sovereign notifier does not notify heartbeats")
+
+var errHandlersNotSupported = errors.New("handlers are not supported in dry run mode, since incoming headers are not delivered")
@@ -0,0 +1,34 @@
+package dryrun
+
+import (
+	"strings"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+)
+
+// formattedEvent holds the human-readable fields of an incoming event, as logged in dry run mode
+type formattedEvent struct {
+	identifier string
+	address    string
+	topics     string
+	data       string
+}
+
+func newFormattedEvent(event *formatter.Event) *formattedEvent {
+	topics := make([]string, len(event.Topics))
+	for idx, topic := range event.Topics {
+		topics[idx] = topic.String()
+	}
+
+	data := ""
+	if len(event.Data.Hex) != 0 {
+		data = event.Data.String()
+	}
+
+	return &formattedEvent{
+		identifier: event.Identifier,
+		address:    event.Address,
+		topics:     "[" + strings.Join(topics, ", ") + "]",
+		data:       data,
+	}
+}
@@ -0,0 +1,54 @@
+package dryrun
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+)
+
+type recordedHeader struct {
+	headerHash []byte
+	header     sovereign.IncomingHeaderHandler
+}
+
+// headersRecorder is the subscriber replacing the delivery of incoming headers. It keeps the headers notified since the
+// last pop, since the notifier calls its subscribers synchronously
+type headersRecorder struct {
+	mutHeaders sync.Mutex
+	headers    []*recordedHeader
+}
+
+func newHeadersRecorder() *headersRecorder {
+	return &headersRecorder{}
+}
+
+// AddHeader records the notified incoming header
+func (hr *headersRecorder) AddHeader(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+	if header == nil || header.IsInterfaceNil() {
+		return errNilIncomingHeader
+	}
+
+	hr.mutHeaders.Lock()
+	hr.headers = append(hr.headers, &recordedHeader{
+		headerHash: headerHash,
+		header:     header,
+	})
+	hr.mutHeaders.Unlock()
+
+	return nil
+}
+
+func (hr *headersRecorder) popHeaders() []*recordedHeader {
+	hr.mutHeaders.Lock()
+	defer hr.mutHeaders.Unlock()
+
+	headers := hr.headers
+	hr.headers = nil
+
+	return headers
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (hr *headersRecorder) IsInterfaceNil() bool {
+	return hr == nil
+}
@@ -0,0 +1,15 @@
+package dryrun
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+)
+
+// IncomingHeaderFormatter should render incoming headers and events in human-readable form
+type IncomingHeaderFormatter interface {
+	FormatIncomingHeader(incomingHeaderHash []byte, incomingHeader sovereign.IncomingHeaderHandler) (*formatter.IncomingHeader, error)
+	FormatEvent(event data.EventHandler) *formatter.Event
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,6 @@
+package process
+
+import "errors"
+
+// ErrInvalidOutportBlock signals an outport block which can never be notified, so it should not be retried
+var ErrInvalidOutportBlock = errors.New("invalid outport block")
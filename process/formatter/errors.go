@@ -0,0 +1,13 @@
+package formatter
+
+import "errors"
+
+var errNilAddressPubkeyConverter = errors.New("nil address pubkey converter provided")
+
+var errNilMarshaller = errors.New("nil marshaller provided")
+
+var errNilHasher = errors.New("nil hasher provided")
+
+var errNilIncomingHeader = errors.New("nil incoming header provided")
+
+var errNilHeader = errors.New("nil header provided")
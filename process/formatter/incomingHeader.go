@@ -0,0 +1,50 @@
+package formatter
+
+// IncomingHeader is the human-readable representation of an incoming header. Its fields are always rendered in the
+// same order, so that the JSON output is stable
+type IncomingHeader struct {
+	IncomingHeaderHash string   `json:"incomingHeaderHash"`
+	HeaderHash         string   `json:"headerHash"`
+	ShardID            uint32   `json:"shardId"`
+	Nonce              uint64   `json:"nonce"`
+	Round              uint64   `json:"round"`
+	Epoch              uint32   `json:"epoch"`
+	TimeStamp          uint64   `json:"timestamp"`
+	PrevHash           string   `json:"prevHash"`
+	RootHash           string   `json:"rootHash"`
+	PrevRandSeed       string   `json:"prevRandSeed"`
+	RandSeed           string   `json:"randSeed"`
+	Events             []*Event `json:"events"`
+}
+
+// Event is the human-readable representation of an incoming event
+type Event struct {
+	Identifier string   `json:"identifier"`
+	Address    string   `json:"address"`
+	Topics     []*Value `json:"topics"`
+	Data       *Value   `json:"data"`
+}
+
+// Value holds the hex representation of an event topic or data, together with its best effort decodings: as an
+// address if it has the length of an address, as text if it is printable UTF-8, and as an unsigned big endian number
+// if it is not longer than an address
+type Value struct {
+	Hex     string `json:"hex"`
+	Address string `json:"address,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Number  string `json:"number,omitempty"`
+}
+
+// String returns the most specific decoding of the value, or its hex representation if it could not be decoded
+func (v *Value) String() string {
+	switch {
+	case len(v.Address) != 0:
+		return v.Address
+	case len(v.Text) != 0:
+		return v.Text
+	case len(v.Number) != 0:
+		return v.Number
+	default:
+		return "0x" + v.Hex
+	}
+}
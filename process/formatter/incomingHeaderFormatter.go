@@ -0,0 +1,175 @@
+package formatter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/hashing/blake2b"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+)
+
+// ArgsIncomingHeaderFormatter is a struct placeholder for args needed to create an incoming header formatter. The
+// marshaller and the hasher should be the ones used by the main chain, to compute the main chain header hash
+type ArgsIncomingHeaderFormatter struct {
+	AddressPubkeyConverter core.PubkeyConverter
+	Marshaller             marshal.Marshalizer
+	Hasher                 hashing.Hasher
+}
+
+type incomingHeaderFormatter struct {
+	addressPubkeyConverter core.PubkeyConverter
+	marshaller             marshal.Marshalizer
+	hasher                 hashing.Hasher
+}
+
+// NewIncomingHeaderFormatter creates a formatter which renders incoming headers in human-readable form, with addresses
+// encoded by the provided converter and the main chain header hash computed with the default marshaller and hasher
+func NewIncomingHeaderFormatter(addressPubkeyConverter core.PubkeyConverter) (*incomingHeaderFormatter, error) {
+	return NewIncomingHeaderFormatterWithArgs(ArgsIncomingHeaderFormatter{
+		AddressPubkeyConverter: addressPubkeyConverter,
+		Marshaller:             &marshal.GogoProtoMarshalizer{},
+		Hasher:                 blake2b.NewBlake2b(),
+	})
+}
+
+// NewIncomingHeaderFormatterWithArgs creates a formatter which renders incoming headers in human-readable form, with
+// addresses encoded by the provided converter and the main chain header hash computed with the provided marshaller
+// and hasher
+func NewIncomingHeaderFormatterWithArgs(args ArgsIncomingHeaderFormatter) (*incomingHeaderFormatter, error) {
+	if check.IfNil(args.AddressPubkeyConverter) {
+		return nil, errNilAddressPubkeyConverter
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if check.IfNil(args.Hasher) {
+		return nil, errNilHasher
+	}
+
+	return &incomingHeaderFormatter{
+		addressPubkeyConverter: args.AddressPubkeyConverter,
+		marshaller:             args.Marshaller,
+		hasher:                 args.Hasher,
+	}, nil
+}
+
+// FormatIncomingHeader returns the human-readable representation of the incoming header
+func (ihf *incomingHeaderFormatter) FormatIncomingHeader(incomingHeaderHash []byte, incomingHeader sovereign.IncomingHeaderHandler) (*IncomingHeader, error) {
+	if check.IfNil(incomingHeader) {
+		return nil, errNilIncomingHeader
+	}
+	header := incomingHeader.GetHeaderHandler()
+	if check.IfNil(header) {
+		return nil, errNilHeader
+	}
+
+	headerHash, err := core.CalculateHash(ihf.marshaller, ihf.hasher, header)
+	if err != nil {
+		return nil, err
+	}
+
+	eventHandlers := incomingHeader.GetIncomingEventHandlers()
+	events := make([]*Event, 0, len(eventHandlers))
+	for _, eventHandler := range eventHandlers {
+		events = append(events, ihf.FormatEvent(eventHandler))
+	}
+
+	return &IncomingHeader{
+		IncomingHeaderHash: hex.EncodeToString(incomingHeaderHash),
+		HeaderHash:         hex.EncodeToString(headerHash),
+		ShardID:            header.GetShardID(),
+		Nonce:              header.GetNonce(),
+		Round:              header.GetRound(),
+		Epoch:              header.GetEpoch(),
+		TimeStamp:          header.GetTimeStamp(),
+		PrevHash:           hex.EncodeToString(header.GetPrevHash()),
+		RootHash:           hex.EncodeToString(header.GetRootHash()),
+		PrevRandSeed:       hex.EncodeToString(header.GetPrevRandSeed()),
+		RandSeed:           hex.EncodeToString(header.GetRandSeed()),
+		Events:             events,
+	}, nil
+}
+
+// FormatIncomingHeaderJSON returns the indented JSON of the human-readable representation of the incoming header
+func (ihf *incomingHeaderFormatter) FormatIncomingHeaderJSON(incomingHeaderHash []byte, incomingHeader sovereign.IncomingHeaderHandler) ([]byte, error) {
+	formattedHeader, err := ihf.FormatIncomingHeader(incomingHeaderHash, incomingHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(formattedHeader, "", "  ")
+}
+
+// FormatEvent returns the human-readable representation of the event
+func (ihf *incomingHeaderFormatter) FormatEvent(event data.EventHandler) *Event {
+	topics := make([]*Value, 0, len(event.GetTopics()))
+	for _, topic := range event.GetTopics() {
+		topics = append(topics, ihf.formatValue(topic))
+	}
+
+	return &Event{
+		Identifier: string(event.GetIdentifier()),
+		Address:    ihf.encodeAddress(event.GetAddress()),
+		Topics:     topics,
+		Data:       ihf.formatValue(event.GetData()),
+	}
+}
+
+func (ihf *incomingHeaderFormatter) formatValue(value []byte) *Value {
+	formattedValue := &Value{
+		Hex: hex.EncodeToString(value),
+	}
+	if len(value) == 0 {
+		return formattedValue
+	}
+
+	addressLen := ihf.addressPubkeyConverter.Len()
+	if len(value) == addressLen {
+		formattedValue.Address = ihf.encodeAddress(value)
+	}
+	if isPrintableUTF8(value) {
+		formattedValue.Text = string(value)
+	}
+	if len(value) <= addressLen {
+		formattedValue.Number = big.NewInt(0).SetBytes(value).String()
+	}
+
+	return formattedValue
+}
+
+// encodeAddress returns the hex address if it could not be encoded, so that invalid addresses are still rendered
+func (ihf *incomingHeaderFormatter) encodeAddress(address []byte) string {
+	encodedAddress, err := ihf.addressPubkeyConverter.Encode(address)
+	if err != nil {
+		return hex.EncodeToString(address)
+	}
+
+	return encodedAddress
+}
+
+func isPrintableUTF8(value []byte) bool {
+	if !utf8.Valid(value) {
+		return false
+	}
+
+	for _, r := range string(value) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (ihf *incomingHeaderFormatter) IsInterfaceNil() bool {
+	return ihf == nil
+}
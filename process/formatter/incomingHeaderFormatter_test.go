@@ -0,0 +1,236 @@
+package formatter
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/hashing/blake2b"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+const aliceAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+
+func createArgs() ArgsIncomingHeaderFormatter {
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	return ArgsIncomingHeaderFormatter{
+		AddressPubkeyConverter: addressPubkeyConverter,
+		Marshaller:             &marshal.GogoProtoMarshalizer{},
+		Hasher:                 blake2b.NewBlake2b(),
+	}
+}
+
+func createFormatter(t *testing.T) *incomingHeaderFormatter {
+	ihf, err := NewIncomingHeaderFormatterWithArgs(createArgs())
+	require.Nil(t, err)
+
+	return ihf
+}
+
+func computeHeaderHash(t *testing.T, incomingHeader *sovereign.IncomingHeader) string {
+	args := createArgs()
+	headerHash, err := core.CalculateHash(args.Marshaller, args.Hasher, incomingHeader.Header)
+	require.Nil(t, err)
+
+	return hex.EncodeToString(headerHash)
+}
+
+func createIncomingHeader(t *testing.T) *sovereign.IncomingHeader {
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	alice, err := addressPubkeyConverter.Decode(aliceAddress)
+	require.Nil(t, err)
+
+	return &sovereign.IncomingHeader{
+		Header: &block.HeaderV2{
+			Header: &block.Header{
+				ShardID:      1,
+				Nonce:        4,
+				Round:        5,
+				Epoch:        2,
+				TimeStamp:    1700000000,
+				PrevHash:     []byte{0xaa, 0xbb},
+				RootHash:     []byte{0xcc},
+				PrevRandSeed: []byte{0x01},
+				RandSeed:     []byte{0x02},
+			},
+		},
+		IncomingEvents: []*transaction.Event{
+			{
+				Address:    alice,
+				Identifier: []byte("deposit"),
+				Topics:     [][]byte{alice, []byte("WEGLD-bd4d79"), {0x0d, 0xe0, 0xb6, 0xb3, 0xa7, 0x64, 0x00, 0x00}, {}},
+				Data:       []byte("data"),
+			},
+		},
+	}
+}
+
+func TestNewIncomingHeaderFormatterWithArgs(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	args.AddressPubkeyConverter = nil
+	ihf, err := NewIncomingHeaderFormatterWithArgs(args)
+	require.Equal(t, errNilAddressPubkeyConverter, err)
+	require.Nil(t, ihf)
+
+	args = createArgs()
+	args.Marshaller = nil
+	ihf, err = NewIncomingHeaderFormatterWithArgs(args)
+	require.Equal(t, errNilMarshaller, err)
+	require.Nil(t, ihf)
+
+	args = createArgs()
+	args.Hasher = nil
+	ihf, err = NewIncomingHeaderFormatterWithArgs(args)
+	require.Equal(t, errNilHasher, err)
+	require.Nil(t, ihf)
+
+	ihf = createFormatter(t)
+	require.False(t, check.IfNil(ihf))
+
+	ihf, err = NewIncomingHeaderFormatter(nil)
+	require.Equal(t, errNilAddressPubkeyConverter, err)
+	require.Nil(t, ihf)
+
+	ihf, err = NewIncomingHeaderFormatter(createArgs().AddressPubkeyConverter)
+	require.Nil(t, err)
+	require.False(t, check.IfNil(ihf))
+}
+
+func TestIncomingHeaderFormatter_FormatIncomingHeader(t *testing.T) {
+	t.Parallel()
+
+	ihf := createFormatter(t)
+
+	t.Run("nil incoming header or header should return error", func(t *testing.T) {
+		t.Parallel()
+
+		formattedHeader, err := ihf.FormatIncomingHeader(nil, nil)
+		require.Equal(t, errNilIncomingHeader, err)
+		require.Nil(t, formattedHeader)
+
+		formattedHeader, err = ihf.FormatIncomingHeader(nil, &sovereign.IncomingHeader{})
+		require.Equal(t, errNilHeader, err)
+		require.Nil(t, formattedHeader)
+	})
+
+	t.Run("should decode the header and events fields", func(t *testing.T) {
+		t.Parallel()
+
+		aliceHex := "0139472eff6886771a982f3083da5d421f24c29181e63888228dc81ca60d69e1"
+		aliceNumber := "553514422321436685415476229189471088538459929158075553757047496270188210657"
+		incomingHeader := createIncomingHeader(t)
+		formattedHeader, err := ihf.FormatIncomingHeader([]byte{0x12, 0x34}, incomingHeader)
+		require.Nil(t, err)
+		require.Equal(t, &IncomingHeader{
+			IncomingHeaderHash: "1234",
+			HeaderHash:         computeHeaderHash(t, incomingHeader),
+			ShardID:            1,
+			Nonce:              4,
+			Round:              5,
+			Epoch:              2,
+			TimeStamp:          1700000000,
+			PrevHash:           "aabb",
+			RootHash:           "cc",
+			PrevRandSeed:       "01",
+			RandSeed:           "02",
+			Events: []*Event{
+				{
+					Identifier: "deposit",
+					Address:    aliceAddress,
+					Topics: []*Value{
+						{Hex: aliceHex, Address: aliceAddress, Number: aliceNumber},
+						{Hex: hex.EncodeToString([]byte("WEGLD-bd4d79")), Text: "WEGLD-bd4d79", Number: "27008948430895954689893087033"},
+						{Hex: "0de0b6b3a7640000", Number: "1000000000000000000"},
+						{Hex: ""},
+					},
+					Data: &Value{Hex: hex.EncodeToString([]byte("data")), Text: "data", Number: "1684108385"},
+				},
+			},
+		}, formattedHeader)
+
+		require.Equal(t, aliceAddress, formattedHeader.Events[0].Topics[0].String())
+		require.Equal(t, "WEGLD-bd4d79", formattedHeader.Events[0].Topics[1].String())
+		require.Equal(t, "1000000000000000000", formattedHeader.Events[0].Topics[2].String())
+		require.Equal(t, "0x", formattedHeader.Events[0].Topics[3].String())
+	})
+
+	t.Run("header marshal error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		errMarshal := errors.New("marshal error")
+		args := createArgs()
+		args.Marshaller = &testscommon.MarshallerStub{
+			MarshalCalled: func(obj interface{}) ([]byte, error) {
+				return nil, errMarshal
+			},
+		}
+		ihfWithMarshalError, _ := NewIncomingHeaderFormatterWithArgs(args)
+
+		formattedHeader, err := ihfWithMarshalError.FormatIncomingHeader(nil, createIncomingHeader(t))
+		require.Equal(t, errMarshal, err)
+		require.Nil(t, formattedHeader)
+	})
+
+	t.Run("invalid address should be rendered as hex", func(t *testing.T) {
+		t.Parallel()
+
+		formattedEvent := ihf.FormatEvent(&transaction.Event{Address: []byte{0x01, 0x02}})
+		require.Equal(t, "0102", formattedEvent.Address)
+		require.Empty(t, formattedEvent.Topics)
+	})
+}
+
+func TestIncomingHeaderFormatter_FormatIncomingHeaderJSON(t *testing.T) {
+	t.Parallel()
+
+	ihf := createFormatter(t)
+
+	jsonHeader, err := ihf.FormatIncomingHeaderJSON(nil, nil)
+	require.Equal(t, errNilIncomingHeader, err)
+	require.Nil(t, jsonHeader)
+
+	incomingHeader := createIncomingHeader(t)
+	incomingHeader.IncomingEvents[0].Topics = [][]byte{{0x01, 0xff}}
+	jsonHeader, err = ihf.FormatIncomingHeaderJSON([]byte{0x12}, incomingHeader)
+	require.Nil(t, err)
+	require.Equal(t, `{
+  "incomingHeaderHash": "12",
+  "headerHash": "`+computeHeaderHash(t, incomingHeader)+`",
+  "shardId": 1,
+  "nonce": 4,
+  "round": 5,
+  "epoch": 2,
+  "timestamp": 1700000000,
+  "prevHash": "aabb",
+  "rootHash": "cc",
+  "prevRandSeed": "01",
+  "randSeed": "02",
+  "events": [
+    {
+      "identifier": "deposit",
+      "address": "`+aliceAddress+`",
+      "topics": [
+        {
+          "hex": "01ff",
+          "number": "511"
+        }
+      ],
+      "data": {
+        "hex": "64617461",
+        "text": "data",
+        "number": "1684108385"
+      }
+    }
+  ]
+}`, string(jsonHeader))
+}
@@ -0,0 +1,10 @@
+package process
+
+// Heartbeat holds the latest main chain progress, periodically notified to subscribers while the main chain feed is alive
+type Heartbeat struct {
+	Round                         uint64
+	RoundTimestamp                uint64
+	Epoch                         uint32
+	LastFinalizedHeaderHash       []byte
+	ConsecutiveRoundsWithoutBlock uint64
+}
@@ -4,16 +4,44 @@ import "errors"
 
 var errNilMarshaller = errors.New("nil marshaller provided")
 
+var errNilHasher = errors.New("nil hasher provided")
+
 var errNilIndexer = errors.New("nil indexer provided")
 
 var errNilSovereignNotifier = errors.New("nil sovereign notifier provided")
 
 var errOperationTypeInvalid = errors.New("invalid/unknown operation type")
 
+var errPayloadVersionNotSupported = errors.New("payload version not supported")
+
 var errNilOutportBlockCache = errors.New("nil outport block cache provided")
 
+var errNilAccountsTracker = errors.New("nil accounts tracker provided")
+
+var errNilValidatorsTracker = errors.New("nil validators tracker provided")
+
+var errNilLivenessTracker = errors.New("nil liveness tracker provided")
+
+var errNilOutportBlockFilter = errors.New("nil outport block filter provided")
+
+var errInvalidPendingFinalizationTimeout = errors.New("invalid pending finalization timeout provided")
+
 var errOutportBlockNotFound = errors.New("outport block not found in cache")
 
 var errNilOutportBlock = errors.New("nil outport block provided to be added in cache")
 
-var errOutportBlockAlreadyExists = errors.New("outport block already exists in cache")
+var errEmptyCachePath = errors.New("empty outport block cache path provided")
+
+var errInvalidCompactionInterval = errors.New("invalid compaction interval provided")
+
+var errConflictingOutportBlock = errors.New("conflicting content received for an outport block already in cache")
+
+var errInvalidUnknownTopicPolicy = errors.New("invalid unknown topic policy provided")
+
+var errEmptyTopic = errors.New("empty topic provided")
+
+var errNilHandlerFunc = errors.New("nil handler func provided")
+
+var errHandlerAlreadyRegistered = errors.New("handler already registered")
+
+var errInvalidHeaderType = errors.New("invalid header type")
@@ -1,48 +1,541 @@
 package indexer
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/liveness"
+	notifierProcess "github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/validators"
 )
 
+// numFinalizedHashesToKeep is the number of recently finalized header hashes used to deduplicate blocks received
+// from multiple observers
+const numFinalizedHashesToKeep = 1000
+
+// ArgsIndexer is a struct placeholder for args needed to create an indexer. A zero pending finalization timeout
+// disables the buffering of finalized blocks received before their saved block
+type ArgsIndexer struct {
+	Notifier                   process.SovereignNotifier
+	Cache                      OutportBlockCache
+	AccountsTracker            process.AccountsTracker
+	ValidatorsTracker          process.ValidatorsTracker
+	LivenessTracker            process.LivenessTracker
+	OutportBlockFilter         process.OutportBlockFilter
+	Marshaller                 marshal.Marshalizer
+	PendingFinalizationTimeout time.Duration
+}
+
+// blockKey identifies the block of a saved accounts payload, which does not hold the header hash. The accounts are
+// attached to the last saved block with the same key, the one just committed by the observer
+type blockKey struct {
+	shardID   uint32
+	timestamp uint64
+}
+
 type indexer struct {
-	notifier process.SovereignNotifier
-	cache    OutportBlockCache
+	notifier          process.SovereignNotifier
+	queuingNotifier   process.QueuingNotifier
+	cache             OutportBlockCache
+	accountsTracker   process.AccountsTracker
+	validatorsTracker process.ValidatorsTracker
+	livenessTracker   process.LivenessTracker
+	blockFilter       process.OutportBlockFilter
+	marshaller        marshal.Marshalizer
+	blockCreators     map[core.HeaderType]block.EmptyBlockCreator
+
+	pendingFinalizationTimeout time.Duration
+
+	mutFinalized         sync.Mutex
+	finalizedHashes      map[string]struct{}
+	finalizedHashesQueue []string
+	pendingFinalizations map[string]*time.Timer
+
+	savedBlocksKeys         map[string]blockKey
+	lastSavedHashes         map[blockKey]string
+	pendingAccounts         map[string]map[string]*alteredAccount.AlteredAccount
+	unresolvedAccounts      map[blockKey]map[string]*alteredAccount.AlteredAccount
+	lastFinalizedTimestamps map[uint32]uint64
 }
 
-// NewIndexer creates an indexer which will internally save received blocks
-// and notify sovereign shards for each finalized block
+// NewIndexer creates an indexer which will internally save received blocks in the provided cache and notify
+// sovereign shards for each finalized block, without tracking accounts, validators or liveness
 func NewIndexer(notifier process.SovereignNotifier, cache OutportBlockCache) (process.Indexer, error) {
-	if check.IfNil(notifier) {
+	return NewIndexerWithArgs(ArgsIndexer{
+		Notifier:           notifier,
+		Cache:              cache,
+		AccountsTracker:    accounts.NewDisabledAccountsTracker(),
+		ValidatorsTracker:  validators.NewValidatorsTracker(),
+		LivenessTracker:    liveness.NewDisabledLivenessTracker(),
+		OutportBlockFilter: notifierProcess.NewDisabledOutportBlockFilter(),
+		Marshaller:         &marshal.GogoProtoMarshalizer{},
+	})
+}
+
+// NewIndexerWithArgs creates an indexer which will internally save received blocks
+// and notify sovereign shards for each finalized block. Blocks and finalized signals received multiple times,
+// from the same or different observers, are deduplicated by header hash. Finalized signals received before their
+// block are kept pending until the block is saved or the pending finalization timeout expires. Saved accounts are
+// kept per header hash and applied only once their block is finalized. Blocks which can never be notified are dropped,
+// while the other notification errors are retried. If the notifier also implements process.QueuingNotifier, the
+// trackers are updated only once the queued blocks are delivered
+func NewIndexerWithArgs(args ArgsIndexer) (process.Indexer, error) {
+	if check.IfNil(args.Notifier) {
 		return nil, errNilSovereignNotifier
 	}
-	if check.IfNil(cache) {
+	if check.IfNil(args.Cache) {
 		return nil, errNilOutportBlockCache
 	}
+	if check.IfNil(args.AccountsTracker) {
+		return nil, errNilAccountsTracker
+	}
+	if check.IfNil(args.ValidatorsTracker) {
+		return nil, errNilValidatorsTracker
+	}
+	if check.IfNil(args.LivenessTracker) {
+		return nil, errNilLivenessTracker
+	}
+	if check.IfNil(args.OutportBlockFilter) {
+		return nil, errNilOutportBlockFilter
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if args.PendingFinalizationTimeout < 0 {
+		return nil, errInvalidPendingFinalizationTimeout
+	}
+
+	queuingNotifier, _ := args.Notifier.(process.QueuingNotifier)
 
 	return &indexer{
-		cache:    cache,
-		notifier: notifier,
+		cache:             args.Cache,
+		notifier:          args.Notifier,
+		queuingNotifier:   queuingNotifier,
+		accountsTracker:   args.AccountsTracker,
+		validatorsTracker: args.ValidatorsTracker,
+		livenessTracker:   args.LivenessTracker,
+		blockFilter:       args.OutportBlockFilter,
+		marshaller:        args.Marshaller,
+		blockCreators: map[core.HeaderType]block.EmptyBlockCreator{
+			core.ShardHeaderV1: block.NewEmptyHeaderCreator(),
+			core.ShardHeaderV2: block.NewEmptyHeaderV2Creator(),
+			core.MetaHeader:    block.NewEmptyMetaBlockCreator(),
+		},
+
+		pendingFinalizationTimeout: args.PendingFinalizationTimeout,
+		finalizedHashes:            make(map[string]struct{}),
+		pendingFinalizations:       make(map[string]*time.Timer),
+		savedBlocksKeys:            make(map[string]blockKey),
+		lastSavedHashes:            make(map[blockKey]string),
+		pendingAccounts:            make(map[string]map[string]*alteredAccount.AlteredAccount),
+		unresolvedAccounts:         make(map[blockKey]map[string]*alteredAccount.AlteredAccount),
+		lastFinalizedTimestamps:    make(map[uint32]uint64),
 	}, nil
 }
 
-// SaveBlock will save the received block in an internal cache
+// SaveBlock will save the received block in an internal cache, if not already saved or finalized.
+// Identical re-sent blocks are ignored, while conflicting contents for the same header hash are flagged.
+// If the block was already signaled as finalized, it is notified right away. The block is filtered before being saved,
+// so that only the data needed to notify it is kept
 func (i *indexer) SaveBlock(outportBlock *outport.OutportBlock) error {
-	return i.cache.Add(outportBlock)
+	i.mutFinalized.Lock()
+	defer i.mutFinalized.Unlock()
+
+	if outportBlock != nil && outportBlock.BlockData != nil && i.wasFinalized(outportBlock.BlockData.HeaderHash) {
+		log.Trace("skipped saving already finalized block", "hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash))
+		return nil
+	}
+
+	err := i.cache.Add(i.blockFilter.FilterOutportBlock(outportBlock))
+	if errors.Is(err, errConflictingOutportBlock) {
+		// the conflicting block is dropped and not reported back, otherwise a blocking acknowledge would make the
+		// observer re-send it indefinitely
+		log.Error("security event: received a different content for an already saved block, the first one is kept",
+			"error", err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	i.saveBlockKey(outportBlock)
+
+	return i.completePendingFinalization(outportBlock.GetBlockData().GetHeaderHash())
+}
+
+func (i *indexer) saveBlockKey(outportBlock *outport.OutportBlock) {
+	headerHash := outportBlock.GetBlockData().GetHeaderHash()
+	key, err := i.getBlockKey(outportBlock.GetBlockData())
+	if err != nil {
+		log.Debug("could not get the block key, its saved accounts will not be applied",
+			"hash", hex.EncodeToString(headerHash), "error", err.Error())
+		return
+	}
+
+	hashStr := string(headerHash)
+	i.savedBlocksKeys[hashStr] = key
+	i.lastSavedHashes[key] = hashStr
+
+	// accounts received before their block are attached to it
+	unresolvedAccounts, found := i.unresolvedAccounts[key]
+	if !found {
+		return
+	}
+
+	delete(i.unresolvedAccounts, key)
+	addAccounts(i.getOrAddPendingAccounts(hashStr), unresolvedAccounts)
+}
+
+func (i *indexer) getBlockKey(blockData *outport.BlockData) (blockKey, error) {
+	creator, found := i.blockCreators[core.HeaderType(blockData.GetHeaderType())]
+	if !found {
+		return blockKey{}, fmt.Errorf("%w: %s", errInvalidHeaderType, blockData.GetHeaderType())
+	}
+
+	header, err := block.GetHeaderFromBytes(i.marshaller, creator, blockData.GetHeaderBytes())
+	if err != nil {
+		return blockKey{}, err
+	}
+
+	return blockKey{
+		shardID:   header.GetShardID(),
+		timestamp: header.GetTimeStamp(),
+	}, nil
+}
+
+func (i *indexer) completePendingFinalization(headerHash []byte) error {
+	timer, isPending := i.pendingFinalizations[string(headerHash)]
+	if !isPending {
+		return nil
+	}
+
+	timer.Stop()
+	delete(i.pendingFinalizations, string(headerHash))
+
+	outportBlock, err := i.cache.Extract(headerHash)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("completed pending finalization", "hash", hex.EncodeToString(headerHash))
+
+	err = i.finalize(headerHash, outportBlock)
+	if err != nil {
+		// the finalization is completed again once the block is re-sent
+		i.addPendingFinalization(headerHash)
+	}
+
+	return err
 }
 
 // FinalizedBlock will check the finalized header for incoming txs
-// to sovereign shard and push the finalized block through notifier
+// to sovereign shard and push the finalized block through notifier, if not already finalized
 func (i *indexer) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	i.mutFinalized.Lock()
+	defer i.mutFinalized.Unlock()
+
+	if i.wasFinalized(finalizedBlock.HeaderHash) {
+		log.Trace("skipped duplicated finalized block", "hash", hex.EncodeToString(finalizedBlock.HeaderHash))
+		return nil
+	}
+
+	_, isPending := i.pendingFinalizations[string(finalizedBlock.HeaderHash)]
+	if isPending {
+		log.Trace("skipped duplicated pending finalized block", "hash", hex.EncodeToString(finalizedBlock.HeaderHash))
+		return nil
+	}
+
 	outportBlock, err := i.cache.Extract(finalizedBlock.HeaderHash)
+	if errors.Is(err, errOutportBlockNotFound) && i.pendingFinalizationTimeout > 0 {
+		i.addPendingFinalization(finalizedBlock.HeaderHash)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	return i.notifier.Notify(outportBlock)
+	return i.finalize(finalizedBlock.HeaderHash, outportBlock)
+}
+
+// finalize notifies the block together with the accounts saved for it. The block is marked as finalized once it was
+// notified or queued for delivery, while the trackers are updated only once it was delivered. A block which can never
+// be notified is dropped, otherwise it is put back in the cache, so that it can be retried
+func (i *indexer) finalize(headerHash []byte, outportBlock *outport.OutportBlock) error {
+	alteredAccounts := i.getFinalizedAccounts(headerHash, outportBlock.AlteredAccounts)
+	outportBlock.AlteredAccounts = alteredAccounts
+
+	err := i.notify(outportBlock, func() {
+		i.livenessTracker.SaveFinalizedBlock(headerHash)
+		i.accountsTracker.UpdateAccounts(alteredAccounts)
+	})
+	if errors.Is(err, process.ErrInvalidOutportBlock) {
+		// the block is not reported back, otherwise a blocking acknowledge would make the observer re-send it
+		// indefinitely, so it is marked as finalized to also drop its re-sends
+		log.Error("dropped finalized block which can not be notified",
+			"hash", hex.EncodeToString(headerHash), "error", err.Error())
+		i.markFinalized(headerHash)
+		i.dropPendingAccounts(headerHash)
+		return nil
+	}
+	if err != nil {
+		i.keepForRetry(outportBlock)
+		return err
+	}
+
+	i.markFinalized(headerHash)
+	i.dropPendingAccounts(headerHash)
+
+	return nil
+}
+
+// notify calls the delivery handler once the block was delivered, which for a queuing notifier may happen after the
+// block was queued. The delivery handler should not use the indexer's state, since it may be called asynchronously
+func (i *indexer) notify(outportBlock *outport.OutportBlock, deliveryHandler func()) error {
+	if !check.IfNil(i.queuingNotifier) {
+		return i.queuingNotifier.NotifyWithDeliveryHandler(outportBlock, deliveryHandler)
+	}
+
+	err := i.notifier.Notify(outportBlock)
+	if err != nil {
+		return err
+	}
+
+	deliveryHandler()
+
+	return nil
+}
+
+func (i *indexer) keepForRetry(outportBlock *outport.OutportBlock) {
+	err := i.cache.Add(outportBlock)
+	if err != nil {
+		log.Error("could not keep the block which was not notified",
+			"hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash), "error", err.Error())
+	}
+}
+
+// getFinalizedAccounts returns the block's altered accounts, overwritten by the accounts saved for the block
+func (i *indexer) getFinalizedAccounts(
+	headerHash []byte,
+	alteredAccounts map[string]*alteredAccount.AlteredAccount,
+) map[string]*alteredAccount.AlteredAccount {
+	pendingAccounts, hasAccounts := i.pendingAccounts[string(headerHash)]
+	if !hasAccounts {
+		return alteredAccounts
+	}
+
+	finalizedAccounts := make(map[string]*alteredAccount.AlteredAccount, len(alteredAccounts)+len(pendingAccounts))
+	addAccounts(finalizedAccounts, alteredAccounts)
+	addAccounts(finalizedAccounts, pendingAccounts)
+
+	return finalizedAccounts
+}
+
+// dropPendingAccounts drops the accounts saved for the finalized block and for the same shard's older blocks, which
+// will never be finalized
+func (i *indexer) dropPendingAccounts(headerHash []byte) {
+	key, found := i.savedBlocksKeys[string(headerHash)]
+	if !found {
+		delete(i.pendingAccounts, string(headerHash))
+		return
+	}
+
+	i.lastFinalizedTimestamps[key.shardID] = key.timestamp
+	for hash, savedKey := range i.savedBlocksKeys {
+		if savedKey.shardID == key.shardID && savedKey.timestamp <= key.timestamp {
+			i.dropBlockAccounts(hash, savedKey)
+		}
+	}
+	for unresolvedKey := range i.unresolvedAccounts {
+		if unresolvedKey.shardID == key.shardID && unresolvedKey.timestamp <= key.timestamp {
+			delete(i.unresolvedAccounts, unresolvedKey)
+		}
+	}
+}
+
+func (i *indexer) dropBlockAccounts(headerHash string, key blockKey) {
+	delete(i.pendingAccounts, headerHash)
+	delete(i.savedBlocksKeys, headerHash)
+	if i.lastSavedHashes[key] == headerHash {
+		delete(i.lastSavedHashes, key)
+	}
+}
+
+func (i *indexer) getOrAddPendingAccounts(headerHash string) map[string]*alteredAccount.AlteredAccount {
+	pendingAccounts, found := i.pendingAccounts[headerHash]
+	if !found {
+		pendingAccounts = make(map[string]*alteredAccount.AlteredAccount)
+		i.pendingAccounts[headerHash] = pendingAccounts
+	}
+
+	return pendingAccounts
+}
+
+func addAccounts(dest map[string]*alteredAccount.AlteredAccount, src map[string]*alteredAccount.AlteredAccount) {
+	for encodedAddr, account := range src {
+		dest[encodedAddr] = account
+	}
+}
+
+func (i *indexer) addPendingFinalization(headerHash []byte) {
+	hashStr := string(headerHash)
+	log.Debug("received finalized block before its block, waiting for it to be saved",
+		"hash", hex.EncodeToString(headerHash), "timeout", i.pendingFinalizationTimeout)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(i.pendingFinalizationTimeout, func() {
+		i.mutFinalized.Lock()
+		defer i.mutFinalized.Unlock()
+
+		if i.pendingFinalizations[hashStr] != timer {
+			return
+		}
+
+		delete(i.pendingFinalizations, hashStr)
+		log.Error("finalized block was not notified, its block was not received in time",
+			"hash", hex.EncodeToString(headerHash), "timeout", i.pendingFinalizationTimeout)
+	})
+	i.pendingFinalizations[hashStr] = timer
+}
+
+func (i *indexer) wasFinalized(headerHash []byte) bool {
+	_, found := i.finalizedHashes[string(headerHash)]
+	return found
+}
+
+func (i *indexer) markFinalized(headerHash []byte) {
+	hashStr := string(headerHash)
+	i.finalizedHashes[hashStr] = struct{}{}
+	i.finalizedHashesQueue = append(i.finalizedHashesQueue, hashStr)
+
+	if len(i.finalizedHashesQueue) > numFinalizedHashesToKeep {
+		delete(i.finalizedHashes, i.finalizedHashesQueue[0])
+		i.finalizedHashesQueue = i.finalizedHashesQueue[1:]
+	}
+}
+
+// RevertIndexedBlock will drop the reverted block from the cache, together with its pending finalization and the
+// accounts saved for it. An already finalized block can not be reverted, so its revert is ignored
+func (i *indexer) RevertIndexedBlock(blockData *outport.BlockData) error {
+	i.mutFinalized.Lock()
+	defer i.mutFinalized.Unlock()
+
+	headerHash := blockData.GetHeaderHash()
+	if i.wasFinalized(headerHash) {
+		log.Warn("ignored revert of an already finalized block", "hash", hex.EncodeToString(headerHash))
+		return nil
+	}
+
+	_, err := i.cache.Extract(headerHash)
+	if err != nil && !errors.Is(err, errOutportBlockNotFound) {
+		return err
+	}
+
+	timer, isPending := i.pendingFinalizations[string(headerHash)]
+	if isPending {
+		timer.Stop()
+		delete(i.pendingFinalizations, string(headerHash))
+	}
+
+	key, found := i.savedBlocksKeys[string(headerHash)]
+	if !found {
+		key, err = i.getBlockKey(blockData)
+		if err != nil {
+			log.Debug("could not get the reverted block key, the accounts received before it are kept",
+				"hash", hex.EncodeToString(headerHash), "error", err.Error())
+			return nil
+		}
+		delete(i.unresolvedAccounts, key)
+	}
+	i.dropBlockAccounts(string(headerHash), key)
+
+	log.Debug("reverted indexed block", "hash", hex.EncodeToString(headerHash), "shard", key.shardID, "timestamp", key.timestamp)
+
+	return nil
+}
+
+// SaveAccounts will keep the altered accounts until their block is finalized. The accounts are attached to the last
+// saved block with the same shard and timestamp, or to the next one saved if their block was not received yet.
+// Accounts received for an already finalized block are dropped
+func (i *indexer) SaveAccounts(accounts *outport.Accounts) error {
+	i.mutFinalized.Lock()
+	defer i.mutFinalized.Unlock()
+
+	key := blockKey{
+		shardID:   accounts.ShardID,
+		timestamp: accounts.BlockTimestamp,
+	}
+	lastFinalizedTimestamp, found := i.lastFinalizedTimestamps[key.shardID]
+	if found && key.timestamp <= lastFinalizedTimestamp {
+		log.Warn("dropped accounts received for an already finalized block",
+			"shard", key.shardID, "timestamp", key.timestamp)
+		return nil
+	}
+
+	headerHash, found := i.lastSavedHashes[key]
+	if found {
+		addAccounts(i.getOrAddPendingAccounts(headerHash), accounts.AlteredAccounts)
+		return nil
+	}
+
+	unresolvedAccounts, found := i.unresolvedAccounts[key]
+	if !found {
+		unresolvedAccounts = make(map[string]*alteredAccount.AlteredAccount, len(accounts.AlteredAccounts))
+		i.unresolvedAccounts[key] = unresolvedAccounts
+	}
+	addAccounts(unresolvedAccounts, accounts.AlteredAccounts)
+
+	return nil
+}
+
+// SaveValidatorsPubKeys will update the validators set of each shard for the received epoch
+func (i *indexer) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	i.validatorsTracker.SaveValidatorsPubKeys(validatorsPubKeys)
+	return nil
+}
+
+// SaveValidatorsRating will update the validators ratings of a shard for the received epoch
+func (i *indexer) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) error {
+	i.validatorsTracker.SaveValidatorsRating(validatorsRating)
+	return nil
+}
+
+// SaveRoundsInfo will update the main chain liveness with the received rounds
+func (i *indexer) SaveRoundsInfo(roundsInfo *outport.RoundsInfo) error {
+	i.livenessTracker.SaveRoundsInfo(roundsInfo)
+	return nil
+}
+
+// Close will drop the pending finalizations, close the cache and the liveness tracker
+func (i *indexer) Close() error {
+	i.mutFinalized.Lock()
+	for hash, timer := range i.pendingFinalizations {
+		timer.Stop()
+		delete(i.pendingFinalizations, hash)
+	}
+	i.mutFinalized.Unlock()
+
+	errCache := i.cache.Close()
+	errLiveness := i.livenessTracker.Close()
+	if errCache != nil {
+		return errCache
+	}
+
+	return errLiveness
 }
 
+// IsInterfaceNil checks if the underlying pointer is nil
 func (i *indexer) IsInterfaceNil() bool {
 	return i == nil
 }
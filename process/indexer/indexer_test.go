@@ -2,34 +2,115 @@ package indexer
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
 	"github.com/stretchr/testify/require"
 )
 
+func createIndexerArgs() ArgsIndexer {
+	return ArgsIndexer{
+		Notifier:           &testscommon.SovereignNotifierStub{},
+		Cache:              &testscommon.OutportBlockCacheStub{},
+		AccountsTracker:    &testscommon.AccountsTrackerStub{},
+		ValidatorsTracker:  &testscommon.ValidatorsTrackerStub{},
+		LivenessTracker:    &testscommon.LivenessTrackerStub{},
+		OutportBlockFilter: &testscommon.OutportBlockFilterStub{},
+		Marshaller:         &testscommon.MarshallerMock{},
+	}
+}
+
 func TestNewIndexer(t *testing.T) {
 	t.Parallel()
 
 	t.Run("should work", func(t *testing.T) {
-		indx, err := NewIndexer(&testscommon.SovereignNotifierStub{}, &testscommon.OutportBlockCacheStub{})
+		indx, err := NewIndexerWithArgs(createIndexerArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(indx))
+	})
+
+	t.Run("notifier and cache constructor should work", func(t *testing.T) {
+		indx, err := NewIndexer(&testscommon.SovereignNotifierStub{}, createOutportBlockCache())
 		require.Nil(t, err)
 		require.False(t, check.IfNil(indx))
+
+		indx, err = NewIndexer(nil, createOutportBlockCache())
+		require.Equal(t, errNilSovereignNotifier, err)
+		require.Nil(t, indx)
 	})
 
 	t.Run("nil sovereign notifier, should error", func(t *testing.T) {
-		indx, err := NewIndexer(nil, &testscommon.OutportBlockCacheStub{})
+		args := createIndexerArgs()
+		args.Notifier = nil
+		indx, err := NewIndexerWithArgs(args)
 		require.Equal(t, errNilSovereignNotifier, err)
 		require.Nil(t, indx)
 	})
 
 	t.Run("nil cache, should error", func(t *testing.T) {
-		indx, err := NewIndexer(&testscommon.SovereignNotifierStub{}, nil)
+		args := createIndexerArgs()
+		args.Cache = nil
+		indx, err := NewIndexerWithArgs(args)
 		require.Equal(t, errNilOutportBlockCache, err)
 		require.Nil(t, indx)
 	})
+
+	t.Run("nil accounts tracker, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.AccountsTracker = nil
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errNilAccountsTracker, err)
+		require.Nil(t, indx)
+	})
+
+	t.Run("nil validators tracker, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.ValidatorsTracker = nil
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errNilValidatorsTracker, err)
+		require.Nil(t, indx)
+	})
+
+	t.Run("nil liveness tracker, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.LivenessTracker = nil
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errNilLivenessTracker, err)
+		require.Nil(t, indx)
+	})
+
+	t.Run("nil outport block filter, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.OutportBlockFilter = nil
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errNilOutportBlockFilter, err)
+		require.Nil(t, indx)
+	})
+
+	t.Run("nil marshaller, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.Marshaller = nil
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errNilMarshaller, err)
+		require.Nil(t, indx)
+	})
+
+	t.Run("negative pending finalization timeout, should error", func(t *testing.T) {
+		args := createIndexerArgs()
+		args.PendingFinalizationTimeout = -time.Second
+		indx, err := NewIndexerWithArgs(args)
+		require.Equal(t, errInvalidPendingFinalizationTimeout, err)
+		require.Nil(t, indx)
+	})
 }
 
 func TestIndexer_SaveBlock(t *testing.T) {
@@ -42,7 +123,9 @@ func TestIndexer_SaveBlock(t *testing.T) {
 			return nil
 		},
 	}
-	indx, _ := NewIndexer(&testscommon.SovereignNotifierStub{}, cache)
+	args := createIndexerArgs()
+	args.Cache = cache
+	indx, _ := NewIndexerWithArgs(args)
 
 	err := indx.SaveBlock(&outport.OutportBlock{})
 	require.Nil(t, err)
@@ -57,7 +140,13 @@ func TestIndexer_FinalizedBlock(t *testing.T) {
 
 		wasExtractCalled := false
 		hash := []byte("hash")
-		outportBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}}
+		alteredAccounts := map[string]*alteredAccount.AlteredAccount{
+			"erd1a": {Address: "erd1a", Balance: "1"},
+		}
+		outportBlock := &outport.OutportBlock{
+			BlockData:       &outport.BlockData{HeaderHash: hash},
+			AlteredAccounts: alteredAccounts,
+		}
 		cache := &testscommon.OutportBlockCacheStub{
 			ExtractCalled: func(headerHash []byte) (*outport.OutportBlock, error) {
 				wasExtractCalled = true
@@ -68,6 +157,15 @@ func TestIndexer_FinalizedBlock(t *testing.T) {
 		}
 
 		wasNotifyCalled := false
+		wasUpdateAccountsCalled := false
+		accountsTracker := &testscommon.AccountsTrackerStub{
+			UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+				wasUpdateAccountsCalled = true
+				require.True(t, wasNotifyCalled)
+				require.Equal(t, alteredAccounts, accounts)
+			},
+		}
+
 		notifier := &testscommon.SovereignNotifierStub{
 			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
 				wasNotifyCalled = true
@@ -76,12 +174,165 @@ func TestIndexer_FinalizedBlock(t *testing.T) {
 				return nil
 			},
 		}
-		indx, _ := NewIndexer(notifier, cache)
+		wasSaveFinalizedBlockCalled := false
+		livenessTracker := &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				wasSaveFinalizedBlockCalled = true
+				require.True(t, wasNotifyCalled)
+				require.Equal(t, hash, headerHash)
+			},
+		}
+		args := createIndexerArgs()
+		args.Notifier = notifier
+		args.Cache = cache
+		args.AccountsTracker = accountsTracker
+		args.LivenessTracker = livenessTracker
+		indx, _ := NewIndexerWithArgs(args)
 
 		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
 		require.Nil(t, err)
 		require.True(t, wasExtractCalled)
 		require.True(t, wasNotifyCalled)
+		require.True(t, wasUpdateAccountsCalled)
+		require.True(t, wasSaveFinalizedBlockCalled)
+	})
+
+	t.Run("notify error should keep the block for retry", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("notify error")
+		numNotifyCalls := 0
+		finalizedHashes := make([][]byte, 0)
+		updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				numNotifyCalls++
+				if numNotifyCalls == 1 {
+					return errNotify
+				}
+
+				return nil
+			},
+		}
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		args.AccountsTracker = &testscommon.AccountsTrackerStub{
+			UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+				updatedAccounts = append(updatedAccounts, accounts)
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+
+		hash := []byte("hash")
+		outportBlock := createOutportBlockWithHeader(t, hash, 1, 10)
+		err := indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+		alteredAccounts := map[string]*alteredAccount.AlteredAccount{
+			"erd1a": {Address: "erd1a", Balance: "1"},
+		}
+		err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: alteredAccounts})
+		require.Nil(t, err)
+
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Equal(t, errNotify, err)
+		require.Empty(t, finalizedHashes)
+		require.Empty(t, updatedAccounts)
+
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		require.Equal(t, 2, numNotifyCalls)
+		require.Equal(t, [][]byte{hash}, finalizedHashes)
+		require.Equal(t, []map[string]*alteredAccount.AlteredAccount{alteredAccounts}, updatedAccounts)
+	})
+
+	t.Run("queued block should update the trackers once delivered", func(t *testing.T) {
+		t.Parallel()
+
+		var deliver func()
+		finalizedHashes := make([][]byte, 0)
+		updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.Notifier = &testscommon.QueuingNotifierStub{
+			NotifyWithDeliveryHandlerCalled: func(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error {
+				deliver = deliveryHandler
+				return nil
+			},
+		}
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		args.AccountsTracker = &testscommon.AccountsTrackerStub{
+			UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+				updatedAccounts = append(updatedAccounts, accounts)
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+
+		hash := []byte("hash")
+		err := indx.SaveBlock(createOutportBlockWithHeader(t, hash, 1, 10))
+		require.Nil(t, err)
+		alteredAccounts := map[string]*alteredAccount.AlteredAccount{
+			"erd1a": {Address: "erd1a", Balance: "1"},
+		}
+		err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: alteredAccounts})
+		require.Nil(t, err)
+
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		require.Empty(t, finalizedHashes)
+		require.Empty(t, updatedAccounts)
+
+		deliver()
+		require.Equal(t, [][]byte{hash}, finalizedHashes)
+		require.Equal(t, []map[string]*alteredAccount.AlteredAccount{alteredAccounts}, updatedAccounts)
+	})
+
+	t.Run("invalid block should be dropped", func(t *testing.T) {
+		t.Parallel()
+
+		numNotifyCalls := 0
+		finalizedHashes := make([][]byte, 0)
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				numNotifyCalls++
+				return fmt.Errorf("%w: invalid header", process.ErrInvalidOutportBlock)
+			},
+		}
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+
+		hash := []byte("hash")
+		outportBlock := createOutportBlockWithHeader(t, hash, 1, 10)
+		err := indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		require.Equal(t, 1, numNotifyCalls)
+		require.Empty(t, finalizedHashes)
+
+		// the dropped block is not kept for retry and its re-sends are ignored
+		err = indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+		_, err = indx.(*indexer).cache.Extract(hash)
+		require.ErrorIs(t, err, errOutportBlockNotFound)
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		require.Equal(t, 1, numNotifyCalls)
 	})
 
 	t.Run("error getting block from cache", func(t *testing.T) {
@@ -106,12 +357,508 @@ func TestIndexer_FinalizedBlock(t *testing.T) {
 				return nil
 			},
 		}
-		indx, _ := NewIndexer(notifier, cache)
+		args := createIndexerArgs()
+		args.Notifier = notifier
+		args.Cache = cache
+		indx, _ := NewIndexerWithArgs(args)
 
 		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
 		require.Equal(t, errGetBlock, err)
 		require.True(t, wasExtractCalled)
 		require.False(t, wasNotifyCalled)
 	})
+}
+
+func createOutportBlockWithHeader(t *testing.T, hash []byte, shardID uint32, timestamp uint64) *outport.OutportBlock {
+	headerBytes, err := (&testscommon.MarshallerMock{}).Marshal(&block.HeaderV2{
+		Header: &block.Header{
+			ShardID:   shardID,
+			TimeStamp: timestamp,
+		},
+	})
+	require.Nil(t, err)
+
+	return &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderHash:  hash,
+			HeaderType:  string(core.ShardHeaderV2),
+			HeaderBytes: headerBytes,
+		},
+	}
+}
+
+func TestIndexer_SaveAccounts(t *testing.T) {
+	t.Parallel()
+
+	updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+	args := createIndexerArgs()
+	args.Cache = createOutportBlockCache()
+	args.AccountsTracker = &testscommon.AccountsTrackerStub{
+		UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+			if len(accounts) != 0 {
+				updatedAccounts = append(updatedAccounts, accounts)
+			}
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	accountsBlock1 := map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
+	}
+	accountsBlock2 := map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "2"},
+	}
+	accountsOtherShard := map[string]*alteredAccount.AlteredAccount{
+		"erd1b": {Address: "erd1b", Balance: "3"},
+	}
+
+	// accounts are saved both before and after their block
+	err := indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: accountsBlock1})
+	require.Nil(t, err)
+	err = indx.SaveAccounts(&outport.Accounts{ShardID: 2, BlockTimestamp: 10, AlteredAccounts: accountsOtherShard})
+	require.Nil(t, err)
+	err = indx.SaveBlock(createOutportBlockWithHeader(t, []byte("hash1"), 1, 10))
+	require.Nil(t, err)
+	err = indx.SaveBlock(createOutportBlockWithHeader(t, []byte("hash2"), 1, 20))
+	require.Nil(t, err)
+	err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 20, AlteredAccounts: accountsBlock2})
+	require.Nil(t, err)
+	require.Empty(t, updatedAccounts)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+	require.Equal(t, []map[string]*alteredAccount.AlteredAccount{accountsBlock1}, updatedAccounts)
+
+	// accounts of an already finalized block are dropped
+	err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: accountsBlock2})
+	require.Nil(t, err)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash2")})
+	require.Nil(t, err)
+	require.Equal(t, []map[string]*alteredAccount.AlteredAccount{accountsBlock1, accountsBlock2}, updatedAccounts)
+}
+
+func TestIndexer_SaveAccountsOfForkedBlocks(t *testing.T) {
+	t.Parallel()
+
+	updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+	args := createIndexerArgs()
+	args.Cache = createOutportBlockCache()
+	args.AccountsTracker = &testscommon.AccountsTrackerStub{
+		UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+			if len(accounts) != 0 {
+				updatedAccounts = append(updatedAccounts, accounts)
+			}
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	accountsFork1 := map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
+	}
+	accountsFork2 := map[string]*alteredAccount.AlteredAccount{
+		"erd1b": {Address: "erd1b", Balance: "2"},
+	}
+
+	// both forks have the same shard and timestamp, each one's accounts following it
+	err := indx.SaveBlock(createOutportBlockWithHeader(t, []byte("fork1"), 1, 10))
+	require.Nil(t, err)
+	err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: accountsFork1})
+	require.Nil(t, err)
+	err = indx.SaveBlock(createOutportBlockWithHeader(t, []byte("fork2"), 1, 10))
+	require.Nil(t, err)
+	err = indx.SaveAccounts(&outport.Accounts{ShardID: 1, BlockTimestamp: 10, AlteredAccounts: accountsFork2})
+	require.Nil(t, err)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("fork2")})
+	require.Nil(t, err)
+	require.Equal(t, []map[string]*alteredAccount.AlteredAccount{accountsFork2}, updatedAccounts)
+	require.Empty(t, indx.(*indexer).pendingAccounts)
+	require.Empty(t, indx.(*indexer).savedBlocksKeys)
+}
+
+func TestIndexer_RevertIndexedBlock(t *testing.T) {
+	t.Parallel()
+
+	updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+	args := createIndexerArgs()
+	args.Cache = createOutportBlockCache()
+	args.AccountsTracker = &testscommon.AccountsTrackerStub{
+		UpdateAccountsCalled: func(accounts map[string]*alteredAccount.AlteredAccount) {
+			if len(accounts) != 0 {
+				updatedAccounts = append(updatedAccounts, accounts)
+			}
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	revertedBlock := createOutportBlockWithHeader(t, []byte("hash1"), 1, 10)
+	err := indx.SaveBlock(revertedBlock)
+	require.Nil(t, err)
+	err = indx.SaveAccounts(&outport.Accounts{
+		ShardID:         1,
+		BlockTimestamp:  10,
+		AlteredAccounts: map[string]*alteredAccount.AlteredAccount{"erd1a": {Address: "erd1a", Balance: "1"}},
+	})
+	require.Nil(t, err)
+
+	err = indx.RevertIndexedBlock(revertedBlock.BlockData)
+	require.Nil(t, err)
+	require.Empty(t, indx.(*indexer).pendingAccounts)
+	require.Empty(t, indx.(*indexer).savedBlocksKeys)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash1")})
+	require.ErrorIs(t, err, errOutportBlockNotFound)
+	require.Empty(t, updatedAccounts)
+
+	// reverting an unknown block does nothing
+	err = indx.RevertIndexedBlock(&outport.BlockData{HeaderHash: []byte("unknown")})
+	require.Nil(t, err)
+
+	// reverting a block signaled as finalized before being saved drops its pending finalization
+	indx.(*indexer).pendingFinalizationTimeout = time.Minute
+	pendingBlock := createOutportBlockWithHeader(t, []byte("pending"), 1, 15)
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("pending")})
+	require.Nil(t, err)
+	require.Len(t, indx.(*indexer).pendingFinalizations, 1)
+	err = indx.RevertIndexedBlock(pendingBlock.BlockData)
+	require.Nil(t, err)
+	require.Empty(t, indx.(*indexer).pendingFinalizations)
+
+	// reverting an already finalized block is ignored
+	finalizedBlock := createOutportBlockWithHeader(t, []byte("hash2"), 1, 20)
+	err = indx.SaveBlock(finalizedBlock)
+	require.Nil(t, err)
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash2")})
+	require.Nil(t, err)
+	err = indx.RevertIndexedBlock(finalizedBlock.BlockData)
+	require.Nil(t, err)
+}
+
+func TestIndexer_SaveValidators(t *testing.T) {
+	t.Parallel()
+
+	validatorsPubKeys := &outport.ValidatorsPubKeys{Epoch: 1}
+	validatorsRating := &outport.ValidatorsRating{Epoch: 1}
+	wasSavePubKeysCalled := false
+	wasSaveRatingCalled := false
+	args := createIndexerArgs()
+	args.ValidatorsTracker = &testscommon.ValidatorsTrackerStub{
+		SaveValidatorsPubKeysCalled: func(pubKeys *outport.ValidatorsPubKeys) {
+			wasSavePubKeysCalled = true
+			require.Equal(t, validatorsPubKeys, pubKeys)
+		},
+		SaveValidatorsRatingCalled: func(rating *outport.ValidatorsRating) {
+			wasSaveRatingCalled = true
+			require.Equal(t, validatorsRating, rating)
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	err := indx.SaveValidatorsPubKeys(validatorsPubKeys)
+	require.Nil(t, err)
+	require.True(t, wasSavePubKeysCalled)
+
+	err = indx.SaveValidatorsRating(validatorsRating)
+	require.Nil(t, err)
+	require.True(t, wasSaveRatingCalled)
+}
+
+func TestIndexer_SaveRoundsInfo(t *testing.T) {
+	t.Parallel()
+
+	roundsInfo := &outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{{Round: 4, BlockWasProposed: true}},
+	}
+	wasSaveRoundsInfoCalled := false
+	wasCloseCalled := false
+	args := createIndexerArgs()
+	args.LivenessTracker = &testscommon.LivenessTrackerStub{
+		SaveRoundsInfoCalled: func(receivedRoundsInfo *outport.RoundsInfo) {
+			wasSaveRoundsInfoCalled = true
+			require.Equal(t, roundsInfo, receivedRoundsInfo)
+		},
+		CloseCalled: func() error {
+			wasCloseCalled = true
+			return nil
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	err := indx.SaveRoundsInfo(roundsInfo)
+	require.Nil(t, err)
+	require.True(t, wasSaveRoundsInfoCalled)
+
+	err = indx.Close()
+	require.Nil(t, err)
+	require.True(t, wasCloseCalled)
+}
+
+func TestIndexer_DuplicatedBlocksAreSkipped(t *testing.T) {
+	t.Parallel()
+
+	numNotifyCalls := 0
+	args := createIndexerArgs()
+	args.Cache = createOutportBlockCache()
+	args.Notifier = &testscommon.SovereignNotifierStub{
+		NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+			numNotifyCalls++
+			return nil
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	hash := []byte("hash")
+	outportBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}}
+
+	err := indx.SaveBlock(outportBlock)
+	require.Nil(t, err)
+	err = indx.SaveBlock(outportBlock)
+	require.Nil(t, err)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+	require.Nil(t, err)
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+	require.Nil(t, err)
+	require.Equal(t, 1, numNotifyCalls)
+
+	// a late block from a slower observer should not be cached and notified again
+	err = indx.SaveBlock(outportBlock)
+	require.Nil(t, err)
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+	require.Nil(t, err)
+	require.Equal(t, 1, numNotifyCalls)
+
+	err = indx.SaveBlock(nil)
+	require.Equal(t, errNilOutportBlock, err)
+}
+
+func TestIndexer_ConflictingBlocksKeepTheFirstContent(t *testing.T) {
+	t.Parallel()
+
+	var notifiedBlock *outport.OutportBlock
+	args := createIndexerArgs()
+	args.Cache = createOutportBlockCache()
+	args.Notifier = &testscommon.SovereignNotifierStub{
+		NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+			notifiedBlock = finalizedBlock
+			return nil
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+
+	hash := []byte("hash")
+	outportBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash, HeaderBytes: []byte("header")}}
+	conflictingBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash, HeaderBytes: []byte("other header")}}
+
+	err := indx.SaveBlock(outportBlock)
+	require.Nil(t, err)
+	err = indx.SaveBlock(conflictingBlock)
+	require.Nil(t, err)
+
+	err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+	require.Nil(t, err)
+	require.True(t, outportBlock == notifiedBlock)
+}
+
+func TestIndexer_OldFinalizedHashesAreRemoved(t *testing.T) {
+	t.Parallel()
+
+	args := createIndexerArgs()
+	args.Cache = &testscommon.OutportBlockCacheStub{
+		ExtractCalled: func(headerHash []byte) (*outport.OutportBlock, error) {
+			return &outport.OutportBlock{}, nil
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
+	idx := indx.(*indexer)
+
+	for i := 0; i < numFinalizedHashesToKeep+1; i++ {
+		err := idx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte(fmt.Sprintf("hash%d", i))})
+		require.Nil(t, err)
+	}
+
+	require.Len(t, idx.finalizedHashes, numFinalizedHashesToKeep)
+	require.Len(t, idx.finalizedHashesQueue, numFinalizedHashesToKeep)
+	require.False(t, idx.wasFinalized([]byte("hash0")))
+	require.True(t, idx.wasFinalized([]byte("hash1")))
+}
+
+func TestIndexer_FinalizedBlockBeforeSaveBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("block saved after finalization should be notified", func(t *testing.T) {
+		t.Parallel()
+
+		notifiedBlocks := make([]*outport.OutportBlock, 0)
+		finalizedHashes := make([][]byte, 0)
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.PendingFinalizationTimeout = time.Minute
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				notifiedBlocks = append(notifiedBlocks, finalizedBlock)
+				return nil
+			},
+		}
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+		defer func() {
+			_ = indx.Close()
+		}()
+
+		hash := []byte("hash")
+		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		err = indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+		require.Empty(t, notifiedBlocks)
+
+		outportBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}}
+		err = indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, []*outport.OutportBlock{outportBlock}, notifiedBlocks)
+		require.Equal(t, [][]byte{hash}, finalizedHashes)
+		require.Empty(t, indx.(*indexer).pendingFinalizations)
+
+		// re-sent block after finalization should not be notified again
+		err = indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+		require.Len(t, notifiedBlocks, 1)
+	})
+
+	t.Run("notify error should complete the finalization when the block is re-sent", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("notify error")
+		numNotifyCalls := 0
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.PendingFinalizationTimeout = time.Minute
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				numNotifyCalls++
+				if numNotifyCalls == 1 {
+					return errNotify
+				}
+
+				return nil
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+		defer func() {
+			_ = indx.Close()
+		}()
+
+		hash := []byte("hash")
+		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+
+		outportBlock := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}}
+		err = indx.SaveBlock(outportBlock)
+		require.Equal(t, errNotify, err)
+		require.Len(t, indx.(*indexer).pendingFinalizations, 1)
+
+		err = indx.SaveBlock(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, 2, numNotifyCalls)
+		require.Empty(t, indx.(*indexer).pendingFinalizations)
+	})
+
+	t.Run("pending finalization should time out", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		numNotifyCalls := 0
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.PendingFinalizationTimeout = 10 * time.Millisecond
+		args.Notifier = &testscommon.SovereignNotifierStub{
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				mut.Lock()
+				numNotifyCalls++
+				mut.Unlock()
+				return nil
+			},
+		}
+		indx, _ := NewIndexerWithArgs(args)
+		idx := indx.(*indexer)
+
+		hash := []byte("hash")
+		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: hash})
+		require.Nil(t, err)
+
+		require.Eventually(t, func() bool {
+			idx.mutFinalized.Lock()
+			defer idx.mutFinalized.Unlock()
+
+			return len(idx.pendingFinalizations) == 0
+		}, time.Second, 5*time.Millisecond)
+
+		err = indx.SaveBlock(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}})
+		require.Nil(t, err)
+
+		mut.Lock()
+		require.Zero(t, numNotifyCalls)
+		mut.Unlock()
+	})
+
+	t.Run("disabled pending finalization, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		indx, _ := NewIndexerWithArgs(args)
+
+		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash")})
+		require.ErrorIs(t, err, errOutportBlockNotFound)
+	})
+
+	t.Run("close should drop pending finalizations", func(t *testing.T) {
+		t.Parallel()
+
+		args := createIndexerArgs()
+		args.Cache = createOutportBlockCache()
+		args.PendingFinalizationTimeout = time.Minute
+		indx, _ := NewIndexerWithArgs(args)
+
+		err := indx.FinalizedBlock(&outport.FinalizedBlock{HeaderHash: []byte("hash")})
+		require.Nil(t, err)
+		require.Len(t, indx.(*indexer).pendingFinalizations, 1)
+
+		err = indx.Close()
+		require.Nil(t, err)
+		require.Empty(t, indx.(*indexer).pendingFinalizations)
+	})
+}
+
+func TestIndexer_Close(t *testing.T) {
+	t.Parallel()
+
+	errCache := errors.New("cache close error")
+	wasCacheCloseCalled := false
+	wasLivenessCloseCalled := false
+	args := createIndexerArgs()
+	args.Cache = &testscommon.OutportBlockCacheStub{
+		CloseCalled: func() error {
+			wasCacheCloseCalled = true
+			return errCache
+		},
+	}
+	args.LivenessTracker = &testscommon.LivenessTrackerStub{
+		CloseCalled: func() error {
+			wasLivenessCloseCalled = true
+			return nil
+		},
+	}
+	indx, _ := NewIndexerWithArgs(args)
 
+	err := indx.Close()
+	require.Equal(t, errCache, err)
+	require.True(t, wasCacheCloseCalled)
+	require.True(t, wasLivenessCloseCalled)
 }
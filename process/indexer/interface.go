@@ -6,12 +6,14 @@ import "github.com/multiversx/mx-chain-core-go/data/outport"
 type OutportBlockCache interface {
 	Add(outportBlock *outport.OutportBlock) error
 	Extract(headerHash []byte) (*outport.OutportBlock, error)
+	Close() error
 	IsInterfaceNil() bool
 }
 
 // DataProcessor defines a payload processor for incoming ws data
 type DataProcessor interface {
 	ProcessPayload(payload []byte, topic string, version uint32) error
+	RegisterHandler(topic string, version uint32, handler HandlerFunc) error
 	Close() error
 	IsInterfaceNil() bool
 }
@@ -0,0 +1,215 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ArgsLevelDBOutportBlockCache is a struct placeholder for args needed to create a LevelDB backed outport block cache.
+// A zero compaction interval disables the periodic compaction
+type ArgsLevelDBOutportBlockCache struct {
+	Path               string
+	Marshaller         marshal.Marshalizer
+	Hasher             hashing.Hasher
+	CompactionInterval time.Duration
+}
+
+type levelDBOutportBlockCache struct {
+	db         *leveldb.DB
+	marshaller marshal.Marshalizer
+	hasher     hashing.Hasher
+	mutDB      sync.Mutex
+	isClosed   bool
+	cancel     context.CancelFunc
+}
+
+// NewLevelDBOutportBlockCache creates an outport block cache persisted in a LevelDB database, so that saved blocks
+// which were not yet finalized survive restarts. Each write is synced to disk
+func NewLevelDBOutportBlockCache(args ArgsLevelDBOutportBlockCache) (*levelDBOutportBlockCache, error) {
+	if len(args.Path) == 0 {
+		return nil, errEmptyCachePath
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if check.IfNil(args.Hasher) {
+		return nil, errNilHasher
+	}
+	if args.CompactionInterval < 0 {
+		return nil, errInvalidCompactionInterval
+	}
+
+	db, err := openLevelDB(args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache := &levelDBOutportBlockCache{
+		db:         db,
+		marshaller: args.Marshaller,
+		hasher:     args.Hasher,
+		cancel:     cancel,
+	}
+
+	if args.CompactionInterval > 0 {
+		go cache.compactPeriodically(ctx, args.CompactionInterval)
+	}
+
+	log.Info("opened persistent outport block cache", "path", args.Path)
+
+	return cache, nil
+}
+
+func openLevelDB(path string) (*leveldb.DB, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if errors.IsCorrupted(err) {
+		log.Warn("persistent outport block cache is corrupted, recovering", "path", path, "error", err.Error())
+		db, err = leveldb.RecoverFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w while opening the persistent outport block cache at %s", err, path)
+	}
+
+	return db, nil
+}
+
+// Add will persist the block, if not nil and if the hash doesn't already exist.
+// Adding the same content again is a no-op, while adding a different content for an existing hash returns error
+func (cache *levelDBOutportBlockCache) Add(outportBlock *outport.OutportBlock) error {
+	if outportBlock == nil || outportBlock.BlockData == nil {
+		return errNilOutportBlock
+	}
+
+	blockBytes, err := cache.marshaller.Marshal(outportBlock)
+	if err != nil {
+		return err
+	}
+
+	cache.mutDB.Lock()
+	defer cache.mutDB.Unlock()
+
+	hash := outportBlock.BlockData.HeaderHash
+	cachedBlockBytes, err := cache.db.Get(hash, nil)
+	if err == leveldb.ErrNotFound {
+		return cache.db.Put(hash, blockBytes, &opt.WriteOptions{Sync: true})
+	}
+	if err != nil {
+		return err
+	}
+
+	cachedBlock := &outport.OutportBlock{}
+	err = cache.marshaller.Unmarshal(cachedBlock, cachedBlockBytes)
+	if err != nil {
+		return err
+	}
+
+	contentHash, err := computeContentHash(cache.marshaller, cache.hasher, outportBlock)
+	if err != nil {
+		return err
+	}
+	cachedContentHash, err := computeContentHash(cache.marshaller, cache.hasher, cachedBlock)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(cachedContentHash, contentHash) {
+		return fmt.Errorf("%w, hash: %s, cached content hash: %s, received content hash: %s",
+			errConflictingOutportBlock,
+			hex.EncodeToString(hash),
+			hex.EncodeToString(cachedContentHash),
+			hex.EncodeToString(contentHash),
+		)
+	}
+
+	return nil
+}
+
+// Extract will extract the outport block specified by the header hash, if exists. Otherwise, returns error
+func (cache *levelDBOutportBlockCache) Extract(headerHash []byte) (*outport.OutportBlock, error) {
+	cache.mutDB.Lock()
+	defer cache.mutDB.Unlock()
+
+	blockBytes, err := cache.db.Get(headerHash, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, fmt.Errorf("%w for header hash: %s",
+			errOutportBlockNotFound, hex.EncodeToString(headerHash))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	outportBlock := &outport.OutportBlock{}
+	err = cache.marshaller.Unmarshal(outportBlock, blockBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cache.db.Delete(headerHash, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return outportBlock, nil
+}
+
+func (cache *levelDBOutportBlockCache) compactPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cache.compact()
+		}
+	}
+}
+
+// compact runs under the database mutex, since closing the database during a compaction is not safe
+func (cache *levelDBOutportBlockCache) compact() {
+	cache.mutDB.Lock()
+	defer cache.mutDB.Unlock()
+
+	if cache.isClosed {
+		return
+	}
+
+	err := cache.db.CompactRange(util.Range{})
+	if err != nil {
+		log.Warn("could not compact persistent outport block cache", "error", err.Error())
+	}
+}
+
+// Close will stop the periodic compaction and close the database
+func (cache *levelDBOutportBlockCache) Close() error {
+	cache.cancel()
+
+	cache.mutDB.Lock()
+	defer cache.mutDB.Unlock()
+
+	if cache.isClosed {
+		return nil
+	}
+
+	cache.isClosed = true
+	return cache.db.Close()
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (cache *levelDBOutportBlockCache) IsInterfaceNil() bool {
+	return cache == nil
+}
@@ -0,0 +1,173 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createLevelDBOutportBlockCacheArgs(t *testing.T) ArgsLevelDBOutportBlockCache {
+	return ArgsLevelDBOutportBlockCache{
+		Path:       t.TempDir(),
+		Marshaller: &testscommon.MarshallerMock{},
+		Hasher:     sha256.NewSha256(),
+	}
+}
+
+func TestNewLevelDBOutportBlockCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		cache, err := NewLevelDBOutportBlockCache(createLevelDBOutportBlockCacheArgs(t))
+		require.Nil(t, err)
+		require.False(t, check.IfNil(cache))
+		require.Nil(t, cache.Close())
+	})
+
+	t.Run("empty path, should return error", func(t *testing.T) {
+		args := createLevelDBOutportBlockCacheArgs(t)
+		args.Path = ""
+		cache, err := NewLevelDBOutportBlockCache(args)
+		require.Equal(t, errEmptyCachePath, err)
+		require.Nil(t, cache)
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createLevelDBOutportBlockCacheArgs(t)
+		args.Marshaller = nil
+		cache, err := NewLevelDBOutportBlockCache(args)
+		require.Equal(t, errNilMarshaller, err)
+		require.Nil(t, cache)
+	})
+
+	t.Run("nil hasher, should return error", func(t *testing.T) {
+		args := createLevelDBOutportBlockCacheArgs(t)
+		args.Hasher = nil
+		cache, err := NewLevelDBOutportBlockCache(args)
+		require.Equal(t, errNilHasher, err)
+		require.Nil(t, cache)
+	})
+
+	t.Run("negative compaction interval, should return error", func(t *testing.T) {
+		args := createLevelDBOutportBlockCacheArgs(t)
+		args.CompactionInterval = -time.Second
+		cache, err := NewLevelDBOutportBlockCache(args)
+		require.Equal(t, errInvalidCompactionInterval, err)
+		require.Nil(t, cache)
+	})
+
+	t.Run("database already opened, should return error", func(t *testing.T) {
+		args := createLevelDBOutportBlockCacheArgs(t)
+		cache, _ := NewLevelDBOutportBlockCache(args)
+		defer func() {
+			_ = cache.Close()
+		}()
+
+		otherCache, err := NewLevelDBOutportBlockCache(args)
+		require.NotNil(t, err)
+		require.Nil(t, otherCache)
+	})
+}
+
+func TestLevelDBOutportBlockCache_AddExtract(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := NewLevelDBOutportBlockCache(createLevelDBOutportBlockCacheArgs(t))
+	defer func() {
+		_ = cache.Close()
+	}()
+
+	err := cache.Add(nil)
+	require.Equal(t, errNilOutportBlock, err)
+	err = cache.Add(&outport.OutportBlock{})
+	require.Equal(t, errNilOutportBlock, err)
+
+	h1 := []byte("h1")
+	bl1 := &outport.OutportBlock{
+		BlockData:       &outport.BlockData{HeaderHash: h1, HeaderBytes: []byte("header")},
+		AlteredAccounts: map[string]*alteredAccount.AlteredAccount{"addr": {Address: "addr", Nonce: 4}},
+	}
+	err = cache.Add(bl1)
+	require.Nil(t, err)
+
+	// identical re-sent block is accepted
+	err = cache.Add(&outport.OutportBlock{
+		BlockData:       &outport.BlockData{HeaderHash: h1, HeaderBytes: []byte("header")},
+		AlteredAccounts: map[string]*alteredAccount.AlteredAccount{"addr": {Address: "addr", Nonce: 4}},
+	})
+	require.Nil(t, err)
+
+	// node local fields are not compared
+	err = cache.Add(&outport.OutportBlock{
+		BlockData:              &outport.BlockData{HeaderHash: h1, HeaderBytes: []byte("header")},
+		HighestFinalBlockNonce: 7,
+	})
+	require.Nil(t, err)
+
+	err = cache.Add(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h1, HeaderBytes: []byte("other header")}})
+	require.ErrorIs(t, err, errConflictingOutportBlock)
+
+	rcvBl1, err := cache.Extract(h1)
+	require.Nil(t, err)
+	require.Equal(t, bl1, rcvBl1)
+
+	rcvBl1, err = cache.Extract(h1)
+	require.Nil(t, rcvBl1)
+	requireErrIsBlockNotFound(t, err, h1)
+}
+
+func TestLevelDBOutportBlockCache_BlocksSurviveRestarts(t *testing.T) {
+	t.Parallel()
+
+	args := createLevelDBOutportBlockCacheArgs(t)
+	cache, _ := NewLevelDBOutportBlockCache(args)
+
+	h1 := []byte("h1")
+	h2 := []byte("h2")
+	bl1 := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h1}}
+	bl2 := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h2}}
+	require.Nil(t, cache.Add(bl1))
+	require.Nil(t, cache.Add(bl2))
+
+	_, err := cache.Extract(h1)
+	require.Nil(t, err)
+	require.Nil(t, cache.Close())
+	require.Nil(t, cache.Close())
+
+	cache, err = NewLevelDBOutportBlockCache(args)
+	require.Nil(t, err)
+	defer func() {
+		_ = cache.Close()
+	}()
+
+	_, err = cache.Extract(h1)
+	requireErrIsBlockNotFound(t, err, h1)
+
+	rcvBl2, err := cache.Extract(h2)
+	require.Nil(t, err)
+	require.Equal(t, bl2, rcvBl2)
+}
+
+func TestLevelDBOutportBlockCache_PeriodicCompaction(t *testing.T) {
+	t.Parallel()
+
+	args := createLevelDBOutportBlockCacheArgs(t)
+	args.CompactionInterval = 5 * time.Millisecond
+	cache, _ := NewLevelDBOutportBlockCache(args)
+
+	hash := []byte("hash")
+	for i := 0; i < 100; i++ {
+		require.Nil(t, cache.Add(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: hash}}))
+		_, err := cache.Extract(hash)
+		require.Nil(t, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	require.Nil(t, cache.Close())
+}
@@ -1,47 +1,118 @@
 package indexer
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"sync"
 
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/hashing/blake2b"
+	"github.com/multiversx/mx-chain-core-go/marshal"
 )
 
+// ArgsOutportBlockCache is a struct placeholder for args needed to create an outport block cache
+type ArgsOutportBlockCache struct {
+	Marshaller marshal.Marshalizer
+	Hasher     hashing.Hasher
+}
+
+type cachedOutportBlock struct {
+	outportBlock *outport.OutportBlock
+	contentHash  []byte
+}
+
 type outportBlockCache struct {
-	cache      map[string]*outport.OutportBlock
+	marshaller marshal.Marshalizer
+	hasher     hashing.Hasher
+	cache      map[string]*cachedOutportBlock
 	cacheMutex sync.RWMutex
 }
 
-// NewOutportBlockCache creates a new LRU cache able to store *outport.OutportBlock
+// NewOutportBlockCache creates a new cache able to store *outport.OutportBlock, comparing the blocks contents with
+// the default marshaller and hasher
 func NewOutportBlockCache() *outportBlockCache {
+	cache, _ := NewOutportBlockCacheWithArgs(ArgsOutportBlockCache{
+		Marshaller: &marshal.GogoProtoMarshalizer{},
+		Hasher:     blake2b.NewBlake2b(),
+	})
+
+	return cache
+}
+
+// NewOutportBlockCacheWithArgs creates a new cache able to store *outport.OutportBlock. Blocks are identified by
+// header hash and their content is compared using the hash of their marshalled header and body
+func NewOutportBlockCacheWithArgs(args ArgsOutportBlockCache) (*outportBlockCache, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if check.IfNil(args.Hasher) {
+		return nil, errNilHasher
+	}
+
 	return &outportBlockCache{
-		cache:      make(map[string]*outport.OutportBlock),
+		marshaller: args.Marshaller,
+		hasher:     args.Hasher,
+		cache:      make(map[string]*cachedOutportBlock),
 		cacheMutex: sync.RWMutex{},
-	}
+	}, nil
 }
 
-// Add will add the block to internal cache, if not nil and if the hash doesn't already exist
-// If full, it will evict the oldest entry in cache
+// Add will add the block to internal cache, if not nil and if the hash doesn't already exist.
+// Adding the same content again is a no-op, while adding a different content for an existing hash returns error
 func (obc *outportBlockCache) Add(outportBlock *outport.OutportBlock) error {
 	if outportBlock == nil || outportBlock.BlockData == nil {
 		return errNilOutportBlock
 	}
 
+	contentHash, err := computeContentHash(obc.marshaller, obc.hasher, outportBlock)
+	if err != nil {
+		return err
+	}
+
 	obc.cacheMutex.Lock()
 	defer obc.cacheMutex.Unlock()
 
 	hash := outportBlock.BlockData.HeaderHash
 	hashStr := string(hash)
-	_, exists := obc.cache[hashStr]
-	if exists {
-		return fmt.Errorf("%w, hash: %s", errOutportBlockAlreadyExists, hex.EncodeToString(hash))
+	cachedBlock, exists := obc.cache[hashStr]
+	if !exists {
+		obc.cache[hashStr] = &cachedOutportBlock{
+			outportBlock: outportBlock,
+			contentHash:  contentHash,
+		}
+		return nil
+	}
+
+	if !bytes.Equal(cachedBlock.contentHash, contentHash) {
+		return fmt.Errorf("%w, hash: %s, cached content hash: %s, received content hash: %s",
+			errConflictingOutportBlock,
+			hex.EncodeToString(hash),
+			hex.EncodeToString(cachedBlock.contentHash),
+			hex.EncodeToString(contentHash),
+		)
 	}
 
-	obc.cache[hashStr] = outportBlock
 	return nil
 }
 
+// computeContentHash hashes only the consensus data of the block, its header and body, so that node local fields,
+// such as the highest final block nonce, do not make the same block received from different observers conflict
+func computeContentHash(marshaller marshal.Marshalizer, hasher hashing.Hasher, outportBlock *outport.OutportBlock) ([]byte, error) {
+	consensusData := &outport.BlockData{
+		ShardID:     outportBlock.BlockData.ShardID,
+		HeaderBytes: outportBlock.BlockData.HeaderBytes,
+		HeaderType:  outportBlock.BlockData.HeaderType,
+		HeaderHash:  outportBlock.BlockData.HeaderHash,
+		Body:        outportBlock.BlockData.Body,
+	}
+
+	return core.CalculateHash(marshaller, hasher, consensusData)
+}
+
 // Extract will extract the outport block specified by the header hash, if exists. Otherwise, returns error
 func (obc *outportBlockCache) Extract(headerHash []byte) (*outport.OutportBlock, error) {
 	hashStr := string(headerHash)
@@ -49,7 +120,7 @@ func (obc *outportBlockCache) Extract(headerHash []byte) (*outport.OutportBlock,
 	obc.cacheMutex.Lock()
 	defer obc.cacheMutex.Unlock()
 
-	outportBlock, exists := obc.cache[hashStr]
+	cachedBlock, exists := obc.cache[hashStr]
 	if !exists {
 		return nil, fmt.Errorf("%w for header hash: %s",
 			errOutportBlockNotFound, hex.EncodeToString(headerHash))
@@ -57,7 +128,12 @@ func (obc *outportBlockCache) Extract(headerHash []byte) (*outport.OutportBlock,
 
 	delete(obc.cache, hashStr)
 
-	return outportBlock, nil
+	return cachedBlock.outportBlock, nil
+}
+
+// Close does nothing, as the in memory cache holds no resources
+func (obc *outportBlockCache) Close() error {
+	return nil
 }
 
 // IsInterfaceNil checks if the underlying pointer is nil
@@ -9,19 +9,67 @@ import (
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
 	"github.com/stretchr/testify/require"
 )
 
+func createOutportBlockCache() *outportBlockCache {
+	cache, _ := NewOutportBlockCacheWithArgs(ArgsOutportBlockCache{
+		Marshaller: &testscommon.MarshallerMock{},
+		Hasher:     sha256.NewSha256(),
+	})
+
+	return cache
+}
+
 func requireErrIsBlockNotFound(t *testing.T, err error, hash []byte) {
 	require.NotNil(t, err)
 	require.True(t, strings.Contains(err.Error(), errOutportBlockNotFound.Error()))
 	require.True(t, strings.Contains(err.Error(), hex.EncodeToString(hash)))
 }
 
+func TestNewOutportBlockCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		cache, err := NewOutportBlockCacheWithArgs(ArgsOutportBlockCache{
+			Marshaller: &testscommon.MarshallerMock{},
+			Hasher:     sha256.NewSha256(),
+		})
+		require.Nil(t, err)
+		require.False(t, check.IfNil(cache))
+	})
+
+	t.Run("default marshaller and hasher should work", func(t *testing.T) {
+		cache := NewOutportBlockCache()
+		require.False(t, check.IfNil(cache))
+		require.Nil(t, cache.Add(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: []byte("hash")}}))
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		cache, err := NewOutportBlockCacheWithArgs(ArgsOutportBlockCache{
+			Marshaller: nil,
+			Hasher:     sha256.NewSha256(),
+		})
+		require.Equal(t, errNilMarshaller, err)
+		require.Nil(t, cache)
+	})
+
+	t.Run("nil hasher, should return error", func(t *testing.T) {
+		cache, err := NewOutportBlockCacheWithArgs(ArgsOutportBlockCache{
+			Marshaller: &testscommon.MarshallerMock{},
+			Hasher:     nil,
+		})
+		require.Equal(t, errNilHasher, err)
+		require.Nil(t, cache)
+	})
+}
+
 func TestOutportBlockCache_Add_Get(t *testing.T) {
 	t.Parallel()
 
-	cache := NewOutportBlockCache()
+	cache := createOutportBlockCache()
 	require.False(t, check.IfNil(cache))
 
 	h1 := []byte("h1")
@@ -62,16 +110,15 @@ func TestOutportBlockCache_Add_Get(t *testing.T) {
 	require.Nil(t, rcvBl2)
 	requireErrIsBlockNotFound(t, err, h2)
 
-	require.Equal(t, map[string]*outport.OutportBlock{
-		string(h3): bl3,
-		string(h4): bl4,
-	}, cache.cache)
+	require.Len(t, cache.cache, 2)
+	require.True(t, bl3 == cache.cache[string(h3)].outportBlock)
+	require.True(t, bl4 == cache.cache[string(h4)].outportBlock)
 }
 
-func TestOutportBlockCache_AddErrorCases(t *testing.T) {
+func TestOutportBlockCache_AddDuplicatesAndErrorCases(t *testing.T) {
 	t.Parallel()
 
-	cache := NewOutportBlockCache()
+	cache := createOutportBlockCache()
 
 	err := cache.Add(nil)
 	require.Equal(t, errNilOutportBlock, err)
@@ -85,20 +132,27 @@ func TestOutportBlockCache_AddErrorCases(t *testing.T) {
 	err = cache.Add(bl1)
 	require.Nil(t, err)
 
-	err = cache.Add(bl1)
-	require.Error(t, err)
-	require.True(t, strings.Contains(err.Error(), errOutportBlockAlreadyExists.Error()))
+	// identical re-sent block is accepted and the cached one is kept
+	err = cache.Add(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h1}})
+	require.Nil(t, err)
+
+	// node local fields are not compared
+	err = cache.Add(&outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h1}, HighestFinalBlockNonce: 7})
+	require.Nil(t, err)
+
+	conflictingBl1 := &outport.OutportBlock{BlockData: &outport.BlockData{HeaderHash: h1, HeaderBytes: []byte("header")}}
+	err = cache.Add(conflictingBl1)
+	require.ErrorIs(t, err, errConflictingOutportBlock)
 	require.True(t, strings.Contains(err.Error(), hex.EncodeToString(h1)))
 
-	require.Equal(t, map[string]*outport.OutportBlock{
-		string(h1): bl1,
-	}, cache.cache)
+	require.Len(t, cache.cache, 1)
+	require.True(t, bl1 == cache.cache[string(h1)].outportBlock)
 }
 
 func TestOutportBlockCache_ConcurrentOperations(t *testing.T) {
 	t.Parallel()
 
-	cache := NewOutportBlockCache()
+	cache := createOutportBlockCache()
 
 	n := 10000
 	extraElems := 10
@@ -2,59 +2,208 @@ package indexer
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	logger "github.com/multiversx/mx-chain-logger-go"
 
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
 )
 
-type handlerFunc func(marshalledData []byte) error
+var log = logger.GetOrCreate("notifier-indexer-process")
+
+// PayloadVersionV1 is the outport payload version sent by observers through the websocket driver
+const PayloadVersionV1 uint32 = 1
+
+const (
+	// UnknownTopicPolicyIgnore will silently drop payloads with unknown topics
+	UnknownTopicPolicyIgnore = "ignore"
+	// UnknownTopicPolicyLog will drop payloads with unknown topics, log them and count them per topic
+	UnknownTopicPolicyLog = "log"
+	// UnknownTopicPolicyFail will return an error for payloads with unknown topics
+	UnknownTopicPolicyFail = "fail"
+)
+
+// coreTopics are the outport topics which should all have a registered handler for a payload version to be supported
+var coreTopics = []string{
+	outport.TopicSaveBlock,
+	outport.TopicRevertIndexedBlock,
+	outport.TopicSaveRoundsInfo,
+	outport.TopicSaveValidatorsRating,
+	outport.TopicSaveValidatorsPubKeys,
+	outport.TopicSaveAccounts,
+	outport.TopicFinalizedBlock,
+}
+
+// HandlerFunc defines a handler for a received outport payload
+type HandlerFunc func(marshalledData []byte) error
+
+// TopicHandler holds a custom handler to be registered for an outport topic and payload version
+type TopicHandler struct {
+	Topic   string
+	Version uint32
+	Handler HandlerFunc
+}
+
+// ArgsPayloadProcessor is a struct placeholder for args needed to create a payload processor
+type ArgsPayloadProcessor struct {
+	Indexer            process.Indexer
+	Marshaller         marshal.Marshalizer
+	UnknownTopicPolicy string
+}
 
 type payloadProcessor struct {
-	indexer           process.Indexer
-	marshaller        marshal.Marshalizer
-	operationHandlers map[string]handlerFunc
+	indexer            process.Indexer
+	marshaller         marshal.Marshalizer
+	unknownTopicPolicy string
+
+	mutHandlers       sync.RWMutex
+	operationHandlers map[uint32]map[string]HandlerFunc
+
+	mutUnknownTopics    sync.RWMutex
+	unknownTopicsCounts map[string]uint64
 }
 
-// NewPayloadProcessor creates a new operation handler
+// NewPayloadProcessor creates a new operation handler which fails on unknown topics
 func NewPayloadProcessor(indexer process.Indexer, marshaller marshal.Marshalizer) (DataProcessor, error) {
-	if check.IfNil(marshaller) {
+	return NewPayloadProcessorWithArgs(ArgsPayloadProcessor{
+		Indexer:    indexer,
+		Marshaller: marshaller,
+	})
+}
+
+// NewPayloadProcessorWithArgs creates a new operation handler with the provided unknown topic policy
+func NewPayloadProcessorWithArgs(args ArgsPayloadProcessor) (DataProcessor, error) {
+	if check.IfNil(args.Marshaller) {
 		return nil, errNilMarshaller
 	}
-	if check.IfNil(indexer) {
+	if check.IfNil(args.Indexer) {
 		return nil, errNilIndexer
 	}
 
-	opHandler := &payloadProcessor{
-		indexer:    indexer,
-		marshaller: marshaller,
+	unknownTopicPolicy, err := getUnknownTopicPolicy(args.UnknownTopicPolicy)
+	if err != nil {
+		return nil, err
 	}
 
-	opHandler.operationHandlers = map[string]handlerFunc{
-		outport.TopicSaveBlock:             opHandler.saveBlock,
-		outport.TopicRevertIndexedBlock:    noOpHandler,
-		outport.TopicSaveRoundsInfo:        noOpHandler,
-		outport.TopicSaveValidatorsRating:  noOpHandler,
-		outport.TopicSaveValidatorsPubKeys: noOpHandler,
-		outport.TopicSaveAccounts:          noOpHandler,
-		outport.TopicFinalizedBlock:        opHandler.finalizedBlock,
+	opHandler := &payloadProcessor{
+		indexer:             args.Indexer,
+		marshaller:          args.Marshaller,
+		unknownTopicPolicy:  unknownTopicPolicy,
+		operationHandlers:   make(map[uint32]map[string]HandlerFunc),
+		unknownTopicsCounts: make(map[string]uint64),
 	}
 
+	opHandler.registerV1Handlers()
+
 	return opHandler, nil
 }
 
-// ProcessPayload executes the handler func that will index data for requested operation type, if exists
-func (pp *payloadProcessor) ProcessPayload(payload []byte, topic string, _ uint32) error {
-	handlerFunc, found := pp.operationHandlers[topic]
+// getUnknownTopicPolicy defaults to failing on unknown topics, if no policy is provided
+func getUnknownTopicPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return UnknownTopicPolicyFail, nil
+	case UnknownTopicPolicyIgnore, UnknownTopicPolicyLog, UnknownTopicPolicyFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %s", errInvalidUnknownTopicPolicy, policy)
+	}
+}
+
+func (pp *payloadProcessor) registerV1Handlers() {
+	pp.operationHandlers[PayloadVersionV1] = map[string]HandlerFunc{
+		outport.TopicSaveBlock:             pp.saveBlock,
+		outport.TopicRevertIndexedBlock:    pp.revertIndexedBlock,
+		outport.TopicSaveRoundsInfo:        pp.saveRoundsInfo,
+		outport.TopicSaveValidatorsRating:  pp.saveValidatorsRating,
+		outport.TopicSaveValidatorsPubKeys: pp.saveValidatorsPubKeys,
+		outport.TopicSaveAccounts:          pp.saveAccounts,
+		outport.TopicFinalizedBlock:        pp.finalizedBlock,
+	}
+}
+
+// RegisterHandler will register a custom handler for the provided topic and payload version. It will return an
+// error if a handler is already registered for the same topic and version. A payload version is supported only once
+// handlers are registered for all its core topics, so that payloads of a version for which only custom topics are
+// handled are still rejected as unsupported
+func (pp *payloadProcessor) RegisterHandler(topic string, version uint32, handler HandlerFunc) error {
+	if len(topic) == 0 {
+		return errEmptyTopic
+	}
+	if handler == nil {
+		return errNilHandlerFunc
+	}
+
+	pp.mutHandlers.Lock()
+	defer pp.mutHandlers.Unlock()
+
+	versionHandlers, found := pp.operationHandlers[version]
 	if !found {
-		return fmt.Errorf("%w, operation type = %s", errOperationTypeInvalid, topic)
+		versionHandlers = make(map[string]HandlerFunc)
+		pp.operationHandlers[version] = versionHandlers
+	}
+
+	_, found = versionHandlers[topic]
+	if found {
+		return fmt.Errorf("%w, operation type = %s, version = %d", errHandlerAlreadyRegistered, topic, version)
+	}
+
+	versionHandlers[topic] = handler
+	log.Debug("registered payload handler", "topic", topic, "version", version)
+
+	return nil
+}
+
+// ProcessPayload executes the handler func registered for the payload version that will index data for requested
+// operation type, if exists
+func (pp *payloadProcessor) ProcessPayload(payload []byte, topic string, version uint32) error {
+	pp.mutHandlers.RLock()
+	versionHandlers := pp.operationHandlers[version]
+	versionSupported := isVersionSupported(versionHandlers)
+	handlerFunc, topicFound := versionHandlers[topic]
+	pp.mutHandlers.RUnlock()
+
+	if !versionSupported {
+		return fmt.Errorf("%w, version = %d, operation type = %s", errPayloadVersionNotSupported, version, topic)
+	}
+	if !topicFound {
+		return pp.handleUnknownTopic(topic, version)
 	}
 
 	return handlerFunc(payload)
 }
 
+func isVersionSupported(versionHandlers map[string]HandlerFunc) bool {
+	for _, topic := range coreTopics {
+		_, found := versionHandlers[topic]
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (pp *payloadProcessor) handleUnknownTopic(topic string, version uint32) error {
+	switch pp.unknownTopicPolicy {
+	case UnknownTopicPolicyIgnore:
+		return nil
+	case UnknownTopicPolicyLog:
+		pp.mutUnknownTopics.Lock()
+		pp.unknownTopicsCounts[topic]++
+		count := pp.unknownTopicsCounts[topic]
+		pp.mutUnknownTopics.Unlock()
+
+		log.Warn("received payload with unknown operation type, ignoring it", "topic", topic, "version", version, "count", count)
+		return nil
+	default:
+		return fmt.Errorf("%w, operation type = %s, version = %d", errOperationTypeInvalid, topic, version)
+	}
+}
+
 func (pp *payloadProcessor) saveBlock(marshalledData []byte) error {
 	outportBlock := &outport.OutportBlock{}
 	err := pp.marshaller.Unmarshal(outportBlock, marshalledData)
@@ -65,8 +214,14 @@ func (pp *payloadProcessor) saveBlock(marshalledData []byte) error {
 	return pp.indexer.SaveBlock(outportBlock)
 }
 
-func noOpHandler(_ []byte) error {
-	return nil
+func (pp *payloadProcessor) revertIndexedBlock(marshalledData []byte) error {
+	blockData := &outport.BlockData{}
+	err := pp.marshaller.Unmarshal(blockData, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return pp.indexer.RevertIndexedBlock(blockData)
 }
 
 func (pp *payloadProcessor) finalizedBlock(marshalledData []byte) error {
@@ -79,12 +234,52 @@ func (pp *payloadProcessor) finalizedBlock(marshalledData []byte) error {
 	return pp.indexer.FinalizedBlock(finalizedBlock)
 }
 
+func (pp *payloadProcessor) saveAccounts(marshalledData []byte) error {
+	accounts := &outport.Accounts{}
+	err := pp.marshaller.Unmarshal(accounts, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return pp.indexer.SaveAccounts(accounts)
+}
+
+func (pp *payloadProcessor) saveValidatorsPubKeys(marshalledData []byte) error {
+	validatorsPubKeys := &outport.ValidatorsPubKeys{}
+	err := pp.marshaller.Unmarshal(validatorsPubKeys, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return pp.indexer.SaveValidatorsPubKeys(validatorsPubKeys)
+}
+
+func (pp *payloadProcessor) saveValidatorsRating(marshalledData []byte) error {
+	validatorsRating := &outport.ValidatorsRating{}
+	err := pp.marshaller.Unmarshal(validatorsRating, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return pp.indexer.SaveValidatorsRating(validatorsRating)
+}
+
+func (pp *payloadProcessor) saveRoundsInfo(marshalledData []byte) error {
+	roundsInfo := &outport.RoundsInfo{}
+	err := pp.marshaller.Unmarshal(roundsInfo, marshalledData)
+	if err != nil {
+		return err
+	}
+
+	return pp.indexer.SaveRoundsInfo(roundsInfo)
+}
+
 // IsInterfaceNil checks if the underlying pointer is nil
 func (pp *payloadProcessor) IsInterfaceNil() bool {
 	return pp == nil
 }
 
-// Close does nothing for now
+// Close will close the indexer
 func (pp *payloadProcessor) Close() error {
-	return nil
+	return pp.indexer.Close()
 }
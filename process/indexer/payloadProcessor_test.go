@@ -1,38 +1,93 @@
 package indexer
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
 	"github.com/stretchr/testify/require"
 )
 
+func createPayloadProcessorArgs() ArgsPayloadProcessor {
+	return ArgsPayloadProcessor{
+		Indexer:            &testscommon.IndexerStub{},
+		Marshaller:         &testscommon.MarshallerMock{},
+		UnknownTopicPolicy: UnknownTopicPolicyFail,
+	}
+}
+
 func TestNewOperationHandler(t *testing.T) {
 	t.Parallel()
 
 	t.Run("should work", func(t *testing.T) {
-		payloadProc, err := NewPayloadProcessor(&testscommon.IndexerStub{}, &testscommon.MarshallerMock{})
+		wasIndexerClosed := false
+		args := createPayloadProcessorArgs()
+		args.Indexer = &testscommon.IndexerStub{
+			CloseCalled: func() error {
+				wasIndexerClosed = true
+				return nil
+			},
+		}
+		payloadProc, err := NewPayloadProcessorWithArgs(args)
 		require.False(t, payloadProc.IsInterfaceNil())
 		require.NotNil(t, payloadProc)
 		require.Nil(t, err)
 
 		err = payloadProc.Close()
 		require.Nil(t, err)
+		require.True(t, wasIndexerClosed)
 	})
 
 	t.Run("nil indexer, should return error", func(t *testing.T) {
-		payloadProc, err := NewPayloadProcessor(nil, &testscommon.MarshallerMock{})
+		args := createPayloadProcessorArgs()
+		args.Indexer = nil
+		payloadProc, err := NewPayloadProcessorWithArgs(args)
 		require.Nil(t, payloadProc)
 		require.Equal(t, errNilIndexer, err)
 	})
 
 	t.Run("nil marshaller, should return error", func(t *testing.T) {
-		payloadProc, err := NewPayloadProcessor(&testscommon.IndexerStub{}, nil)
+		args := createPayloadProcessorArgs()
+		args.Marshaller = nil
+		payloadProc, err := NewPayloadProcessorWithArgs(args)
 		require.Nil(t, payloadProc)
 		require.Equal(t, errNilMarshaller, err)
 	})
+
+	t.Run("invalid unknown topic policy, should return error", func(t *testing.T) {
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = "drop"
+		payloadProc, err := NewPayloadProcessorWithArgs(args)
+		require.Nil(t, payloadProc)
+		require.True(t, errors.Is(err, errInvalidUnknownTopicPolicy))
+		require.True(t, strings.Contains(err.Error(), "drop"))
+	})
+
+	t.Run("empty unknown topic policy, should default to fail", func(t *testing.T) {
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = ""
+		payloadProc, err := NewPayloadProcessorWithArgs(args)
+		require.Nil(t, err)
+
+		err = payloadProc.ProcessPayload([]byte("payload"), "0xFFFFF", PayloadVersionV1)
+		require.True(t, errors.Is(err, errOperationTypeInvalid))
+	})
+
+	t.Run("indexer and marshaller constructor should fail on unknown topics", func(t *testing.T) {
+		payloadProc, err := NewPayloadProcessor(nil, &testscommon.MarshallerMock{})
+		require.Nil(t, payloadProc)
+		require.Equal(t, errNilIndexer, err)
+
+		payloadProc, err = NewPayloadProcessor(&testscommon.IndexerStub{}, &testscommon.MarshallerMock{})
+		require.Nil(t, err)
+
+		err = payloadProc.ProcessPayload([]byte("payload"), "0xFFFFF", PayloadVersionV1)
+		require.True(t, errors.Is(err, errOperationTypeInvalid))
+	})
 }
 
 func TestOperationHandler_GetOperationHandler(t *testing.T) {
@@ -55,13 +110,13 @@ func TestOperationHandler_GetOperationHandler(t *testing.T) {
 			},
 		}
 
-		payloadProc, _ := NewPayloadProcessor(indexerStub, marshaller)
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
 
-		err := payloadProc.ProcessPayload(blockBytes, outport.TopicSaveBlock, 0)
+		err := payloadProc.ProcessPayload(blockBytes, outport.TopicSaveBlock, PayloadVersionV1)
 		require.True(t, saveBlockCalled)
 		require.Nil(t, err)
 
-		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveBlock, 0)
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveBlock, PayloadVersionV1)
 		require.NotNil(t, err)
 	})
 
@@ -80,44 +135,360 @@ func TestOperationHandler_GetOperationHandler(t *testing.T) {
 			},
 		}
 
-		payloadProc, _ := NewPayloadProcessor(indexerStub, marshaller)
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
 
-		err := payloadProc.ProcessPayload(blockBytes, outport.TopicFinalizedBlock, 0)
+		err := payloadProc.ProcessPayload(blockBytes, outport.TopicFinalizedBlock, PayloadVersionV1)
 		require.True(t, finalizedBlockCalled)
 		require.Nil(t, err)
 
-		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicFinalizedBlock, 0)
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicFinalizedBlock, PayloadVersionV1)
 		require.NotNil(t, err)
 	})
 
-	t.Run("no operation handlers", func(t *testing.T) {
+	t.Run("revert indexed block", func(t *testing.T) {
 		t.Parallel()
 
-		payloadProc, _ := NewPayloadProcessor(&testscommon.IndexerStub{}, marshaller)
+		blockData := &outport.BlockData{HeaderHash: []byte("hash")}
+		blockDataBytes, _ := marshaller.Marshal(blockData)
+		revertCalled := false
 
-		err := payloadProc.ProcessPayload([]byte("payload"), outport.TopicRevertIndexedBlock, 0)
+		indexerStub := &testscommon.IndexerStub{
+			RevertIndexedBlockCalled: func(receivedBlockData *outport.BlockData) error {
+				revertCalled = true
+				require.Equal(t, blockData, receivedBlockData)
+				return nil
+			},
+		}
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		err := payloadProc.ProcessPayload(blockDataBytes, outport.TopicRevertIndexedBlock, PayloadVersionV1)
 		require.Nil(t, err)
+		require.True(t, revertCalled)
+
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicRevertIndexedBlock, PayloadVersionV1)
+		require.NotNil(t, err)
+	})
+
+	t.Run("save rounds info", func(t *testing.T) {
+		t.Parallel()
+
+		roundsInfo := &outport.RoundsInfo{
+			ShardID: 1,
+			RoundsInfo: []*outport.RoundInfo{
+				{Round: 4, BlockWasProposed: true, Epoch: 2, Timestamp: 100},
+			},
+		}
+		roundsInfoBytes, _ := marshaller.Marshal(roundsInfo)
+		saveRoundsInfoCalled := false
 
-		err = payloadProc.ProcessPayload([]byte("payload"), outport.TopicSaveRoundsInfo, 0)
+		indexerStub := &testscommon.IndexerStub{
+			SaveRoundsInfoCalled: func(receivedRoundsInfo *outport.RoundsInfo) error {
+				saveRoundsInfoCalled = true
+				require.Equal(t, roundsInfo, receivedRoundsInfo)
+				return nil
+			},
+		}
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		err := payloadProc.ProcessPayload(roundsInfoBytes, outport.TopicSaveRoundsInfo, PayloadVersionV1)
+		require.True(t, saveRoundsInfoCalled)
 		require.Nil(t, err)
 
-		err = payloadProc.ProcessPayload([]byte("payload"), outport.TopicSaveValidatorsRating, 0)
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveRoundsInfo, PayloadVersionV1)
+		require.NotNil(t, err)
+	})
+
+	t.Run("save validators pub keys", func(t *testing.T) {
+		t.Parallel()
+
+		validatorsPubKeys := &outport.ValidatorsPubKeys{
+			ShardID: 1,
+			Epoch:   2,
+			ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+				1: {Keys: [][]byte{[]byte("pk1"), []byte("pk2")}},
+			},
+		}
+		pubKeysBytes, _ := marshaller.Marshal(validatorsPubKeys)
+		savePubKeysCalled := false
+
+		indexerStub := &testscommon.IndexerStub{
+			SaveValidatorsPubKeysCalled: func(receivedPubKeys *outport.ValidatorsPubKeys) error {
+				savePubKeysCalled = true
+				require.Equal(t, validatorsPubKeys, receivedPubKeys)
+				return nil
+			},
+		}
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		err := payloadProc.ProcessPayload(pubKeysBytes, outport.TopicSaveValidatorsPubKeys, PayloadVersionV1)
+		require.True(t, savePubKeysCalled)
 		require.Nil(t, err)
 
-		err = payloadProc.ProcessPayload([]byte("payload"), outport.TopicSaveValidatorsPubKeys, 0)
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveValidatorsPubKeys, PayloadVersionV1)
+		require.NotNil(t, err)
+	})
+
+	t.Run("save validators rating", func(t *testing.T) {
+		t.Parallel()
+
+		validatorsRating := &outport.ValidatorsRating{
+			ShardID: 1,
+			Epoch:   2,
+			ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{
+				{PublicKey: "pk1", Rating: 50},
+			},
+		}
+		ratingBytes, _ := marshaller.Marshal(validatorsRating)
+		saveRatingCalled := false
+
+		indexerStub := &testscommon.IndexerStub{
+			SaveValidatorsRatingCalled: func(receivedRating *outport.ValidatorsRating) error {
+				saveRatingCalled = true
+				require.Equal(t, validatorsRating, receivedRating)
+				return nil
+			},
+		}
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		err := payloadProc.ProcessPayload(ratingBytes, outport.TopicSaveValidatorsRating, PayloadVersionV1)
+		require.True(t, saveRatingCalled)
 		require.Nil(t, err)
 
-		err = payloadProc.ProcessPayload([]byte("payload"), outport.TopicSaveAccounts, 0)
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveValidatorsRating, PayloadVersionV1)
+		require.NotNil(t, err)
+	})
+
+	t.Run("save accounts", func(t *testing.T) {
+		t.Parallel()
+
+		accounts := &outport.Accounts{
+			ShardID: 1,
+			AlteredAccounts: map[string]*alteredAccount.AlteredAccount{
+				"erd1a": {Address: "erd1a", Balance: "1"},
+			},
+		}
+		accountsBytes, _ := marshaller.Marshal(accounts)
+		saveAccountsCalled := false
+
+		indexerStub := &testscommon.IndexerStub{
+			SaveAccountsCalled: func(receivedAccounts *outport.Accounts) error {
+				saveAccountsCalled = true
+				require.Equal(t, accounts, receivedAccounts)
+				return nil
+			},
+		}
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		err := payloadProc.ProcessPayload(accountsBytes, outport.TopicSaveAccounts, PayloadVersionV1)
+		require.True(t, saveAccountsCalled)
 		require.Nil(t, err)
+
+		err = payloadProc.ProcessPayload([]byte("invalid bytes"), outport.TopicSaveAccounts, PayloadVersionV1)
+		require.NotNil(t, err)
 	})
 
 	t.Run("handler not found", func(t *testing.T) {
 		t.Parallel()
 
-		payloadProc, _ := NewPayloadProcessor(&testscommon.IndexerStub{}, marshaller)
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: &testscommon.IndexerStub{}, Marshaller: marshaller})
 
-		err := payloadProc.ProcessPayload([]byte("payload"), "0xFFFFF", 0)
+		err := payloadProc.ProcessPayload([]byte("payload"), "0xFFFFF", PayloadVersionV1)
 		require.True(t, strings.Contains(err.Error(), errOperationTypeInvalid.Error()))
 		require.True(t, strings.Contains(err.Error(), "0xFFFFF"))
 	})
+
+	t.Run("unsupported payload version", func(t *testing.T) {
+		t.Parallel()
+
+		saveBlockCalled := false
+		indexerStub := &testscommon.IndexerStub{
+			SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+				saveBlockCalled = true
+				return nil
+			},
+		}
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		blockBytes, _ := marshaller.Marshal(&outport.OutportBlock{})
+		for _, version := range []uint32{0, PayloadVersionV1 + 1} {
+			err := payloadProc.ProcessPayload(blockBytes, outport.TopicSaveBlock, version)
+			require.True(t, errors.Is(err, errPayloadVersionNotSupported))
+			require.True(t, strings.Contains(err.Error(), fmt.Sprintf("version = %d", version)))
+		}
+		require.False(t, saveBlockCalled)
+	})
+
+	t.Run("handlers for multiple payload versions", func(t *testing.T) {
+		t.Parallel()
+
+		saveBlockCalled := false
+		indexerStub := &testscommon.IndexerStub{
+			SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+				saveBlockCalled = true
+				return nil
+			},
+		}
+		payloadProc, _ := NewPayloadProcessorWithArgs(ArgsPayloadProcessor{Indexer: indexerStub, Marshaller: marshaller})
+
+		v2SaveBlockCalled := false
+		for _, topic := range coreTopics {
+			handler := noOpHandler
+			if topic == outport.TopicSaveBlock {
+				handler = func(marshalledData []byte) error {
+					v2SaveBlockCalled = true
+					return nil
+				}
+			}
+			require.Nil(t, payloadProc.RegisterHandler(topic, PayloadVersionV1+1, handler))
+		}
+
+		blockBytes, _ := marshaller.Marshal(&outport.OutportBlock{})
+		err := payloadProc.ProcessPayload(blockBytes, outport.TopicSaveBlock, PayloadVersionV1+1)
+		require.Nil(t, err)
+		require.True(t, v2SaveBlockCalled)
+		require.False(t, saveBlockCalled)
+
+		err = payloadProc.ProcessPayload(blockBytes, outport.TopicSaveBlock, PayloadVersionV1)
+		require.Nil(t, err)
+		require.True(t, saveBlockCalled)
+
+		err = payloadProc.ProcessPayload(blockBytes, "newTopic", PayloadVersionV1+1)
+		require.True(t, errors.Is(err, errOperationTypeInvalid))
+	})
+
+	t.Run("version with only custom handlers is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = UnknownTopicPolicyLog
+		payloadProc, _ := NewPayloadProcessorWithArgs(args)
+
+		customHandlerCalled := false
+		err := payloadProc.RegisterHandler("newTopic", PayloadVersionV1+1, func(marshalledData []byte) error {
+			customHandlerCalled = true
+			return nil
+		})
+		require.Nil(t, err)
+
+		err = payloadProc.ProcessPayload([]byte("payload"), outport.TopicSaveBlock, PayloadVersionV1+1)
+		require.True(t, errors.Is(err, errPayloadVersionNotSupported))
+		err = payloadProc.ProcessPayload([]byte("payload"), "newTopic", PayloadVersionV1+1)
+		require.True(t, errors.Is(err, errPayloadVersionNotSupported))
+		require.False(t, customHandlerCalled)
+		require.Empty(t, payloadProc.(*payloadProcessor).unknownTopicsCounts)
+	})
+}
+
+func TestPayloadProcessor_UnknownTopicPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ignore", func(t *testing.T) {
+		t.Parallel()
+
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = UnknownTopicPolicyIgnore
+		payloadProc, _ := NewPayloadProcessorWithArgs(args)
+
+		err := payloadProc.ProcessPayload([]byte("payload"), "newTopic", PayloadVersionV1)
+		require.Nil(t, err)
+		require.Empty(t, payloadProc.(*payloadProcessor).unknownTopicsCounts)
+	})
+
+	t.Run("log and count", func(t *testing.T) {
+		t.Parallel()
+
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = UnknownTopicPolicyLog
+		payloadProc, _ := NewPayloadProcessorWithArgs(args)
+
+		err := payloadProc.ProcessPayload([]byte("payload"), "newTopic1", PayloadVersionV1)
+		require.Nil(t, err)
+		err = payloadProc.ProcessPayload([]byte("payload"), "newTopic1", PayloadVersionV1)
+		require.Nil(t, err)
+		err = payloadProc.ProcessPayload([]byte("payload"), "newTopic2", PayloadVersionV1)
+		require.Nil(t, err)
+
+		require.Equal(t, map[string]uint64{
+			"newTopic1": 2,
+			"newTopic2": 1,
+		}, payloadProc.(*payloadProcessor).unknownTopicsCounts)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(createPayloadProcessorArgs())
+
+		err := payloadProc.ProcessPayload([]byte("payload"), "newTopic", PayloadVersionV1)
+		require.True(t, errors.Is(err, errOperationTypeInvalid))
+		require.Empty(t, payloadProc.(*payloadProcessor).unknownTopicsCounts)
+	})
+
+	t.Run("unsupported version is not handled by policy", func(t *testing.T) {
+		t.Parallel()
+
+		args := createPayloadProcessorArgs()
+		args.UnknownTopicPolicy = UnknownTopicPolicyIgnore
+		payloadProc, _ := NewPayloadProcessorWithArgs(args)
+
+		err := payloadProc.ProcessPayload([]byte("payload"), "newTopic", PayloadVersionV1+1)
+		require.True(t, errors.Is(err, errPayloadVersionNotSupported))
+	})
+}
+
+func TestPayloadProcessor_RegisterHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(createPayloadProcessorArgs())
+
+		receivedPayload := make([]byte, 0)
+		err := payloadProc.RegisterHandler("newTopic", PayloadVersionV1, func(marshalledData []byte) error {
+			receivedPayload = marshalledData
+			return nil
+		})
+		require.Nil(t, err)
+
+		err = payloadProc.ProcessPayload([]byte("payload"), "newTopic", PayloadVersionV1)
+		require.Nil(t, err)
+		require.Equal(t, []byte("payload"), receivedPayload)
+	})
+
+	t.Run("invalid handlers, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(createPayloadProcessorArgs())
+
+		err := payloadProc.RegisterHandler("", PayloadVersionV1, noOpHandler)
+		require.Equal(t, errEmptyTopic, err)
+
+		err = payloadProc.RegisterHandler("newTopic", PayloadVersionV1, nil)
+		require.Equal(t, errNilHandlerFunc, err)
+	})
+
+	t.Run("already registered handler, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		payloadProc, _ := NewPayloadProcessorWithArgs(createPayloadProcessorArgs())
+
+		err := payloadProc.RegisterHandler(outport.TopicSaveBlock, PayloadVersionV1, noOpHandler)
+		require.True(t, errors.Is(err, errHandlerAlreadyRegistered))
+		require.True(t, strings.Contains(err.Error(), outport.TopicSaveBlock))
+
+		err = payloadProc.RegisterHandler("newTopic", PayloadVersionV1, noOpHandler)
+		require.Nil(t, err)
+
+		err = payloadProc.RegisterHandler("newTopic", PayloadVersionV1, noOpHandler)
+		require.True(t, errors.Is(err, errHandlerAlreadyRegistered))
+	})
+}
+
+func noOpHandler(_ []byte) error {
+	return nil
 }
@@ -1,6 +1,9 @@
 package process
 
 import (
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/data/sovereign"
 )
@@ -12,6 +15,32 @@ type SovereignNotifier interface {
 	IsInterfaceNil() bool
 }
 
+// PolicyHandlerRegisterer should register incoming header subscribers with a notification policy. Sovereign notifiers
+// supporting notification policies also implement this interface
+type PolicyHandlerRegisterer interface {
+	RegisterHandlerWithPolicy(handler IncomingHeaderSubscriber, policy NotificationPolicy) error
+	IsInterfaceNil() bool
+}
+
+// IncomingHeaderHashComputer should compute the hash of the incoming header which would be notified for an outport block
+type IncomingHeaderHashComputer interface {
+	ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error)
+	IsInterfaceNil() bool
+}
+
+// QueuingNotifier should notify finalized blocks which may be queued before being delivered to the subscribers. The
+// delivery handler is called once the block was delivered, so that the notified data is tracked only afterwards
+type QueuingNotifier interface {
+	NotifyWithDeliveryHandler(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error
+	IsInterfaceNil() bool
+}
+
+// HeartbeatNotifier should notify subscribers that the main chain feed is alive
+type HeartbeatNotifier interface {
+	NotifyHeartbeat(heartbeat *Heartbeat) error
+	IsInterfaceNil() bool
+}
+
 // ShardCoordinator should be able to compute an address' shard id
 type ShardCoordinator interface {
 	ComputeId(address []byte) uint32
@@ -24,14 +53,113 @@ type IncomingHeaderSubscriber interface {
 	IsInterfaceNil() bool
 }
 
+// IncomingHeaderSink defines a subscriber to incoming headers which writes them to an output, released on Close
+type IncomingHeaderSink interface {
+	IncomingHeaderSubscriber
+	Close() error
+}
+
+// HeadersBatchSubscriber defines a subscriber to batches of incoming headers. Subscribers registered with the
+// NotifyBatchedHeaders mode should implement this interface, since they will receive all headers in a single message
+type HeadersBatchSubscriber interface {
+	AddHeaders(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error
+	IsInterfaceNil() bool
+}
+
+// AccountsSubscriber defines a subscriber to the latest state of the subscribed accounts. If an IncomingHeaderSubscriber
+// also implements this interface, it will receive the accounts state after each incoming header
+type AccountsSubscriber interface {
+	AddAccounts(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error
+	IsInterfaceNil() bool
+}
+
+// MiniBlocksSubscriber defines a subscriber to incoming miniblocks. If an IncomingHeaderSubscriber also implements this
+// interface, it will receive after each incoming header the miniblocks with the transactions and smart contract results
+// sent to subscribed addresses, with their tx hashes ordered by execution
+type MiniBlocksSubscriber interface {
+	AddIncomingMiniBlocks(headerHash []byte, miniBlocks []*block.MiniBlock) error
+	IsInterfaceNil() bool
+}
+
+// HeartbeatSubscriber defines a subscriber to main chain heartbeats. If an IncomingHeaderSubscriber also implements
+// this interface, it will periodically receive heartbeats while the main chain feed is alive, even without deposits
+type HeartbeatSubscriber interface {
+	AddHeartbeat(heartbeat *Heartbeat) error
+	IsInterfaceNil() bool
+}
+
+// LivenessTracker should track the main chain rounds and finalized blocks to detect a stalled feed
+type LivenessTracker interface {
+	SaveRoundsInfo(roundsInfo *outport.RoundsInfo)
+	SaveFinalizedBlock(headerHash []byte)
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// AccountsTracker should keep the latest state of the subscribed accounts
+type AccountsTracker interface {
+	UpdateAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount)
+	GetAccounts() map[string]*alteredAccount.AlteredAccount
+	GetUpdatedAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount
+	IsInterfaceNil() bool
+}
+
+// ValidatorsTracker should keep the validators set and their ratings for each shard and epoch
+type ValidatorsTracker interface {
+	SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys)
+	SaveValidatorsRating(validatorsRating *outport.ValidatorsRating)
+	GetValidatorsPubKeys(shardID uint32, epoch uint32) ([][]byte, error)
+	GetValidatorsRatings(shardID uint32, epoch uint32) (map[string]float32, error)
+	IsInterfaceNil() bool
+}
+
+// MultiSigVerifier should be able to verify an aggregated signature, as the multi signers from mx-chain-crypto-go do
+type MultiSigVerifier interface {
+	VerifyAggregatedSig(pubKeysSigners [][]byte, message []byte, aggSig []byte) error
+	IsInterfaceNil() bool
+}
+
+// OutportBlockFilter should strip an outport block down to the data needed to notify it
+type OutportBlockFilter interface {
+	FilterOutportBlock(outportBlock *outport.OutportBlock) *outport.OutportBlock
+	IsInterfaceNil() bool
+}
+
+// HeaderVerifier should verify an incoming header before it is notified
+type HeaderVerifier interface {
+	VerifyHeader(header data.HeaderHandler, signersIndexes []uint64) error
+	IsInterfaceNil() bool
+}
+
 // WSClient defines what a websocket client should do
 type WSClient interface {
 	Close() error
 }
 
+// PayloadHandler defines a handler for payloads received through websocket
+type PayloadHandler interface {
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// SourcesHealthTracker should track the health of each upstream observer
+type SourcesHealthTracker interface {
+	AddSource(source string)
+	RecordPayload(source string)
+	RecordError(source string, err error)
+	IsInterfaceNil() bool
+}
+
 // Indexer should handle node indexer events
 type Indexer interface {
 	SaveBlock(outportBlock *outport.OutportBlock) error
 	FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
+	RevertIndexedBlock(blockData *outport.BlockData) error
+	SaveAccounts(accounts *outport.Accounts) error
+	SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error
+	SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) error
+	SaveRoundsInfo(roundsInfo *outport.RoundsInfo) error
+	Close() error
 	IsInterfaceNil() bool
 }
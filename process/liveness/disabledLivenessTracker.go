@@ -0,0 +1,30 @@
+package liveness
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+type disabledLivenessTracker struct {
+}
+
+// NewDisabledLivenessTracker creates a liveness tracker which does not track anything, used when liveness tracking
+// is not needed
+func NewDisabledLivenessTracker() *disabledLivenessTracker {
+	return &disabledLivenessTracker{}
+}
+
+// SaveRoundsInfo does nothing
+func (dlt *disabledLivenessTracker) SaveRoundsInfo(_ *outport.RoundsInfo) {
+}
+
+// SaveFinalizedBlock does nothing
+func (dlt *disabledLivenessTracker) SaveFinalizedBlock(_ []byte) {
+}
+
+// Close returns nil
+func (dlt *disabledLivenessTracker) Close() error {
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (dlt *disabledLivenessTracker) IsInterfaceNil() bool {
+	return dlt == nil
+}
@@ -0,0 +1,7 @@
+package liveness
+
+import "errors"
+
+var errNilHeartbeatNotifier = errors.New("nil heartbeat notifier provided")
+
+var errInvalidCheckInterval = errors.New("invalid liveness check interval provided")
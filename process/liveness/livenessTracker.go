@@ -0,0 +1,264 @@
+package liveness
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+var log = logger.GetOrCreate("notifier-liveness-process")
+
+// ArgsLivenessTracker is a struct placeholder for args needed to create a liveness tracker. A zero timeout, threshold
+// or heartbeat interval disables the corresponding check
+type ArgsLivenessTracker struct {
+	HeartbeatNotifier                process.HeartbeatNotifier
+	CheckInterval                    time.Duration
+	StalledChainTimeout              time.Duration
+	FinalizationTimeout              time.Duration
+	MaxConsecutiveRoundsWithoutBlock uint64
+	HeartbeatInterval                time.Duration
+}
+
+// livenessMetrics holds the main chain liveness metrics
+type livenessMetrics struct {
+	LastRound                     uint64
+	LastRoundTimestamp            uint64
+	LastEpoch                     uint32
+	LastRoundReceivedAt           time.Time
+	LastFinalizedHeaderHash       []byte
+	LastFinalizedAt               time.Time
+	NumFinalizedBlocks            uint64
+	NumRoundsWithoutBlock         uint64
+	ConsecutiveRoundsWithoutBlock uint64
+	NumHeartbeats                 uint64
+	IsChainStalled                bool
+	IsFinalizationStalled         bool
+}
+
+type livenessTracker struct {
+	heartbeatNotifier                process.HeartbeatNotifier
+	stalledChainTimeout              time.Duration
+	finalizationTimeout              time.Duration
+	maxConsecutiveRoundsWithoutBlock uint64
+	heartbeatInterval                time.Duration
+
+	mutMetrics      sync.RWMutex
+	metrics         livenessMetrics
+	startTime       time.Time
+	lastHeartbeatAt time.Time
+
+	cancel context.CancelFunc
+}
+
+// NewLivenessTracker creates a tracker which uses the received rounds info and finalized blocks to detect a stalled
+// main chain feed. It will also periodically notify heartbeats, if enabled
+func NewLivenessTracker(args ArgsLivenessTracker) (*livenessTracker, error) {
+	if check.IfNil(args.HeartbeatNotifier) {
+		return nil, errNilHeartbeatNotifier
+	}
+	if args.CheckInterval <= 0 {
+		return nil, errInvalidCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tracker := &livenessTracker{
+		heartbeatNotifier:                args.HeartbeatNotifier,
+		stalledChainTimeout:              args.StalledChainTimeout,
+		finalizationTimeout:              args.FinalizationTimeout,
+		maxConsecutiveRoundsWithoutBlock: args.MaxConsecutiveRoundsWithoutBlock,
+		heartbeatInterval:                args.HeartbeatInterval,
+		startTime:                        time.Now(),
+		cancel:                           cancel,
+	}
+
+	go tracker.monitor(ctx, args.CheckInterval)
+
+	return tracker, nil
+}
+
+// SaveRoundsInfo will update the latest main chain round and count the rounds without a proposed block
+func (lt *livenessTracker) SaveRoundsInfo(roundsInfo *outport.RoundsInfo) {
+	lt.mutMetrics.Lock()
+	defer lt.mutMetrics.Unlock()
+
+	for _, roundInfo := range roundsInfo.GetRoundsInfo() {
+		// round 0 is a valid first round, so it is compared only after a round was received
+		hasReceivedRounds := !lt.metrics.LastRoundReceivedAt.IsZero()
+		if hasReceivedRounds && roundInfo.GetRound() <= lt.metrics.LastRound {
+			continue
+		}
+
+		lt.metrics.LastRound = roundInfo.GetRound()
+		lt.metrics.LastRoundTimestamp = roundInfo.GetTimestamp()
+		lt.metrics.LastEpoch = roundInfo.GetEpoch()
+		lt.metrics.LastRoundReceivedAt = time.Now()
+
+		if roundInfo.GetBlockWasProposed() {
+			lt.metrics.ConsecutiveRoundsWithoutBlock = 0
+			continue
+		}
+
+		lt.metrics.NumRoundsWithoutBlock++
+		lt.metrics.ConsecutiveRoundsWithoutBlock++
+		if lt.metrics.ConsecutiveRoundsWithoutBlock == lt.maxConsecutiveRoundsWithoutBlock {
+			log.Warn("main chain rounds without block",
+				"num consecutive rounds", lt.metrics.ConsecutiveRoundsWithoutBlock,
+				"last round", lt.metrics.LastRound)
+		}
+	}
+}
+
+// SaveFinalizedBlock will update the latest main chain finalized block
+func (lt *livenessTracker) SaveFinalizedBlock(headerHash []byte) {
+	lt.mutMetrics.Lock()
+	defer lt.mutMetrics.Unlock()
+
+	lt.metrics.LastFinalizedHeaderHash = headerHash
+	lt.metrics.LastFinalizedAt = time.Now()
+	lt.metrics.NumFinalizedBlocks++
+}
+
+// getMetrics returns the current main chain liveness metrics, which are also logged after each liveness check
+func (lt *livenessTracker) getMetrics() livenessMetrics {
+	lt.mutMetrics.RLock()
+	defer lt.mutMetrics.RUnlock()
+
+	return lt.metrics
+}
+
+func (lt *livenessTracker) monitor(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("liveness tracker is closing")
+			return
+		case now := <-ticker.C:
+			lt.checkLiveness(now)
+			lt.logMetrics()
+		}
+	}
+}
+
+func (lt *livenessTracker) checkLiveness(now time.Time) {
+	lt.mutMetrics.Lock()
+	lt.checkChainStalled(now)
+	lt.checkFinalizationStalled(now)
+	heartbeat := lt.createHeartbeatIfNeeded(now)
+	lt.mutMetrics.Unlock()
+
+	if heartbeat == nil {
+		return
+	}
+
+	err := lt.heartbeatNotifier.NotifyHeartbeat(heartbeat)
+	if err != nil {
+		log.Warn("could not notify heartbeat", "round", heartbeat.Round, "error", err)
+	}
+}
+
+func (lt *livenessTracker) logMetrics() {
+	metrics := lt.getMetrics()
+	log.Debug("main chain liveness",
+		"last round", metrics.LastRound,
+		"last epoch", metrics.LastEpoch,
+		"last finalized hash", hex.EncodeToString(metrics.LastFinalizedHeaderHash),
+		"num finalized blocks", metrics.NumFinalizedBlocks,
+		"num rounds without block", metrics.NumRoundsWithoutBlock,
+		"consecutive rounds without block", metrics.ConsecutiveRoundsWithoutBlock,
+		"num heartbeats", metrics.NumHeartbeats,
+		"is chain stalled", metrics.IsChainStalled,
+		"is finalization stalled", metrics.IsFinalizationStalled,
+	)
+}
+
+func (lt *livenessTracker) checkChainStalled(now time.Time) {
+	if lt.stalledChainTimeout == 0 {
+		return
+	}
+
+	lastRoundReceivedAt := lt.metrics.LastRoundReceivedAt
+	if lastRoundReceivedAt.IsZero() {
+		lastRoundReceivedAt = lt.startTime
+	}
+
+	isStalled := now.Sub(lastRoundReceivedAt) >= lt.stalledChainTimeout
+	if isStalled && !lt.metrics.IsChainStalled {
+		log.Warn("main chain feed is stalled, no rounds info received",
+			"last round", lt.metrics.LastRound,
+			"timeout", lt.stalledChainTimeout)
+	}
+	if !isStalled && lt.metrics.IsChainStalled {
+		log.Info("main chain feed recovered", "last round", lt.metrics.LastRound)
+	}
+
+	lt.metrics.IsChainStalled = isStalled
+}
+
+func (lt *livenessTracker) checkFinalizationStalled(now time.Time) {
+	if lt.finalizationTimeout == 0 {
+		return
+	}
+
+	lastFinalizedAt := lt.metrics.LastFinalizedAt
+	if lastFinalizedAt.IsZero() {
+		lastFinalizedAt = lt.startTime
+	}
+
+	isStalled := now.Sub(lastFinalizedAt) >= lt.finalizationTimeout
+	if isStalled && !lt.metrics.IsFinalizationStalled {
+		log.Warn("main chain finalization is stalled, no finalized block received",
+			"last finalized hash", hex.EncodeToString(lt.metrics.LastFinalizedHeaderHash),
+			"timeout", lt.finalizationTimeout)
+	}
+	if !isStalled && lt.metrics.IsFinalizationStalled {
+		log.Info("main chain finalization recovered",
+			"last finalized hash", hex.EncodeToString(lt.metrics.LastFinalizedHeaderHash))
+	}
+
+	lt.metrics.IsFinalizationStalled = isStalled
+}
+
+// createHeartbeatIfNeeded returns a heartbeat only if the interval elapsed and new rounds were received in time
+func (lt *livenessTracker) createHeartbeatIfNeeded(now time.Time) *process.Heartbeat {
+	if lt.heartbeatInterval == 0 {
+		return nil
+	}
+	if lt.metrics.LastRoundReceivedAt.IsZero() || lt.metrics.IsChainStalled {
+		return nil
+	}
+	if now.Sub(lt.lastHeartbeatAt) < lt.heartbeatInterval {
+		return nil
+	}
+
+	lt.lastHeartbeatAt = now
+	lt.metrics.NumHeartbeats++
+
+	return &process.Heartbeat{
+		Round:                         lt.metrics.LastRound,
+		RoundTimestamp:                lt.metrics.LastRoundTimestamp,
+		Epoch:                         lt.metrics.LastEpoch,
+		LastFinalizedHeaderHash:       lt.metrics.LastFinalizedHeaderHash,
+		ConsecutiveRoundsWithoutBlock: lt.metrics.ConsecutiveRoundsWithoutBlock,
+	}
+}
+
+// Close will stop the liveness checks
+func (lt *livenessTracker) Close() error {
+	lt.cancel()
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (lt *livenessTracker) IsInterfaceNil() bool {
+	return lt == nil
+}
@@ -0,0 +1,295 @@
+package liveness
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgs() ArgsLivenessTracker {
+	return ArgsLivenessTracker{
+		HeartbeatNotifier:                &testscommon.SovereignNotifierStub{},
+		CheckInterval:                    time.Hour,
+		StalledChainTimeout:              time.Minute,
+		FinalizationTimeout:              2 * time.Minute,
+		MaxConsecutiveRoundsWithoutBlock: 2,
+		HeartbeatInterval:                10 * time.Second,
+	}
+}
+
+func TestNewLivenessTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		tracker, err := NewLivenessTracker(createArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(tracker))
+		require.Nil(t, tracker.Close())
+	})
+
+	t.Run("nil heartbeat notifier, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HeartbeatNotifier = nil
+		tracker, err := NewLivenessTracker(args)
+		require.Equal(t, errNilHeartbeatNotifier, err)
+		require.Nil(t, tracker)
+	})
+
+	t.Run("invalid check interval, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.CheckInterval = 0
+		tracker, err := NewLivenessTracker(args)
+		require.Equal(t, errInvalidCheckInterval, err)
+		require.Nil(t, tracker)
+	})
+}
+
+func TestLivenessTracker_SaveRoundsInfo(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewLivenessTracker(createArgs())
+	defer func() {
+		_ = tracker.Close()
+	}()
+
+	tracker.SaveRoundsInfo(&outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 1, BlockWasProposed: true, Epoch: 1, Timestamp: 10},
+			{Round: 2, BlockWasProposed: false, Epoch: 1, Timestamp: 16},
+			{Round: 3, BlockWasProposed: false, Epoch: 1, Timestamp: 22},
+		},
+	})
+
+	metrics := tracker.getMetrics()
+	require.Equal(t, uint64(3), metrics.LastRound)
+	require.Equal(t, uint64(22), metrics.LastRoundTimestamp)
+	require.Equal(t, uint32(1), metrics.LastEpoch)
+	require.Equal(t, uint64(2), metrics.NumRoundsWithoutBlock)
+	require.Equal(t, uint64(2), metrics.ConsecutiveRoundsWithoutBlock)
+	require.False(t, metrics.LastRoundReceivedAt.IsZero())
+
+	tracker.SaveRoundsInfo(&outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 2, BlockWasProposed: true, Epoch: 1, Timestamp: 16},
+			{Round: 4, BlockWasProposed: true, Epoch: 2, Timestamp: 28},
+		},
+	})
+
+	metrics = tracker.getMetrics()
+	require.Equal(t, uint64(4), metrics.LastRound)
+	require.Equal(t, uint32(2), metrics.LastEpoch)
+	require.Equal(t, uint64(2), metrics.NumRoundsWithoutBlock)
+	require.Equal(t, uint64(0), metrics.ConsecutiveRoundsWithoutBlock)
+
+	// a re-sent round should not be counted again
+	tracker.SaveRoundsInfo(&outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 4, BlockWasProposed: false, Epoch: 2, Timestamp: 28},
+		},
+	})
+
+	metrics = tracker.getMetrics()
+	require.Equal(t, uint64(4), metrics.LastRound)
+	require.Equal(t, uint64(2), metrics.NumRoundsWithoutBlock)
+	require.Equal(t, uint64(0), metrics.ConsecutiveRoundsWithoutBlock)
+}
+
+func TestLivenessTracker_SaveRoundsInfoFirstRoundZero(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewLivenessTracker(createArgs())
+	defer func() {
+		_ = tracker.Close()
+	}()
+
+	tracker.SaveRoundsInfo(&outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 0, BlockWasProposed: false, Epoch: 0, Timestamp: 4},
+		},
+	})
+
+	metrics := tracker.getMetrics()
+	require.Equal(t, uint64(0), metrics.LastRound)
+	require.Equal(t, uint64(4), metrics.LastRoundTimestamp)
+	require.Equal(t, uint64(1), metrics.NumRoundsWithoutBlock)
+	require.False(t, metrics.LastRoundReceivedAt.IsZero())
+
+	tracker.SaveRoundsInfo(&outport.RoundsInfo{
+		RoundsInfo: []*outport.RoundInfo{
+			{Round: 0, BlockWasProposed: false, Epoch: 0, Timestamp: 4},
+		},
+	})
+	require.Equal(t, uint64(1), tracker.getMetrics().NumRoundsWithoutBlock)
+}
+
+func TestLivenessTracker_SaveFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	tracker, _ := NewLivenessTracker(createArgs())
+	defer func() {
+		_ = tracker.Close()
+	}()
+
+	tracker.SaveFinalizedBlock([]byte("hash1"))
+	tracker.SaveFinalizedBlock([]byte("hash2"))
+
+	metrics := tracker.getMetrics()
+	require.Equal(t, []byte("hash2"), metrics.LastFinalizedHeaderHash)
+	require.Equal(t, uint64(2), metrics.NumFinalizedBlocks)
+	require.False(t, metrics.LastFinalizedAt.IsZero())
+}
+
+func TestLivenessTracker_CheckLiveness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stalled chain and finalization are detected and recovered", func(t *testing.T) {
+		t.Parallel()
+
+		tracker, _ := NewLivenessTracker(createArgs())
+		defer func() {
+			_ = tracker.Close()
+		}()
+
+		tracker.checkLiveness(tracker.startTime.Add(time.Second))
+		metrics := tracker.getMetrics()
+		require.False(t, metrics.IsChainStalled)
+		require.False(t, metrics.IsFinalizationStalled)
+
+		tracker.checkLiveness(tracker.startTime.Add(time.Minute))
+		metrics = tracker.getMetrics()
+		require.True(t, metrics.IsChainStalled)
+		require.False(t, metrics.IsFinalizationStalled)
+
+		tracker.checkLiveness(tracker.startTime.Add(2 * time.Minute))
+		metrics = tracker.getMetrics()
+		require.True(t, metrics.IsChainStalled)
+		require.True(t, metrics.IsFinalizationStalled)
+
+		tracker.SaveRoundsInfo(&outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{{Round: 1, BlockWasProposed: true}}})
+		tracker.SaveFinalizedBlock([]byte("hash"))
+		tracker.checkLiveness(time.Now())
+		metrics = tracker.getMetrics()
+		require.False(t, metrics.IsChainStalled)
+		require.False(t, metrics.IsFinalizationStalled)
+	})
+
+	t.Run("disabled timeouts, should never be stalled", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.StalledChainTimeout = 0
+		args.FinalizationTimeout = 0
+		tracker, _ := NewLivenessTracker(args)
+		defer func() {
+			_ = tracker.Close()
+		}()
+
+		tracker.checkLiveness(tracker.startTime.Add(time.Hour))
+		metrics := tracker.getMetrics()
+		require.False(t, metrics.IsChainStalled)
+		require.False(t, metrics.IsFinalizationStalled)
+	})
+}
+
+func TestLivenessTracker_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("heartbeats are notified at the configured interval while the chain is alive", func(t *testing.T) {
+		t.Parallel()
+
+		heartbeats := make([]*process.Heartbeat, 0)
+		args := createArgs()
+		args.HeartbeatNotifier = &testscommon.SovereignNotifierStub{
+			NotifyHeartbeatCalled: func(heartbeat *process.Heartbeat) error {
+				heartbeats = append(heartbeats, heartbeat)
+				return nil
+			},
+		}
+		tracker, _ := NewLivenessTracker(args)
+		defer func() {
+			_ = tracker.Close()
+		}()
+
+		now := time.Now()
+		tracker.checkLiveness(now)
+		require.Empty(t, heartbeats)
+
+		tracker.SaveRoundsInfo(&outport.RoundsInfo{
+			RoundsInfo: []*outport.RoundInfo{
+				{Round: 5, BlockWasProposed: false, Epoch: 1, Timestamp: 30},
+			},
+		})
+		tracker.SaveFinalizedBlock([]byte("hash"))
+
+		now = time.Now()
+		tracker.checkLiveness(now)
+		require.Len(t, heartbeats, 1)
+		require.Equal(t, &process.Heartbeat{
+			Round:                         5,
+			RoundTimestamp:                30,
+			Epoch:                         1,
+			LastFinalizedHeaderHash:       []byte("hash"),
+			ConsecutiveRoundsWithoutBlock: 1,
+		}, heartbeats[0])
+
+		tracker.checkLiveness(now.Add(time.Second))
+		require.Len(t, heartbeats, 1)
+
+		tracker.checkLiveness(now.Add(10 * time.Second))
+		require.Len(t, heartbeats, 2)
+		require.Equal(t, uint64(2), tracker.getMetrics().NumHeartbeats)
+
+		tracker.checkLiveness(now.Add(2 * time.Minute))
+		require.Len(t, heartbeats, 2)
+		require.True(t, tracker.getMetrics().IsChainStalled)
+	})
+
+	t.Run("disabled heartbeat, should not notify", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.HeartbeatInterval = 0
+		args.HeartbeatNotifier = &testscommon.SovereignNotifierStub{
+			NotifyHeartbeatCalled: func(heartbeat *process.Heartbeat) error {
+				require.Fail(t, "should not notify heartbeat")
+				return nil
+			},
+		}
+		tracker, _ := NewLivenessTracker(args)
+		defer func() {
+			_ = tracker.Close()
+		}()
+
+		tracker.SaveRoundsInfo(&outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{{Round: 1, BlockWasProposed: true}}})
+		tracker.checkLiveness(time.Now())
+	})
+
+	t.Run("monitor should periodically check liveness until closed", func(t *testing.T) {
+		t.Parallel()
+
+		numHeartbeats := uint32(0)
+		args := createArgs()
+		args.CheckInterval = time.Millisecond
+		args.HeartbeatInterval = time.Millisecond
+		args.HeartbeatNotifier = &testscommon.SovereignNotifierStub{
+			NotifyHeartbeatCalled: func(heartbeat *process.Heartbeat) error {
+				atomic.AddUint32(&numHeartbeats, 1)
+				return nil
+			},
+		}
+		tracker, _ := NewLivenessTracker(args)
+		tracker.SaveRoundsInfo(&outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{{Round: 1, BlockWasProposed: true}}})
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint32(&numHeartbeats) > 0
+		}, time.Second, time.Millisecond)
+
+		require.Nil(t, tracker.Close())
+	})
+}
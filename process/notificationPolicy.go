@@ -0,0 +1,28 @@
+package process
+
+import "time"
+
+// NotificationMode defines which incoming headers are notified to a subscriber and how
+type NotificationMode string
+
+const (
+	// NotifyAllHeaders will notify every finalized header, one by one
+	NotifyAllHeaders NotificationMode = "all"
+	// NotifyHeadersWithEvents will notify, one by one, only the finalized headers which contain incoming events or
+	// incoming miniblocks
+	NotifyHeadersWithEvents NotificationMode = "with_events"
+	// NotifyBatchedHeaders will notify every finalized header, grouped in batches. Subscribers should also implement
+	// HeadersBatchSubscriber
+	NotifyBatchedHeaders NotificationMode = "batched"
+)
+
+// NotificationPolicy holds the notification policy of an incoming header subscriber. In batched mode, a batch is
+// notified once it reaches BatchSize headers or BatchInterval passed since its first header, whichever comes first.
+// A zero BatchSize or BatchInterval disables the corresponding trigger. If ShardIDs is provided, only the headers of
+// these shards are notified, which allows routing the headers of multi-shard observers to different subscribers
+type NotificationPolicy struct {
+	Mode          NotificationMode
+	BatchSize     uint32
+	BatchInterval time.Duration
+	ShardIDs      []uint32
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import "sync"
+
+// maxTrackedDeliveries is the number of latest headers for which a subscription remembers the delivered steps. A
+// failed notification is retried with the same header, so only the latest headers need to be remembered
+const maxTrackedDeliveries = 100
+
+type deliveryStep int
+
+const (
+	noStepDelivered deliveryStep = iota
+	headerDelivered
+	miniBlocksDelivered
+	accountsDelivered
+)
+
+// deliveryTracker remembers the steps of the header notification which were accepted by a subscriber, so that a
+// retried notification does not deliver the same data twice
+type deliveryTracker struct {
+	mutDeliveries sync.Mutex
+	steps         map[string]deliveryStep
+	order         []string
+}
+
+func newDeliveryTracker() *deliveryTracker {
+	return &deliveryTracker{
+		steps: make(map[string]deliveryStep),
+		order: make([]string, 0),
+	}
+}
+
+func (dt *deliveryTracker) getStep(headerHash []byte) deliveryStep {
+	dt.mutDeliveries.Lock()
+	defer dt.mutDeliveries.Unlock()
+
+	return dt.steps[string(headerHash)]
+}
+
+func (dt *deliveryTracker) saveStep(headerHash []byte, step deliveryStep) {
+	dt.mutDeliveries.Lock()
+	defer dt.mutDeliveries.Unlock()
+
+	key := string(headerHash)
+	_, found := dt.steps[key]
+	dt.steps[key] = step
+	if found {
+		return
+	}
+
+	dt.order = append(dt.order, key)
+	if len(dt.order) > maxTrackedDeliveries {
+		delete(dt.steps, dt.order[0])
+		dt.order = dt.order[1:]
+	}
+}
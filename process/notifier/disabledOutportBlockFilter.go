@@ -0,0 +1,22 @@
+package notifier
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+type disabledOutportBlockFilter struct {
+}
+
+// NewDisabledOutportBlockFilter creates an outport block filter which keeps the blocks unchanged, used when
+// pre-filtering is not enabled
+func NewDisabledOutportBlockFilter() *disabledOutportBlockFilter {
+	return &disabledOutportBlockFilter{}
+}
+
+// FilterOutportBlock returns the provided outport block
+func (dobf *disabledOutportBlockFilter) FilterOutportBlock(outportBlock *outport.OutportBlock) *outport.OutportBlock {
+	return outportBlock
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (dobf *disabledOutportBlockFilter) IsInterfaceNil() bool {
+	return dobf == nil
+}
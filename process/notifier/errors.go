@@ -19,3 +19,15 @@ var errNilTransactionPool = errors.New("nil transaction pool provided")
 var errNilBlockData = errors.New("nil block data provided")
 
 var errNilHasher = errors.New("nil hasher provided")
+
+var errNilAccountsTracker = errors.New("nil accounts tracker provided")
+
+var errNilHeaderVerifier = errors.New("nil header verifier provided")
+
+var errInvalidNotificationMode = errors.New("invalid notification mode provided")
+
+var errInvalidBatchPolicy = errors.New("invalid batch policy, batch size or batch interval should be provided")
+
+var errNotHeadersBatchSubscriber = errors.New("subscriber registered in batched mode does not implement process.HeadersBatchSubscriber")
+
+var errNilShardCoordinator = errors.New("nil shard coordinator provided")
@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+)
+
+// DiffEvents returns the events found only in the events list, then the ones found only in the baseline events list.
+// Events are compared by content, identical events being matched by count
+func DiffEvents(events []*transaction.Event, baselineEvents []*transaction.Event) ([]*transaction.Event, []*transaction.Event) {
+	baselineCounts := make(map[string]int, len(baselineEvents))
+	for _, event := range baselineEvents {
+		baselineCounts[getEventKey(event)]++
+	}
+
+	addedEvents := make([]*transaction.Event, 0)
+	for _, event := range events {
+		key := getEventKey(event)
+		if baselineCounts[key] > 0 {
+			baselineCounts[key]--
+			continue
+		}
+
+		addedEvents = append(addedEvents, event)
+	}
+
+	removedEvents := make([]*transaction.Event, 0)
+	for _, event := range baselineEvents {
+		key := getEventKey(event)
+		if baselineCounts[key] > 0 {
+			baselineCounts[key]--
+			removedEvents = append(removedEvents, event)
+		}
+	}
+
+	return addedEvents, removedEvents
+}
+
+// getEventKey hex encodes the event fields, so that the separators can not be found in the encoded fields
+func getEventKey(event *transaction.Event) string {
+	topics := make([]string, 0, len(event.GetTopics()))
+	for _, topic := range event.GetTopics() {
+		topics = append(topics, hex.EncodeToString(topic))
+	}
+	additionalData := make([]string, 0, len(event.GetAdditionalData()))
+	for _, value := range event.GetAdditionalData() {
+		additionalData = append(additionalData, hex.EncodeToString(value))
+	}
+
+	return strings.Join([]string{
+		hex.EncodeToString(event.GetIdentifier()),
+		hex.EncodeToString(event.GetAddress()),
+		strings.Join(topics, ","),
+		hex.EncodeToString(event.GetData()),
+		strings.Join(additionalData, ","),
+	}, "|")
+}
@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEvents(t *testing.T) {
+	t.Parallel()
+
+	eventA := &transaction.Event{Identifier: identifier, Address: []byte("addrA")}
+	eventB := &transaction.Event{Identifier: identifier, Address: []byte("addrB")}
+	eventC := &transaction.Event{Identifier: []byte("execute"), Address: []byte("addrA")}
+	eventCTopics := &transaction.Event{Identifier: []byte("execute"), Address: []byte("addrA"), Topics: [][]byte{[]byte("topic")}}
+
+	addedEvents, removedEvents := DiffEvents([]*transaction.Event{eventA, eventB}, []*transaction.Event{eventB, eventA})
+	require.Empty(t, addedEvents)
+	require.Empty(t, removedEvents)
+
+	// identical events are matched by count
+	addedEvents, removedEvents = DiffEvents([]*transaction.Event{eventA, eventA, eventC}, []*transaction.Event{eventA, eventB})
+	require.Equal(t, []*transaction.Event{eventA, eventC}, addedEvents)
+	require.Equal(t, []*transaction.Event{eventB}, removedEvents)
+
+	addedEvents, removedEvents = DiffEvents(nil, []*transaction.Event{eventC})
+	require.Empty(t, addedEvents)
+	require.Equal(t, []*transaction.Event{eventC}, removedEvents)
+
+	addedEvents, removedEvents = DiffEvents([]*transaction.Event{eventCTopics}, []*transaction.Event{eventC})
+	require.Equal(t, []*transaction.Event{eventCTopics}, addedEvents)
+	require.Equal(t, []*transaction.Event{eventC}, removedEvents)
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+// eventsMatcher indexes the subscribed events by identifier and then by address, so that matching an event does not
+// depend on the number of subscriptions. It also indexes all subscribed addresses, to match the receivers of transactions
+type eventsMatcher struct {
+	addressesByIdentifier map[string]map[string]string
+	subscribedAddresses   map[string]string
+}
+
+func newEventsMatcher(subscribedEvents []SubscribedEvent) *eventsMatcher {
+	addressesByIdentifier := make(map[string]map[string]string)
+	subscribedAddresses := make(map[string]string)
+	for _, subEvent := range subscribedEvents {
+		identifier := string(subEvent.Identifier)
+		addresses, found := addressesByIdentifier[identifier]
+		if !found {
+			addresses = make(map[string]string, len(subEvent.Addresses))
+			addressesByIdentifier[identifier] = addresses
+		}
+
+		for decodedAddr, encodedAddr := range subEvent.Addresses {
+			addresses[decodedAddr] = encodedAddr
+			subscribedAddresses[decodedAddr] = encodedAddr
+		}
+	}
+
+	return &eventsMatcher{
+		addressesByIdentifier: addressesByIdentifier,
+		subscribedAddresses:   subscribedAddresses,
+	}
+}
+
+func (em *eventsMatcher) isSubscribed(event *transaction.Event, txHash string) bool {
+	addresses, found := em.addressesByIdentifier[string(event.GetIdentifier())]
+	if !found {
+		return false
+	}
+
+	encodedAddr, found := addresses[string(event.GetAddress())]
+	if !found {
+		return false
+	}
+
+	// the level is checked first, to avoid the allocations of the log arguments for each matched event
+	if log.GetLevel() == logger.LogTrace {
+		log.Trace("found incoming event", "original tx hash", txHash, "receiver", encodedAddr)
+	}
+	return true
+}
+
+func (em *eventsMatcher) isSubscribedReceiver(receiver []byte, txHash string) bool {
+	encodedAddr, found := em.subscribedAddresses[string(receiver)]
+	if !found {
+		return false
+	}
+
+	if log.GetLevel() == logger.LogTrace {
+		log.Trace("found incoming tx", "tx hash", txHash, "receiver", encodedAddr)
+	}
+	return true
+}
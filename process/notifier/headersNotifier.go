@@ -5,42 +5,54 @@ import (
 	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/sovereign"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
 )
 
 type headersNotifier struct {
 	mutSubscribers sync.RWMutex
-	subscribers    []process.IncomingHeaderSubscriber
+	subscribers    []*subscription
 }
 
 func newHeadersNotifier() *headersNotifier {
 	return &headersNotifier{
 		mutSubscribers: sync.RWMutex{},
-		subscribers:    make([]process.IncomingHeaderSubscriber, 0),
+		subscribers:    make([]*subscription, 0),
 	}
 }
 
-func (hn *headersNotifier) registerSubscriber(handler process.IncomingHeaderSubscriber) error {
+func (hn *headersNotifier) registerSubscriber(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
 	if check.IfNil(handler) {
 		return errNilHeaderSubscriber
 	}
 
+	sub, err := newSubscription(handler, policy)
+	if err != nil {
+		return err
+	}
+
 	hn.mutSubscribers.Lock()
-	hn.subscribers = append(hn.subscribers, handler)
+	hn.subscribers = append(hn.subscribers, sub)
 	hn.mutSubscribers.Unlock()
 
 	return nil
 }
 
-func (hn *headersNotifier) notifyHeaderSubscribers(header sovereign.IncomingHeaderHandler, headerHash []byte) error {
+func (hn *headersNotifier) notifyHeaderSubscribers(
+	header sovereign.IncomingHeaderHandler,
+	headerHash []byte,
+	miniBlocks []*block.MiniBlock,
+	accounts map[string]*alteredAccount.AlteredAccount,
+) error {
 	log.Debug("notifying incoming header", "hash", hex.EncodeToString(headerHash))
 
 	hn.mutSubscribers.RLock()
 	defer hn.mutSubscribers.RUnlock()
 
-	for _, handler := range hn.subscribers {
-		err := handler.AddHeader(headerHash, header)
+	for _, sub := range hn.subscribers {
+		err := sub.notify(header, headerHash, miniBlocks, accounts)
 		if err != nil {
 			return err
 		}
@@ -48,3 +60,41 @@ func (hn *headersNotifier) notifyHeaderSubscribers(header sovereign.IncomingHead
 
 	return nil
 }
+
+func (hn *headersNotifier) notifyHeartbeatSubscribers(heartbeat *process.Heartbeat) error {
+	log.Trace("notifying heartbeat", "round", heartbeat.Round)
+
+	hn.mutSubscribers.RLock()
+	defer hn.mutSubscribers.RUnlock()
+
+	for _, sub := range hn.subscribers {
+		heartbeatHandler, ok := sub.handler.(process.HeartbeatSubscriber)
+		if !ok {
+			continue
+		}
+
+		err := heartbeatHandler.AddHeartbeat(heartbeat)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close will notify the pending batches of all subscribers, returning the last error encountered
+func (hn *headersNotifier) close() error {
+	hn.mutSubscribers.RLock()
+	defer hn.mutSubscribers.RUnlock()
+
+	var lastErr error
+	for _, sub := range hn.subscribers {
+		err := sub.close()
+		if err != nil {
+			log.Error("could not notify pending headers batch on close", "error", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
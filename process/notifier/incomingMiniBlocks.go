@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"encoding/hex"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+)
+
+// createIncomingMiniBlocks creates, for each block miniblock followed by each intra shard miniblock, a miniblock with
+// the hashes of the transactions and smart contract results sent to subscribed addresses. Miniblocks without subscribed
+// receivers are skipped. The tx hashes of each miniblock are ordered by the execution order set by the node in the pool,
+// and the miniblocks by the execution order of their first tx hash
+func createIncomingMiniBlocks(outportBlock *outport.OutportBlock, matcher *eventsMatcher) []*block.MiniBlock {
+	pool := outportBlock.GetTransactionPool()
+	executionOrder := getExecutionOrder(pool)
+	getTxOrder := func(txHash []byte) uint32 {
+		return executionOrder[hex.EncodeToString(txHash)]
+	}
+	addedTxHashes := make(map[string]struct{})
+	incomingMiniBlocks := make([]*block.MiniBlock, 0)
+	addMiniBlocks := func(miniBlocks []*block.MiniBlock) {
+		for _, miniBlock := range miniBlocks {
+			txHashes := make([][]byte, 0)
+			for _, txHash := range miniBlock.GetTxHashes() {
+				encodedTxHash := hex.EncodeToString(txHash)
+				_, found := addedTxHashes[encodedTxHash]
+				if found || !matcher.isSubscribedReceiver(getReceiver(pool, encodedTxHash), encodedTxHash) {
+					continue
+				}
+
+				addedTxHashes[encodedTxHash] = struct{}{}
+				txHashes = append(txHashes, txHash)
+			}
+			if len(txHashes) == 0 {
+				continue
+			}
+			sort.SliceStable(txHashes, func(i, j int) bool {
+				return getTxOrder(txHashes[i]) < getTxOrder(txHashes[j])
+			})
+
+			incomingMiniBlocks = append(incomingMiniBlocks, &block.MiniBlock{
+				TxHashes:        txHashes,
+				ReceiverShardID: miniBlock.GetReceiverShardID(),
+				SenderShardID:   miniBlock.GetSenderShardID(),
+				Type:            miniBlock.GetType(),
+			})
+		}
+	}
+
+	addMiniBlocks(outportBlock.GetBlockData().GetBody().GetMiniBlocks())
+	addMiniBlocks(outportBlock.GetBlockData().GetIntraShardMiniBlocks())
+	sort.SliceStable(incomingMiniBlocks, func(i, j int) bool {
+		return getTxOrder(incomingMiniBlocks[i].TxHashes[0]) < getTxOrder(incomingMiniBlocks[j].TxHashes[0])
+	})
+
+	return incomingMiniBlocks
+}
+
+// getReceiver returns the receiver of the transaction or smart contract result with the provided hex encoded hash, or
+// nil if it is not found in the pool
+func getReceiver(pool *outport.TransactionPool, encodedTxHash string) []byte {
+	txInfo, found := pool.GetTransactions()[encodedTxHash]
+	if found {
+		return txInfo.GetTransaction().GetRcvAddr()
+	}
+
+	scrInfo, found := pool.GetSmartContractResults()[encodedTxHash]
+	if found {
+		return scrInfo.GetSmartContractResult().GetRcvAddr()
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+)
+
+// getLogsInCanonicalOrder returns the logs of the pool ordered by the execution order of their transactions, as set by
+// the node in the pool. Logs of transactions which are not found in the pool are placed last, ordered by transaction
+// hash. Logs of the same transaction keep their relative order. The pool logs are not modified
+func getLogsInCanonicalOrder(pool *outport.TransactionPool) []*outport.LogData {
+	executionOrder := getExecutionOrder(pool)
+	logsData := pool.GetLogs()
+
+	sortedLogs := make([]*outport.LogData, len(logsData))
+	copy(sortedLogs, logsData)
+	sort.SliceStable(sortedLogs, func(i, j int) bool {
+		txHashI := sortedLogs[i].GetTxHash()
+		txHashJ := sortedLogs[j].GetTxHash()
+		orderI, foundI := executionOrder[txHashI]
+		orderJ, foundJ := executionOrder[txHashJ]
+
+		switch {
+		case foundI && foundJ:
+			return orderI < orderJ
+		case foundI != foundJ:
+			return foundI
+		default:
+			return txHashI < txHashJ
+		}
+	})
+
+	return sortedLogs
+}
+
+// getExecutionOrder maps the hex encoded hashes of the transactions, smart contract results and rewards of the pool to
+// their execution order
+func getExecutionOrder(pool *outport.TransactionPool) map[string]uint32 {
+	executionOrder := make(map[string]uint32)
+	for txHash, txInfo := range pool.GetTransactions() {
+		executionOrder[txHash] = txInfo.GetExecutionOrder()
+	}
+	for scrHash, scrInfo := range pool.GetSmartContractResults() {
+		executionOrder[scrHash] = scrInfo.GetExecutionOrder()
+	}
+	for rewardHash, rewardInfo := range pool.GetRewards() {
+		executionOrder[rewardHash] = rewardInfo.GetExecutionOrder()
+	}
+
+	return executionOrder
+}
@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+)
+
+type outportBlockFilter struct {
+	mutSubscriptions    sync.RWMutex
+	eventsMatcher       *eventsMatcher
+	subscribedAddresses map[string]struct{}
+}
+
+// NewOutportBlockFilter creates a filter which strips an outport block down to the data needed to notify it: the
+// header, the miniblocks, the signers, the subscribed events, the transactions and smart contract results sent to
+// subscribed addresses and the subscribed altered accounts
+func NewOutportBlockFilter(subscribedEvents []SubscribedEvent) (*outportBlockFilter, error) {
+	err := checkEvents(subscribedEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outportBlockFilter{
+		eventsMatcher:       newEventsMatcher(subscribedEvents),
+		subscribedAddresses: getEncodedAddresses(subscribedEvents),
+	}, nil
+}
+
+func getEncodedAddresses(subscribedEvents []SubscribedEvent) map[string]struct{} {
+	encodedAddresses := make(map[string]struct{})
+	for _, event := range subscribedEvents {
+		for _, encodedAddr := range event.Addresses {
+			encodedAddresses[encodedAddr] = struct{}{}
+		}
+	}
+
+	return encodedAddresses
+}
+
+// UpdateSubscribedEvents will validate and replace the subscribed events. Blocks which were already filtered keep
+// only the data of the previous subscriptions
+func (obf *outportBlockFilter) UpdateSubscribedEvents(subscribedEvents []SubscribedEvent) error {
+	err := checkEvents(subscribedEvents)
+	if err != nil {
+		return err
+	}
+
+	matcher := newEventsMatcher(subscribedEvents)
+	subscribedAddresses := getEncodedAddresses(subscribedEvents)
+
+	obf.mutSubscriptions.Lock()
+	obf.eventsMatcher = matcher
+	obf.subscribedAddresses = subscribedAddresses
+	obf.mutSubscriptions.Unlock()
+
+	return nil
+}
+
+// FilterOutportBlock returns a new outport block which only holds the data used to notify it. Notifications created
+// from the filtered block are identical to the ones created from the full block
+func (obf *outportBlockFilter) FilterOutportBlock(outportBlock *outport.OutportBlock) *outport.OutportBlock {
+	if outportBlock == nil || outportBlock.BlockData == nil {
+		return outportBlock
+	}
+
+	obf.mutSubscriptions.RLock()
+	matcher, subscribedAddresses := obf.eventsMatcher, obf.subscribedAddresses
+	obf.mutSubscriptions.RUnlock()
+
+	pool := outportBlock.GetTransactionPool()
+	filteredLogs := filterLogs(pool.GetLogs(), matcher)
+	logsTxHashes := getLogsTxHashes(filteredLogs)
+
+	return &outport.OutportBlock{
+		ShardID: outportBlock.ShardID,
+		BlockData: &outport.BlockData{
+			ShardID:     outportBlock.BlockData.ShardID,
+			HeaderBytes: outportBlock.BlockData.HeaderBytes,
+			HeaderType:  outportBlock.BlockData.HeaderType,
+			HeaderHash:  outportBlock.BlockData.HeaderHash,
+			// miniblocks only hold transaction hashes and are needed to create the incoming miniblocks
+			Body:                 outportBlock.BlockData.Body,
+			IntraShardMiniBlocks: outportBlock.BlockData.IntraShardMiniBlocks,
+		},
+		TransactionPool: &outport.TransactionPool{
+			Transactions:         filterTransactions(pool.GetTransactions(), matcher, logsTxHashes),
+			SmartContractResults: filterSmartContractResults(pool.GetSmartContractResults(), matcher, logsTxHashes),
+			Rewards:              filterRewards(pool.GetRewards(), logsTxHashes),
+			Logs:                 filteredLogs,
+		},
+		AlteredAccounts:        filterAlteredAccounts(outportBlock.AlteredAccounts, subscribedAddresses),
+		SignersIndexes:         outportBlock.SignersIndexes,
+		HighestFinalBlockNonce: outportBlock.HighestFinalBlockNonce,
+	}
+}
+
+func filterLogs(logsData []*outport.LogData, matcher *eventsMatcher) []*outport.LogData {
+	filteredLogs := make([]*outport.LogData, 0)
+	for _, logData := range logsData {
+		events := make([]*transaction.Event, 0)
+		for _, event := range logData.GetLog().GetEvents() {
+			if matcher.isSubscribed(event, logData.TxHash) {
+				events = append(events, event)
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		filteredLogs = append(filteredLogs, &outport.LogData{
+			TxHash: logData.TxHash,
+			Log: &transaction.Log{
+				Address: logData.GetLog().GetAddress(),
+				Events:  events,
+			},
+		})
+	}
+
+	return filteredLogs
+}
+
+func getLogsTxHashes(logsData []*outport.LogData) map[string]struct{} {
+	txHashes := make(map[string]struct{}, len(logsData))
+	for _, logData := range logsData {
+		txHashes[logData.TxHash] = struct{}{}
+	}
+
+	return txHashes
+}
+
+// filterTransactions keeps the transactions sent to subscribed addresses, and only the execution order of the ones
+// with subscribed events, which is needed to order the incoming events
+func filterTransactions(
+	txs map[string]*outport.TxInfo,
+	matcher *eventsMatcher,
+	logsTxHashes map[string]struct{},
+) map[string]*outport.TxInfo {
+	filteredTxs := make(map[string]*outport.TxInfo)
+	for txHash, txInfo := range txs {
+		if matcher.isSubscribedReceiver(txInfo.GetTransaction().GetRcvAddr(), txHash) {
+			filteredTxs[txHash] = txInfo
+			continue
+		}
+
+		_, hasLogs := logsTxHashes[txHash]
+		if hasLogs {
+			filteredTxs[txHash] = &outport.TxInfo{ExecutionOrder: txInfo.GetExecutionOrder()}
+		}
+	}
+
+	return filteredTxs
+}
+
+// filterSmartContractResults keeps the smart contract results sent to subscribed addresses, and only the execution
+// order of the ones with subscribed events
+func filterSmartContractResults(
+	scrs map[string]*outport.SCRInfo,
+	matcher *eventsMatcher,
+	logsTxHashes map[string]struct{},
+) map[string]*outport.SCRInfo {
+	filteredSCRs := make(map[string]*outport.SCRInfo)
+	for scrHash, scrInfo := range scrs {
+		if matcher.isSubscribedReceiver(scrInfo.GetSmartContractResult().GetRcvAddr(), scrHash) {
+			filteredSCRs[scrHash] = scrInfo
+			continue
+		}
+
+		_, hasLogs := logsTxHashes[scrHash]
+		if hasLogs {
+			filteredSCRs[scrHash] = &outport.SCRInfo{ExecutionOrder: scrInfo.GetExecutionOrder()}
+		}
+	}
+
+	return filteredSCRs
+}
+
+// filterRewards keeps only the execution order of the rewards with subscribed events
+func filterRewards(rewards map[string]*outport.RewardInfo, logsTxHashes map[string]struct{}) map[string]*outport.RewardInfo {
+	filteredRewards := make(map[string]*outport.RewardInfo)
+	for rewardHash, rewardInfo := range rewards {
+		_, hasLogs := logsTxHashes[rewardHash]
+		if hasLogs {
+			filteredRewards[rewardHash] = &outport.RewardInfo{ExecutionOrder: rewardInfo.GetExecutionOrder()}
+		}
+	}
+
+	return filteredRewards
+}
+
+func filterAlteredAccounts(
+	alteredAccounts map[string]*alteredAccount.AlteredAccount,
+	subscribedAddresses map[string]struct{},
+) map[string]*alteredAccount.AlteredAccount {
+	filteredAccounts := make(map[string]*alteredAccount.AlteredAccount)
+	for encodedAddr, account := range alteredAccounts {
+		if account == nil {
+			continue
+		}
+
+		address := encodedAddr
+		if len(account.Address) != 0 {
+			address = account.Address
+		}
+
+		_, isSubscribed := subscribedAddresses[address]
+		if isSubscribed {
+			filteredAccounts[encodedAddr] = account
+		}
+	}
+
+	return filteredAccounts
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (obf *outportBlockFilter) IsInterfaceNil() bool {
+	return obf == nil
+}
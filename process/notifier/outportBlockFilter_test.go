@@ -0,0 +1,164 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/smartContractResult"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutportBlockFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		filter, err := NewOutportBlockFilter(createArgs().SubscribedEvents)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(filter))
+	})
+
+	t.Run("no subscribed events, should return error", func(t *testing.T) {
+		filter, err := NewOutportBlockFilter(nil)
+		require.Equal(t, errNoSubscribedEvent, err)
+		require.Nil(t, filter)
+	})
+}
+
+func TestOutportBlockFilter_FilterOutportBlock(t *testing.T) {
+	t.Parallel()
+
+	filter, _ := NewOutportBlockFilter(createArgs().SubscribedEvents)
+
+	t.Run("nil outport block or block data should be returned unchanged", func(t *testing.T) {
+		require.Nil(t, filter.FilterOutportBlock(nil))
+
+		outportBlock := &outport.OutportBlock{}
+		require.True(t, outportBlock == filter.FilterOutportBlock(outportBlock))
+	})
+
+	t.Run("should keep only the notified data", func(t *testing.T) {
+		marshaller := &testscommon.MarshallerMock{}
+		outportBlock := createOutportBlockWithEvents(marshaller, 4, true)
+		outportBlock.BlockData.HeaderHash = []byte("hash")
+		outportBlock.BlockData.Body = &block.Body{MiniBlocks: []*block.MiniBlock{{TxHashes: [][]byte{[]byte("txHash")}}}}
+		outportBlock.SignersIndexes = []uint64{1, 2}
+		subscribedEvent := outportBlock.TransactionPool.Logs[0].Log.Events[0]
+		outportBlock.TransactionPool.Logs[0].Log.Events = append(outportBlock.TransactionPool.Logs[0].Log.Events,
+			&transaction.Event{Address: []byte("encodedAddr"), Identifier: []byte("other identifier")})
+		scrLog := &outport.LogData{
+			TxHash: "scrHash2",
+			Log:    &transaction.Log{Events: []*transaction.Event{{Address: []byte("encodedAddr"), Identifier: identifier}}},
+		}
+		rewardLog := &outport.LogData{
+			TxHash: "rewardHash",
+			Log:    &transaction.Log{Events: []*transaction.Event{{Address: []byte("encodedAddr"), Identifier: identifier}}},
+		}
+		outportBlock.TransactionPool.Logs = append(outportBlock.TransactionPool.Logs, &outport.LogData{
+			TxHash: "txHash2",
+			Log:    &transaction.Log{Events: []*transaction.Event{{Address: []byte("addr"), Identifier: identifier}}},
+		}, scrLog, rewardLog)
+		subscribedTx := &outport.TxInfo{Transaction: &transaction.Transaction{RcvAddr: []byte("encodedAddr")}, ExecutionOrder: 3}
+		outportBlock.TransactionPool.Transactions = map[string]*outport.TxInfo{
+			"txHash":  subscribedTx,
+			"txHash2": {Transaction: &transaction.Transaction{RcvAddr: []byte("addr")}, ExecutionOrder: 1},
+			"txHash3": {},
+		}
+		subscribedSCR := &outport.SCRInfo{SmartContractResult: &smartContractResult.SmartContractResult{RcvAddr: []byte("encodedAddr")}}
+		outportBlock.TransactionPool.SmartContractResults = map[string]*outport.SCRInfo{
+			"scrHash":  subscribedSCR,
+			"scrHash2": {SmartContractResult: &smartContractResult.SmartContractResult{RcvAddr: []byte("addr")}, ExecutionOrder: 2},
+		}
+		outportBlock.TransactionPool.Rewards = map[string]*outport.RewardInfo{
+			"rewardHash":  {ExecutionOrder: 0},
+			"rewardHash2": {ExecutionOrder: 4},
+		}
+		subscribedAccount := &alteredAccount.AlteredAccount{Address: "decodedAddr", Nonce: 1}
+		outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+			"decodedAddr": subscribedAccount,
+			"other":       {Address: "other"},
+		}
+
+		filteredBlock := filter.FilterOutportBlock(outportBlock)
+		require.Equal(t, &outport.OutportBlock{
+			BlockData: &outport.BlockData{
+				HeaderBytes: outportBlock.BlockData.HeaderBytes,
+				HeaderType:  outportBlock.BlockData.HeaderType,
+				HeaderHash:  []byte("hash"),
+				Body:        outportBlock.BlockData.Body,
+			},
+			TransactionPool: &outport.TransactionPool{
+				Transactions: map[string]*outport.TxInfo{"txHash": subscribedTx},
+				// only the execution order is kept for the transactions with subscribed events
+				SmartContractResults: map[string]*outport.SCRInfo{
+					"scrHash":  subscribedSCR,
+					"scrHash2": {ExecutionOrder: 2},
+				},
+				Rewards: map[string]*outport.RewardInfo{"rewardHash": {ExecutionOrder: 0}},
+				Logs: []*outport.LogData{
+					{
+						TxHash: "txHash",
+						Log:    &transaction.Log{Events: []*transaction.Event{subscribedEvent}},
+					},
+					scrLog,
+					rewardLog,
+				},
+			},
+			AlteredAccounts: map[string]*alteredAccount.AlteredAccount{"decodedAddr": subscribedAccount},
+			SignersIndexes:  []uint64{1, 2},
+		}, filteredBlock)
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		fullBlockHash, err := sn.ComputeIncomingHeaderHash(outportBlock)
+		require.Nil(t, err)
+		filteredBlockHash, err := sn.ComputeIncomingHeaderHash(filteredBlock)
+		require.Nil(t, err)
+		require.Equal(t, fullBlockHash, filteredBlockHash)
+		matcher := newEventsMatcher(createArgs().SubscribedEvents)
+		require.Equal(t, createIncomingMiniBlocks(outportBlock, matcher), createIncomingMiniBlocks(filteredBlock, matcher))
+	})
+}
+
+func TestOutportBlockFilter_UpdateSubscribedEvents(t *testing.T) {
+	t.Parallel()
+
+	filter, _ := NewOutportBlockFilter(createArgs().SubscribedEvents)
+
+	outportBlock := createOutportBlockWithEvents(&testscommon.MarshallerMock{}, 4, true)
+	newEvent := &transaction.Event{Address: []byte("newAddr"), Identifier: []byte("newIdentifier")}
+	outportBlock.TransactionPool.Logs[0].Log.Events = append(outportBlock.TransactionPool.Logs[0].Log.Events, newEvent)
+	newAccount := &alteredAccount.AlteredAccount{Address: "erd1new"}
+	outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+		"decodedAddr": {Address: "decodedAddr"},
+		"erd1new":     newAccount,
+	}
+
+	err := filter.UpdateSubscribedEvents(nil)
+	require.Equal(t, errNoSubscribedEvent, err)
+
+	err = filter.UpdateSubscribedEvents([]SubscribedEvent{
+		{
+			Identifier: []byte("newIdentifier"),
+			Addresses:  map[string]string{"newAddr": "erd1new"},
+		},
+	})
+	require.Nil(t, err)
+
+	filteredBlock := filter.FilterOutportBlock(outportBlock)
+	require.Equal(t, []*transaction.Event{newEvent}, filteredBlock.TransactionPool.Logs[0].Log.Events)
+	require.Equal(t, map[string]*alteredAccount.AlteredAccount{"erd1new": newAccount}, filteredBlock.AlteredAccounts)
+}
+
+func TestDisabledOutportBlockFilter_FilterOutportBlock(t *testing.T) {
+	t.Parallel()
+
+	filter := NewDisabledOutportBlockFilter()
+	require.False(t, check.IfNil(filter))
+
+	outportBlock := createOutportBlockWithEvents(&testscommon.MarshallerMock{}, 4, false)
+	require.True(t, outportBlock == filter.FilterOutportBlock(outportBlock))
+}
@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+)
+
+// shadowMetrics holds the differences found between the events of the active subscriptions and the ones of the shadow
+// subscriptions, since the shadow subscriptions were set
+type shadowMetrics struct {
+	NumComparedBlocks        uint64
+	NumBlocksWithDifferences uint64
+	NumAddedEvents           uint64
+	NumRemovedEvents         uint64
+	LastDifferentHeaderHash  []byte
+}
+
+// comparedHeader holds an incoming header computed for a subscriptions set
+type comparedHeader struct {
+	headerHash []byte
+	events     []*transaction.Event
+	matcher    *eventsMatcher
+}
+
+// shadowSubscriptions holds a candidate subscriptions set, for which incoming headers are computed for each block and
+// compared with the delivered ones, without being delivered
+type shadowSubscriptions struct {
+	mutShadow sync.RWMutex
+	matcher   *eventsMatcher
+	metrics   shadowMetrics
+}
+
+func newShadowSubscriptions(subscribedEvents []SubscribedEvent) *shadowSubscriptions {
+	shadow := &shadowSubscriptions{}
+	shadow.setSubscribedEvents(subscribedEvents)
+
+	return shadow
+}
+
+// setSubscribedEvents replaces the shadow subscriptions and resets the metrics, which are logged for the replaced
+// subscriptions. No subscribed events disable the shadow comparison
+func (ss *shadowSubscriptions) setSubscribedEvents(subscribedEvents []SubscribedEvent) {
+	var matcher *eventsMatcher
+	if len(subscribedEvents) != 0 {
+		matcher = newEventsMatcher(subscribedEvents)
+	}
+
+	ss.mutShadow.Lock()
+	replacedMatcher := ss.matcher
+	metrics := ss.metrics
+	ss.matcher = matcher
+	ss.metrics = shadowMetrics{}
+	ss.mutShadow.Unlock()
+
+	if replacedMatcher != nil {
+		log.Info("shadow subscriptions: replaced, differences found since they were set",
+			"num compared blocks", metrics.NumComparedBlocks,
+			"num blocks with differences", metrics.NumBlocksWithDifferences,
+			"num added events", metrics.NumAddedEvents,
+			"num removed events", metrics.NumRemovedEvents,
+			"last different header hash", hex.EncodeToString(metrics.LastDifferentHeaderHash))
+	}
+}
+
+func (ss *shadowSubscriptions) getEventsMatcher() *eventsMatcher {
+	ss.mutShadow.RLock()
+	defer ss.mutShadow.RUnlock()
+
+	return ss.matcher
+}
+
+// getMetrics returns the differences found since the shadow subscriptions were set, which are also logged for each block
+// with differences and when the shadow subscriptions are replaced
+func (ss *shadowSubscriptions) getMetrics() shadowMetrics {
+	ss.mutShadow.RLock()
+	defer ss.mutShadow.RUnlock()
+
+	return ss.metrics
+}
+
+// compare reports the events notified only with the shadow subscriptions as added, and the ones notified only with
+// the active subscriptions as removed
+func (ss *shadowSubscriptions) compare(headerHash []byte, active *comparedHeader, shadow *comparedHeader) {
+	addedEvents, removedEvents := DiffEvents(shadow.events, active.events)
+
+	ss.mutShadow.Lock()
+	// the shadow subscriptions might have been replaced meanwhile, in which case the comparison is outdated
+	if ss.matcher != shadow.matcher {
+		ss.mutShadow.Unlock()
+		return
+	}
+
+	ss.metrics.NumComparedBlocks++
+	hasDifferences := len(addedEvents) != 0 || len(removedEvents) != 0
+	if hasDifferences {
+		ss.metrics.NumBlocksWithDifferences++
+		ss.metrics.NumAddedEvents += uint64(len(addedEvents))
+		ss.metrics.NumRemovedEvents += uint64(len(removedEvents))
+		ss.metrics.LastDifferentHeaderHash = headerHash
+	}
+	metrics := ss.metrics
+	ss.mutShadow.Unlock()
+
+	if !hasDifferences {
+		log.Trace("shadow subscriptions: same events", "header hash", hex.EncodeToString(headerHash), "num events", len(active.events))
+		return
+	}
+
+	log.Info("shadow subscriptions: events differ from the active subscriptions",
+		"header hash", hex.EncodeToString(headerHash),
+		"incoming header hash", hex.EncodeToString(active.headerHash),
+		"shadow incoming header hash", hex.EncodeToString(shadow.headerHash),
+		"num active events", len(active.events),
+		"num shadow events", len(shadow.events),
+		"num added events", len(addedEvents),
+		"num removed events", len(removedEvents),
+		"num compared blocks", metrics.NumComparedBlocks,
+		"num blocks with differences", metrics.NumBlocksWithDifferences)
+	for _, event := range addedEvents {
+		log.Info("shadow subscriptions: added event",
+			"identifier", string(event.GetIdentifier()),
+			"address", shadow.matcher.subscribedAddresses[string(event.GetAddress())])
+	}
+	for _, event := range removedEvents {
+		log.Info("shadow subscriptions: removed event",
+			"identifier", string(event.GetIdentifier()),
+			"address", active.matcher.subscribedAddresses[string(event.GetAddress())])
+	}
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// getUnreachableAddresses returns the encoded subscribed addresses which are not in one of the shards served by the
+// observers, mapped to their shard. Events of these addresses can never be received. If no observer shard is
+// provided, the check is skipped
+func getUnreachableAddresses(
+	subscribedEvents []SubscribedEvent,
+	shardCoordinator process.ShardCoordinator,
+	observerShardIDs []uint32,
+) map[string]uint32 {
+	unreachableAddresses := make(map[string]uint32)
+	if len(observerShardIDs) == 0 {
+		return unreachableAddresses
+	}
+
+	for _, event := range subscribedEvents {
+		for decodedAddr, encodedAddr := range event.Addresses {
+			shardID := shardCoordinator.ComputeId([]byte(decodedAddr))
+			if !containsShard(observerShardIDs, shardID) {
+				unreachableAddresses[encodedAddr] = shardID
+			}
+		}
+	}
+
+	return unreachableAddresses
+}
+
+func warnUnreachableSubscriptions(
+	subscribedEvents []SubscribedEvent,
+	shardCoordinator process.ShardCoordinator,
+	observerShardIDs []uint32,
+) {
+	unreachableAddresses := getUnreachableAddresses(subscribedEvents, shardCoordinator, observerShardIDs)
+	for encodedAddr, shardID := range unreachableAddresses {
+		log.Warn("subscribed address is not in a shard served by the observers, its events can never be received",
+			"address", encodedAddr, "shard", shardID, "observer shards", observerShardIDs)
+	}
+}
+
+func containsShard(shardIDs []uint32, shardID uint32) bool {
+	for _, id := range shardIDs {
+		if id == shardID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUnreachableAddresses(t *testing.T) {
+	t.Parallel()
+
+	subscribedEvents := []SubscribedEvent{
+		{
+			Identifier: []byte("deposit"),
+			Addresses:  map[string]string{"addr0": "erd0", "addr1": "erd1"},
+		},
+		{
+			Identifier: []byte("execute"),
+			Addresses:  map[string]string{"addr2": "erd2"},
+		},
+	}
+	shardCoordinator := &testscommon.ShardCoordinatorStub{
+		ComputeIdCalled: func(address []byte) uint32 {
+			return uint32(address[len(address)-1] - '0')
+		},
+	}
+
+	t.Run("no observer shards, should skip the check", func(t *testing.T) {
+		t.Parallel()
+
+		unreachableAddresses := getUnreachableAddresses(subscribedEvents, shardCoordinator, nil)
+		require.Empty(t, unreachableAddresses)
+	})
+
+	t.Run("should return the addresses of the shards not served by observers", func(t *testing.T) {
+		t.Parallel()
+
+		unreachableAddresses := getUnreachableAddresses(subscribedEvents, shardCoordinator, []uint32{1})
+		require.Equal(t, map[string]uint32{"erd0": 0, "erd2": 2}, unreachableAddresses)
+
+		unreachableAddresses = getUnreachableAddresses(subscribedEvents, shardCoordinator, []uint32{0, 1, 2})
+		require.Empty(t, unreachableAddresses)
+	})
+}
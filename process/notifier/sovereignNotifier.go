@@ -1,8 +1,9 @@
 package notifier
 
 import (
-	"bytes"
+	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -18,25 +19,44 @@ import (
 
 var log = logger.GetOrCreate("notifier-sovereign-process")
 
+// minLogsForParallelExtraction is the minimum number of logs in a block for which the events are extracted in parallel
+const minLogsForParallelExtraction = 128
+
 // SubscribedEvent contains a subscribed event from the main chain that the notifier is watching
 type SubscribedEvent struct {
 	Identifier []byte
 	Addresses  map[string]string
 }
 
-// ArgsSovereignNotifier is a struct placeholder for args needed to create a sovereign notifier
+// ArgsSovereignNotifier is a struct placeholder for args needed to create a sovereign notifier. If the number of
+// extraction workers is greater than 1, the events of large blocks are extracted in parallel. If the observer shard ids
+// are provided, subscribed addresses which are not in one of these shards are reported at creation. The shadow
+// subscribed events are optional and are never delivered
 type ArgsSovereignNotifier struct {
-	Marshaller       marshal.Marshalizer
-	Hasher           hashing.Hasher
-	SubscribedEvents []SubscribedEvent
+	Marshaller             marshal.Marshalizer
+	Hasher                 hashing.Hasher
+	SubscribedEvents       []SubscribedEvent
+	ShadowSubscribedEvents []SubscribedEvent
+	AccountsTracker        process.AccountsTracker
+	HeaderVerifier         process.HeaderVerifier
+	ShardCoordinator       process.ShardCoordinator
+	ObserverShardIDs       []uint32
+	NumExtractionWorkers   uint32
 }
 
 type sovereignNotifier struct {
-	headersNotifier  *headersNotifier
-	subscribedEvents []SubscribedEvent
-	headerV2Creator  block.EmptyBlockCreator
-	marshaller       marshal.Marshalizer
-	hasher           hashing.Hasher
+	headersNotifier      *headersNotifier
+	mutEventsMatcher     sync.RWMutex
+	eventsMatcher        *eventsMatcher
+	shadow               *shadowSubscriptions
+	headerV2Creator      block.EmptyBlockCreator
+	marshaller           marshal.Marshalizer
+	hasher               hashing.Hasher
+	accountsTracker      process.AccountsTracker
+	headerVerifier       process.HeaderVerifier
+	shardCoordinator     process.ShardCoordinator
+	observerShardIDs     []uint32
+	numExtractionWorkers int
 }
 
 // NewSovereignNotifier will create a sovereign shard notifier
@@ -47,20 +67,51 @@ func NewSovereignNotifier(args ArgsSovereignNotifier) (*sovereignNotifier, error
 	if check.IfNil(args.Hasher) {
 		return nil, errNilHasher
 	}
+	if check.IfNil(args.AccountsTracker) {
+		return nil, errNilAccountsTracker
+	}
+	if check.IfNil(args.HeaderVerifier) {
+		return nil, errNilHeaderVerifier
+	}
+	if check.IfNil(args.ShardCoordinator) {
+		return nil, errNilShardCoordinator
+	}
 	err := checkEvents(args.SubscribedEvents)
 	if err != nil {
 		return nil, err
 	}
+	err = checkShadowEvents(args.ShadowSubscribedEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	warnUnreachableSubscriptions(args.SubscribedEvents, args.ShardCoordinator, args.ObserverShardIDs)
 
 	return &sovereignNotifier{
-		subscribedEvents: args.SubscribedEvents,
-		headersNotifier:  newHeadersNotifier(),
-		headerV2Creator:  block.NewEmptyHeaderV2Creator(),
-		marshaller:       args.Marshaller,
-		hasher:           args.Hasher,
+		eventsMatcher:        newEventsMatcher(args.SubscribedEvents),
+		shadow:               newShadowSubscriptions(args.ShadowSubscribedEvents),
+		headersNotifier:      newHeadersNotifier(),
+		headerV2Creator:      block.NewEmptyHeaderV2Creator(),
+		marshaller:           args.Marshaller,
+		hasher:               args.Hasher,
+		accountsTracker:      args.AccountsTracker,
+		headerVerifier:       args.HeaderVerifier,
+		shardCoordinator:     args.ShardCoordinator,
+		observerShardIDs:     args.ObserverShardIDs,
+		numExtractionWorkers: int(args.NumExtractionWorkers),
 	}, nil
 }
 
+// CheckSubscribedEvents validates the subscribed events, which should have an identifier and addresses
+func CheckSubscribedEvents(events []SubscribedEvent) error {
+	return checkEvents(events)
+}
+
+// CheckShadowSubscribedEvents validates the shadow subscribed events, which are optional
+func CheckShadowSubscribedEvents(events []SubscribedEvent) error {
+	return checkShadowEvents(events)
+}
+
 func checkEvents(events []SubscribedEvent) error {
 	if len(events) == 0 {
 		return errNoSubscribedEvent
@@ -83,6 +134,20 @@ func checkEvents(events []SubscribedEvent) error {
 	return nil
 }
 
+// checkShadowEvents validates the shadow subscribed events, which are optional
+func checkShadowEvents(events []SubscribedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := checkEvents(events)
+	if err != nil {
+		return fmt.Errorf("%w for shadow subscriptions", err)
+	}
+
+	return nil
+}
+
 func checkEmptyAddresses(addresses map[string]string) error {
 	if len(addresses) == 0 {
 		return errNoSubscribedAddresses
@@ -101,29 +166,157 @@ func checkEmptyAddresses(addresses map[string]string) error {
 
 // Notify will notify the sovereign nodes about the finalized block and incoming mb txs
 // For each subscribed address, it searches if the receiver is found in transaction pool.
-// If found, IncomingMiniBlocks will contain the ordered tx hashes by execution.
+// If found, the incoming miniblocks will contain the ordered tx hashes by execution. They are notified after the header
+// to subscribers which also implement process.MiniBlocksSubscriber, and are not part of the incoming header hash.
+// Incoming events are ordered by the execution order of their transactions, then by log and event index.
+// Subscribers which also implement process.AccountsSubscriber will receive the state of subscribed accounts updated with
+// the block's altered accounts. Errors caused by the block contents wrap process.ErrInvalidOutportBlock, while the
+// subscribers errors are returned as they are.
 func (notifier *sovereignNotifier) Notify(outportBlock *outport.OutportBlock) error {
 	err := checkNilOutportBlockFields(outportBlock)
 	if err != nil {
-		return err
+		return invalidOutportBlockError(err)
 	}
 
 	headerV2, err := notifier.getHeaderV2(core.HeaderType(outportBlock.BlockData.HeaderType), outportBlock.BlockData.HeaderBytes)
+	if err != nil {
+		return invalidOutportBlockError(err)
+	}
+
+	err = notifier.headerVerifier.VerifyHeader(headerV2, outportBlock.SignersIndexes)
+	if err != nil {
+		return invalidOutportBlockError(fmt.Errorf("%w for header hash: %s", err, hex.EncodeToString(outportBlock.BlockData.HeaderHash)))
+	}
+
+	// the same subscriptions are used for the events and the miniblocks, even if they are updated meanwhile
+	matcher := notifier.getEventsMatcher()
+	extendedHeader, headerHash, err := notifier.createIncomingHeader(headerV2, outportBlock, matcher)
+	if err != nil {
+		return invalidOutportBlockError(err)
+	}
+
+	notifier.compareShadowSubscriptions(headerV2, outportBlock, &comparedHeader{
+		headerHash: headerHash,
+		events:     extendedHeader.IncomingEvents,
+		matcher:    matcher,
+	})
+
+	return notifier.headersNotifier.notifyHeaderSubscribers(
+		extendedHeader,
+		headerHash,
+		createIncomingMiniBlocks(outportBlock, matcher),
+		notifier.accountsTracker.GetUpdatedAccounts(outportBlock.AlteredAccounts),
+	)
+}
+
+// ComputeIncomingHeaderHash computes the hash of the incoming header which would be notified for the outport block,
+// without verifying the header signature
+func (notifier *sovereignNotifier) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	err := checkNilOutportBlockFields(outportBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	headerV2, err := notifier.getHeaderV2(core.HeaderType(outportBlock.BlockData.HeaderType), outportBlock.BlockData.HeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	_, headerHash, err := notifier.createIncomingHeader(headerV2, outportBlock, notifier.getEventsMatcher())
+	return headerHash, err
+}
+
+// UpdateSubscribedEvents will validate and replace the subscribed events. Blocks notified afterwards will contain the
+// events and miniblocks of the new subscriptions
+func (notifier *sovereignNotifier) UpdateSubscribedEvents(subscribedEvents []SubscribedEvent) error {
+	err := checkEvents(subscribedEvents)
+	if err != nil {
+		return err
+	}
+
+	warnUnreachableSubscriptions(subscribedEvents, notifier.shardCoordinator, notifier.observerShardIDs)
+
+	matcher := newEventsMatcher(subscribedEvents)
+	notifier.mutEventsMatcher.Lock()
+	notifier.eventsMatcher = matcher
+	notifier.mutEventsMatcher.Unlock()
+
+	log.Info("updated sovereign notifier subscribed events", "num subscribed events", len(subscribedEvents))
+
+	return nil
+}
+
+// UpdateShadowSubscribedEvents will validate and replace the shadow subscribed events, and reset the shadow metrics.
+// If no events are provided, the shadow comparison is disabled
+func (notifier *sovereignNotifier) UpdateShadowSubscribedEvents(shadowSubscribedEvents []SubscribedEvent) error {
+	err := checkShadowEvents(shadowSubscribedEvents)
 	if err != nil {
 		return err
 	}
 
+	notifier.shadow.setSubscribedEvents(shadowSubscribedEvents)
+	log.Info("updated sovereign notifier shadow subscribed events", "num shadow subscribed events", len(shadowSubscribedEvents))
+
+	return nil
+}
+
+// compareShadowSubscriptions computes the incoming header of the shadow subscriptions, if any, and reports its
+// differences from the delivered one. Failures are only logged, since the shadow header is never delivered
+func (notifier *sovereignNotifier) compareShadowSubscriptions(
+	headerV2 *block.HeaderV2,
+	outportBlock *outport.OutportBlock,
+	active *comparedHeader,
+) {
+	shadowMatcher := notifier.shadow.getEventsMatcher()
+	if shadowMatcher == nil {
+		return
+	}
+
+	shadowHeader, shadowHeaderHash, err := notifier.createIncomingHeader(headerV2, outportBlock, shadowMatcher)
+	if err != nil {
+		log.Warn("could not create the shadow incoming header",
+			"header hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash), "error", err)
+		return
+	}
+
+	notifier.shadow.compare(outportBlock.BlockData.HeaderHash, active, &comparedHeader{
+		headerHash: shadowHeaderHash,
+		events:     shadowHeader.IncomingEvents,
+		matcher:    shadowMatcher,
+	})
+}
+
+func (notifier *sovereignNotifier) getEventsMatcher() *eventsMatcher {
+	notifier.mutEventsMatcher.RLock()
+	defer notifier.mutEventsMatcher.RUnlock()
+
+	return notifier.eventsMatcher
+}
+
+// createIncomingHeader creates the incoming header with the events in canonical order, so that its hash does not
+// depend on the order in which the observer serialized the logs
+func (notifier *sovereignNotifier) createIncomingHeader(
+	headerV2 *block.HeaderV2,
+	outportBlock *outport.OutportBlock,
+	matcher *eventsMatcher,
+) (*sovereign.IncomingHeader, []byte, error) {
+	logsData := getLogsInCanonicalOrder(outportBlock.TransactionPool)
 	extendedHeader := &sovereign.IncomingHeader{
 		Header:         headerV2,
-		IncomingEvents: notifier.createIncomingEvents(outportBlock.TransactionPool.Logs),
+		IncomingEvents: notifier.createIncomingEvents(logsData, matcher),
 	}
 
 	headerHash, err := core.CalculateHash(notifier.marshaller, notifier.hasher, extendedHeader)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	return notifier.headersNotifier.notifyHeaderSubscribers(extendedHeader, headerHash)
+	return extendedHeader, headerHash, nil
+}
+
+// invalidOutportBlockError marks the errors caused by the outport block contents, which would fail any retry
+func invalidOutportBlockError(err error) error {
+	return fmt.Errorf("%w: %w", process.ErrInvalidOutportBlock, err)
 }
 
 func checkNilOutportBlockFields(outportBlock *outport.OutportBlock) error {
@@ -140,48 +333,55 @@ func checkNilOutportBlockFields(outportBlock *outport.OutportBlock) error {
 	return nil
 }
 
-func (notifier *sovereignNotifier) createIncomingEvents(logsData []*outport.LogData) []*transaction.Event {
-	incomingEvents := make([]*transaction.Event, 0)
-
-	for _, logData := range logsData {
-		eventsFromLog := notifier.getIncomingEvents(logData)
-		incomingEvents = append(incomingEvents, eventsFromLog...)
+// createIncomingEvents extracts the subscribed events from the logs, in the order of the logs. If enabled and the pool
+// is large enough, the logs are split in contiguous chunks scanned in parallel, which keeps the output deterministic
+func (notifier *sovereignNotifier) createIncomingEvents(logsData []*outport.LogData, matcher *eventsMatcher) []*transaction.Event {
+	numWorkers := notifier.numExtractionWorkers
+	if numWorkers <= 1 || len(logsData) < minLogsForParallelExtraction {
+		return getIncomingEventsFromLogs(logsData, matcher)
 	}
 
-	return incomingEvents
-}
-
-func (notifier *sovereignNotifier) getIncomingEvents(logData *outport.LogData) []*transaction.Event {
-	incomingEvents := make([]*transaction.Event, 0)
-
-	for _, event := range logData.GetLog().Events {
-		if !notifier.isSubscribed(event, logData.TxHash) {
-			continue
+	chunkSize := (len(logsData) + numWorkers - 1) / numWorkers
+	numChunks := (len(logsData) + chunkSize - 1) / chunkSize
+	eventsByChunk := make([][]*transaction.Event, numChunks)
+	wg := sync.WaitGroup{}
+	wg.Add(numChunks)
+	for chunkIdx := 0; chunkIdx < numChunks; chunkIdx++ {
+		start := chunkIdx * chunkSize
+		end := start + chunkSize
+		if end > len(logsData) {
+			end = len(logsData)
 		}
 
-		incomingEvents = append(incomingEvents, event)
+		go func(idx int, chunk []*outport.LogData) {
+			defer wg.Done()
+			eventsByChunk[idx] = getIncomingEventsFromLogs(chunk, matcher)
+		}(chunkIdx, logsData[start:end])
+	}
+	wg.Wait()
+
+	incomingEvents := make([]*transaction.Event, 0)
+	for _, chunkEvents := range eventsByChunk {
+		incomingEvents = append(incomingEvents, chunkEvents...)
 	}
 
 	return incomingEvents
 }
 
-func (notifier *sovereignNotifier) isSubscribed(event *transaction.Event, txHash string) bool {
-	for _, subEvent := range notifier.subscribedEvents {
-		if !bytes.Equal(event.GetIdentifier(), subEvent.Identifier) {
-			continue
-		}
+func getIncomingEventsFromLogs(logsData []*outport.LogData, matcher *eventsMatcher) []*transaction.Event {
+	incomingEvents := make([]*transaction.Event, 0)
 
-		receiver := event.GetAddress()
-		encodedAddr, found := subEvent.Addresses[string(receiver)]
-		if !found {
-			continue
-		}
+	for _, logData := range logsData {
+		for _, event := range logData.GetLog().GetEvents() {
+			if !matcher.isSubscribed(event, logData.TxHash) {
+				continue
+			}
 
-		log.Trace("found incoming event", "original tx hash", txHash, "receiver", encodedAddr)
-		return true
+			incomingEvents = append(incomingEvents, event)
+		}
 	}
 
-	return false
+	return incomingEvents
 }
 
 func (notifier *sovereignNotifier) getHeaderV2(headerType core.HeaderType, headerBytes []byte) (*block.HeaderV2, error) {
@@ -198,9 +398,33 @@ func (notifier *sovereignNotifier) getHeaderV2(headerType core.HeaderType, heade
 	return headerHandler.(*block.HeaderV2), nil
 }
 
-// RegisterHandler will register an extended header handler to be notified about incoming headers and miniblocks
+// RegisterHandler will register an extended header handler to be notified about every incoming header
 func (notifier *sovereignNotifier) RegisterHandler(handler process.IncomingHeaderSubscriber) error {
-	return notifier.headersNotifier.registerSubscriber(handler)
+	return notifier.RegisterHandlerWithPolicy(handler, process.NotificationPolicy{Mode: process.NotifyAllHeaders})
+}
+
+// RegisterHandlerWithPolicy will register an extended header handler to be notified about incoming headers
+// according to the provided notification policy
+func (notifier *sovereignNotifier) RegisterHandlerWithPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	for _, shardID := range policy.ShardIDs {
+		if len(notifier.observerShardIDs) != 0 && !containsShard(notifier.observerShardIDs, shardID) {
+			log.Warn("subscriber registered for a shard which is not served by the observers",
+				"shard", shardID, "observer shards", notifier.observerShardIDs)
+		}
+	}
+
+	return notifier.headersNotifier.registerSubscriber(handler, policy)
+}
+
+// NotifyHeartbeat will notify the subscribers which also implement process.HeartbeatSubscriber that the main chain
+// feed is alive
+func (notifier *sovereignNotifier) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	return notifier.headersNotifier.notifyHeartbeatSubscribers(heartbeat)
+}
+
+// Close will notify the pending headers batches of the subscribers with a batched notification policy
+func (notifier *sovereignNotifier) Close() error {
+	return notifier.headersNotifier.close()
 }
 
 // IsInterfaceNil checks if the underlying pointer is nil
@@ -1,20 +1,28 @@
 package notifier
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/smartContractResult"
 	"github.com/multiversx/mx-chain-core-go/data/sovereign"
 	"github.com/multiversx/mx-chain-core-go/data/transaction"
 	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
 	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
 	"github.com/stretchr/testify/require"
 )
@@ -32,7 +40,10 @@ func createArgs() ArgsSovereignNotifier {
 				},
 			},
 		},
-		Hasher: sha256.NewSha256(),
+		Hasher:           sha256.NewSha256(),
+		AccountsTracker:  &testscommon.AccountsTrackerStub{},
+		HeaderVerifier:   &testscommon.HeaderVerifierStub{},
+		ShardCoordinator: &testscommon.ShardCoordinatorStub{},
 	}
 }
 
@@ -75,6 +86,30 @@ func TestNewSovereignNotifier(t *testing.T) {
 		require.Nil(t, notif)
 	})
 
+	t.Run("nil accounts tracker, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.AccountsTracker = nil
+		notif, err := NewSovereignNotifier(args)
+		require.Equal(t, errNilAccountsTracker, err)
+		require.Nil(t, notif)
+	})
+
+	t.Run("nil header verifier, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.HeaderVerifier = nil
+		notif, err := NewSovereignNotifier(args)
+		require.Equal(t, errNilHeaderVerifier, err)
+		require.Nil(t, notif)
+	})
+
+	t.Run("nil shard coordinator, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ShardCoordinator = nil
+		notif, err := NewSovereignNotifier(args)
+		require.Equal(t, errNilShardCoordinator, err)
+		require.Nil(t, notif)
+	})
+
 	t.Run("no subscribed address, should return error", func(t *testing.T) {
 		args := createArgs()
 		args.SubscribedEvents = nil
@@ -121,6 +156,14 @@ func TestNewSovereignNotifier(t *testing.T) {
 		require.Nil(t, notif)
 	})
 
+	t.Run("invalid shadow subscribed events, should return error", func(t *testing.T) {
+		args := createArgs()
+		args.ShadowSubscribedEvents = []SubscribedEvent{{Identifier: identifier}}
+		notif, err := NewSovereignNotifier(args)
+		require.ErrorIs(t, err, errNoSubscribedAddresses)
+		require.Contains(t, err.Error(), "for shadow subscriptions")
+		require.Nil(t, notif)
+	})
 }
 
 func TestSovereignNotifier_Notify(t *testing.T) {
@@ -306,21 +349,24 @@ func TestSovereignNotifier_NotifyRegisterHandlerErrorCases(t *testing.T) {
 		sn, _ := NewSovereignNotifier(args)
 
 		err := sn.Notify(nil)
-		require.Equal(t, errNilOutportBlock, err)
+		require.ErrorIs(t, err, errNilOutportBlock)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
 
 		outportBlock := &outport.OutportBlock{
 			BlockData:       createBlockData(args.Marshaller),
 			TransactionPool: nil,
 		}
 		err = sn.Notify(outportBlock)
-		require.Equal(t, errNilTransactionPool, err)
+		require.ErrorIs(t, err, errNilTransactionPool)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
 
 		outportBlock = &outport.OutportBlock{
 			BlockData:       nil,
 			TransactionPool: &outport.TransactionPool{},
 		}
 		err = sn.Notify(outportBlock)
-		require.Equal(t, errNilBlockData, err)
+		require.ErrorIs(t, err, errNilBlockData)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
 	})
 
 	t.Run("notify invalid header type", func(t *testing.T) {
@@ -338,6 +384,7 @@ func TestSovereignNotifier_NotifyRegisterHandlerErrorCases(t *testing.T) {
 		require.NotNil(t, err)
 		require.True(t, strings.Contains(err.Error(), errInvalidHeaderTypeReceived.Error()))
 		require.True(t, strings.Contains(err.Error(), blockData.HeaderType))
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
 	})
 
 	t.Run("notify invalid header bytes", func(t *testing.T) {
@@ -382,7 +429,8 @@ func TestSovereignNotifier_NotifyRegisterHandlerErrorCases(t *testing.T) {
 		}
 
 		err := sn.Notify(outportBlock)
-		require.Equal(t, errMarshal, err)
+		require.ErrorIs(t, err, errMarshal)
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
 		require.Equal(t, 2, marshalCt)
 	})
 
@@ -408,47 +456,346 @@ func TestSovereignNotifier_NotifyRegisterHandlerErrorCases(t *testing.T) {
 		err := sn.Notify(outportBlock)
 		require.Equal(t, errAddHeader, err)
 	})
+
+	t.Run("header signature verification fails, should not notify", func(t *testing.T) {
+		args := createArgs()
+		signersIndexes := []uint64{0, 2}
+		errVerify := errors.New("invalid signature")
+		args.HeaderVerifier = &testscommon.HeaderVerifierStub{
+			VerifyHeaderCalled: func(header data.HeaderHandler, receivedSignersIndexes []uint64) error {
+				require.Equal(t, signersIndexes, receivedSignersIndexes)
+				return errVerify
+			},
+		}
+		sn, _ := NewSovereignNotifier(args)
+
+		wasAddHeaderCalled := false
+		_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				wasAddHeaderCalled = true
+				return nil
+			},
+		})
+
+		blockData := createBlockData(args.Marshaller)
+		blockData.HeaderHash = []byte("hash")
+		outportBlock := &outport.OutportBlock{
+			BlockData:       blockData,
+			TransactionPool: &outport.TransactionPool{},
+			SignersIndexes:  signersIndexes,
+		}
+
+		err := sn.Notify(outportBlock)
+		require.True(t, errors.Is(err, errVerify))
+		require.ErrorIs(t, err, process.ErrInvalidOutportBlock)
+		require.True(t, strings.Contains(err.Error(), hex.EncodeToString(blockData.HeaderHash)))
+		require.False(t, wasAddHeaderCalled)
+	})
+
+	t.Run("accounts subscriber cannot add accounts", func(t *testing.T) {
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		outportBlock := &outport.OutportBlock{
+			BlockData:       createBlockData(args.Marshaller),
+			TransactionPool: &outport.TransactionPool{},
+		}
+
+		errAddAccounts := errors.New("cannot add accounts")
+		subscriber := &testscommon.HeaderAccountsSubscriberStub{
+			AddAccountsCalled: func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+				return errAddAccounts
+			},
+		}
+		_ = sn.RegisterHandler(subscriber)
+
+		err := sn.Notify(outportBlock)
+		require.Equal(t, errAddAccounts, err)
+	})
 }
 
-func TestSovereignNotifier_ConcurrentOperations(t *testing.T) {
+func TestSovereignNotifier_NotifyWithAccountsSubscriber(t *testing.T) {
 	t.Parallel()
 
-	addr1 := []byte("addr1")
-	headerV2 := &block.HeaderV2{
-		Header:            &block.Header{},
-		ScheduledRootHash: []byte("root hash"),
+	subscribedAccounts := map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "1"},
 	}
-	incomingHeader := &sovereign.IncomingHeader{
-		Header: headerV2,
-		IncomingEvents: []*transaction.Event{
-			{
-				Address:    addr1,
-				Identifier: identifier,
-			},
-		},
+	blockAlteredAccounts := map[string]*alteredAccount.AlteredAccount{
+		"erd1a": {Address: "erd1a", Balance: "2"},
 	}
 
 	args := createArgs()
-	args.SubscribedEvents = []SubscribedEvent{
-		{
-			Identifier: identifier,
-			Addresses: map[string]string{
-				string(addr1): string(addr1),
+	args.AccountsTracker = &testscommon.AccountsTrackerStub{
+		GetUpdatedAccountsCalled: func(alteredAccounts map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount {
+			require.Equal(t, blockAlteredAccounts, alteredAccounts)
+			return subscribedAccounts
+		},
+	}
+	sn, _ := NewSovereignNotifier(args)
+
+	var notifiedHeaderHash []byte
+	wasAddAccountsCalled := false
+	accountsSubscriber := &testscommon.HeaderAccountsSubscriberStub{
+		HeaderSubscriberStub: testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedHeaderHash = headerHash
+				return nil
 			},
 		},
+		AddAccountsCalled: func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+			wasAddAccountsCalled = true
+			require.Equal(t, notifiedHeaderHash, headerHash)
+			require.Equal(t, subscribedAccounts, accounts)
+			return nil
+		},
+	}
+	wasAddHeaderCalled := false
+	headerSubscriber := &testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			wasAddHeaderCalled = true
+			return nil
+		},
 	}
+	_ = sn.RegisterHandler(accountsSubscriber)
+	_ = sn.RegisterHandler(headerSubscriber)
 
-	extendedShardHeaderHash, err := core.CalculateHash(args.Marshaller, args.Hasher, incomingHeader)
+	outportBlock := &outport.OutportBlock{
+		BlockData:       createBlockData(args.Marshaller),
+		TransactionPool: &outport.TransactionPool{},
+		AlteredAccounts: blockAlteredAccounts,
+	}
+
+	err := sn.Notify(outportBlock)
 	require.Nil(t, err)
+	require.True(t, wasAddAccountsCalled)
+	require.True(t, wasAddHeaderCalled)
+}
+
+func TestSovereignNotifier_RetriedNotification(t *testing.T) {
+	t.Parallel()
 
+	args := createArgs()
 	sn, _ := NewSovereignNotifier(args)
 
-	headerBytes, err := args.Marshaller.Marshal(headerV2)
+	numAddHeaderCalls := 0
+	numAddAccountsCalls := 0
+	errAddAccounts := errors.New("cannot add accounts")
+	accountsSubscriber := &testscommon.HeaderAccountsSubscriberStub{
+		HeaderSubscriberStub: testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				numAddHeaderCalls++
+				return nil
+			},
+		},
+		AddAccountsCalled: func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+			numAddAccountsCalls++
+			if numAddAccountsCalls == 1 {
+				return errAddAccounts
+			}
+			return nil
+		},
+	}
+	numOtherAddHeaderCalls := 0
+	errAddHeader := errors.New("cannot add header")
+	otherSubscriber := &testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			numOtherAddHeaderCalls++
+			if numOtherAddHeaderCalls == 1 {
+				return errAddHeader
+			}
+			return nil
+		},
+	}
+	_ = sn.RegisterHandler(accountsSubscriber)
+	_ = sn.RegisterHandler(otherSubscriber)
+
+	outportBlock := createOutportBlockWithEvents(args.Marshaller, 1, true)
+	err := sn.Notify(outportBlock)
+	require.Equal(t, errAddAccounts, err)
+	err = sn.Notify(outportBlock)
+	require.Equal(t, errAddHeader, err)
+	err = sn.Notify(outportBlock)
+	require.Nil(t, err)
+	err = sn.Notify(outportBlock)
 	require.Nil(t, err)
 
-	outportBlock := &outport.OutportBlock{
+	// each subscriber receives only the data it did not accept before
+	require.Equal(t, 1, numAddHeaderCalls)
+	require.Equal(t, 2, numAddAccountsCalls)
+	require.Equal(t, 2, numOtherAddHeaderCalls)
+}
+
+func TestSovereignNotifier_NotifyWithMiniBlocksSubscriber(t *testing.T) {
+	t.Parallel()
+
+	tx1, tx2, scr1, scr2 := []byte("tx1"), []byte("tx2"), []byte("scr1"), []byte("scr2")
+	subscribedAddr, otherAddr := []byte("encodedAddr"), []byte("otherAddr")
+	createOutportBlock := func(marshaller marshal.Marshalizer) *outport.OutportBlock {
+		outportBlock := createOutportBlockWithEvents(marshaller, 4, false)
+		outportBlock.BlockData.Body = &block.Body{
+			MiniBlocks: []*block.MiniBlock{
+				{TxHashes: [][]byte{tx1}, SenderShardID: 1, ReceiverShardID: 1, Type: block.TxBlock},
+				{TxHashes: [][]byte{tx2, scr1}, SenderShardID: 0, ReceiverShardID: 1, Type: block.SmartContractResultBlock},
+			},
+		}
+		outportBlock.BlockData.IntraShardMiniBlocks = []*block.MiniBlock{
+			{TxHashes: [][]byte{scr2, scr1}, SenderShardID: 1, ReceiverShardID: 1, Type: block.SmartContractResultBlock},
+		}
+		outportBlock.TransactionPool.Transactions = map[string]*outport.TxInfo{
+			hex.EncodeToString(tx1): {Transaction: &transaction.Transaction{RcvAddr: otherAddr}, ExecutionOrder: 0},
+			hex.EncodeToString(tx2): {Transaction: &transaction.Transaction{RcvAddr: subscribedAddr}, ExecutionOrder: 3},
+		}
+		outportBlock.TransactionPool.SmartContractResults = map[string]*outport.SCRInfo{
+			hex.EncodeToString(scr1): {SmartContractResult: &smartContractResult.SmartContractResult{RcvAddr: subscribedAddr}, ExecutionOrder: 2},
+			hex.EncodeToString(scr2): {SmartContractResult: &smartContractResult.SmartContractResult{RcvAddr: subscribedAddr}, ExecutionOrder: 1},
+		}
+
+		return outportBlock
+	}
+
+	// the miniblock without subscribed receivers is skipped, while the already added scr1 is not duplicated. The tx
+	// hashes and the miniblocks are ordered by the execution order
+	expectedMiniBlocks := []*block.MiniBlock{
+		{TxHashes: [][]byte{scr2}, SenderShardID: 1, ReceiverShardID: 1, Type: block.SmartContractResultBlock},
+		{TxHashes: [][]byte{scr1, tx2}, SenderShardID: 0, ReceiverShardID: 1, Type: block.SmartContractResultBlock},
+	}
+
+	t.Run("should notify miniblocks after the header", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		var notifiedHeaderHash []byte
+		var notifiedMiniBlocks []*block.MiniBlock
+		subscriber := &testscommon.HeaderMiniBlocksSubscriberStub{
+			HeaderSubscriberStub: testscommon.HeaderSubscriberStub{
+				AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+					require.Nil(t, notifiedMiniBlocks)
+					notifiedHeaderHash = headerHash
+					return nil
+				},
+			},
+			AddIncomingMiniBlocksCalled: func(headerHash []byte, miniBlocks []*block.MiniBlock) error {
+				require.Equal(t, notifiedHeaderHash, headerHash)
+				notifiedMiniBlocks = miniBlocks
+				return nil
+			},
+		}
+		_ = sn.RegisterHandler(subscriber)
+
+		outportBlock := createOutportBlock(args.Marshaller)
+		expectedHash, _ := sn.ComputeIncomingHeaderHash(createOutportBlockWithEvents(args.Marshaller, 4, false))
+
+		err := sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, expectedMiniBlocks, notifiedMiniBlocks)
+		require.Equal(t, expectedHash, notifiedHeaderHash, "miniblocks should not change the incoming header hash")
+	})
+
+	t.Run("header without events but with miniblocks should be notified in with events mode", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		var notifiedMiniBlocks []*block.MiniBlock
+		subscriber := &testscommon.HeaderMiniBlocksSubscriberStub{
+			AddIncomingMiniBlocksCalled: func(headerHash []byte, miniBlocks []*block.MiniBlock) error {
+				notifiedMiniBlocks = miniBlocks
+				return nil
+			},
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyHeadersWithEvents})
+
+		err := sn.Notify(createOutportBlock(args.Marshaller))
+		require.Nil(t, err)
+		require.Equal(t, expectedMiniBlocks, notifiedMiniBlocks)
+	})
+
+	t.Run("add incoming miniblocks error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		errAddMiniBlocks := errors.New("add miniblocks error")
+		_ = sn.RegisterHandler(&testscommon.HeaderMiniBlocksSubscriberStub{
+			AddIncomingMiniBlocksCalled: func(headerHash []byte, miniBlocks []*block.MiniBlock) error {
+				return errAddMiniBlocks
+			},
+		})
+
+		err := sn.Notify(createOutportBlock(args.Marshaller))
+		require.Equal(t, errAddMiniBlocks, err)
+	})
+}
+
+func TestSovereignNotifier_NotifyHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	heartbeat := &process.Heartbeat{Round: 4, Epoch: 1}
+
+	t.Run("should notify heartbeat subscribers", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+
+		wasAddHeartbeatCalled := false
+		_ = sn.RegisterHandler(&testscommon.HeaderHeartbeatSubscriberStub{
+			AddHeartbeatCalled: func(receivedHeartbeat *process.Heartbeat) error {
+				wasAddHeartbeatCalled = true
+				require.Equal(t, heartbeat, receivedHeartbeat)
+				return nil
+			},
+		})
+		wasAddHeaderCalled := false
+		_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				wasAddHeaderCalled = true
+				return nil
+			},
+		})
+
+		err := sn.NotifyHeartbeat(heartbeat)
+		require.Nil(t, err)
+		require.True(t, wasAddHeartbeatCalled)
+		require.False(t, wasAddHeaderCalled)
+	})
+
+	t.Run("subscriber cannot add heartbeat, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+
+		errAddHeartbeat := errors.New("cannot add heartbeat")
+		_ = sn.RegisterHandler(&testscommon.HeaderHeartbeatSubscriberStub{
+			AddHeartbeatCalled: func(receivedHeartbeat *process.Heartbeat) error {
+				return errAddHeartbeat
+			},
+		})
+
+		err := sn.NotifyHeartbeat(heartbeat)
+		require.Equal(t, errAddHeartbeat, err)
+	})
+}
+
+func createOutportBlockWithEvents(marshaller marshal.Marshalizer, nonce uint64, withEvents bool) *outport.OutportBlock {
+	headerV2 := &block.HeaderV2{
+		Header: &block.Header{Nonce: nonce},
+	}
+	headerBytes, _ := marshaller.Marshal(headerV2)
+
+	events := make([]*transaction.Event, 0)
+	if withEvents {
+		events = append(events, &transaction.Event{
+			Address:    []byte("encodedAddr"),
+			Identifier: identifier,
+		})
+	}
+
+	return &outport.OutportBlock{
 		BlockData: &outport.BlockData{
-			HeaderHash:  []byte("hash"),
 			HeaderBytes: headerBytes,
 			HeaderType:  string(core.ShardHeaderV2),
 		},
@@ -456,54 +803,923 @@ func TestSovereignNotifier_ConcurrentOperations(t *testing.T) {
 			Logs: []*outport.LogData{
 				{
 					TxHash: "txHash",
-					Log: &transaction.Log{
-						Events: []*transaction.Event{
-							{
-								Address:    addr1,
-								Identifier: identifier,
-							},
-						},
-					},
+					Log:    &transaction.Log{Events: events},
 				},
 			},
 		},
 	}
+}
 
-	n := 100
-	wg := sync.WaitGroup{}
-	for i := 0; i < n; i++ {
-		switch i % 2 {
-		case 0:
-			go func() {
-				errNotify := sn.Notify(outportBlock)
-				require.Nil(t, errNotify)
-			}()
-		case 1:
-			wg.Add(1)
-
-			go func() {
-				defer wg.Done()
+func TestSovereignNotifier_NotifyWithShardsPolicy(t *testing.T) {
+	t.Parallel()
 
-				handler := &testscommon.HeaderSubscriberStub{
-					AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
-						require.Equal(t, extendedShardHeaderHash, headerHash)
-						require.Equal(t, incomingHeader, header)
+	args := createArgs()
+	args.ObserverShardIDs = []uint32{0, 1}
+	sn, _ := NewSovereignNotifier(args)
 
-						return nil
-					},
-				}
+	notifiedShardsBySubscriber := make([][]uint32, 3)
+	policiesShards := [][]uint32{nil, {0}, {1, 2}}
+	for idx, shardIDs := range policiesShards {
+		subscriberIdx := idx
+		err := sn.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				shardID := header.GetHeaderHandler().GetShardID()
+				notifiedShardsBySubscriber[subscriberIdx] = append(notifiedShardsBySubscriber[subscriberIdx], shardID)
+				return nil
+			},
+		}, process.NotificationPolicy{Mode: process.NotifyAllHeaders, ShardIDs: shardIDs})
+		require.Nil(t, err)
+	}
 
-				errRegister := sn.RegisterHandler(handler)
-				require.Nil(t, errRegister)
-			}()
-		default:
-			require.Fail(t, "should not have entered here")
-		}
+	for idx, shardID := range []uint32{0, 1, 0} {
+		headerV2 := &block.HeaderV2{Header: &block.Header{ShardID: shardID, Nonce: uint64(idx)}}
+		headerBytes, _ := args.Marshaller.Marshal(headerV2)
+		err := sn.Notify(&outport.OutportBlock{
+			BlockData: &outport.BlockData{
+				HeaderBytes: headerBytes,
+				HeaderType:  string(core.ShardHeaderV2),
+			},
+			TransactionPool: &outport.TransactionPool{},
+		})
+		require.Nil(t, err)
 	}
 
-	wg.Wait()
+	require.Equal(t, []uint32{0, 1, 0}, notifiedShardsBySubscriber[0])
+	require.Equal(t, []uint32{0, 0}, notifiedShardsBySubscriber[1])
+	require.Equal(t, []uint32{1}, notifiedShardsBySubscriber[2])
+}
 
-	sn.headersNotifier.mutSubscribers.RLock()
-	defer sn.headersNotifier.mutSubscribers.RUnlock()
-	require.Equal(t, n/2, len(sn.headersNotifier.subscribers))
+func TestSovereignNotifier_UpdateSubscribedEvents(t *testing.T) {
+	t.Parallel()
+
+	args := createArgs()
+	sn, _ := NewSovereignNotifier(args)
+
+	var notifiedEvents []data.EventHandler
+	_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+		AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			notifiedEvents = header.GetIncomingEventHandlers()
+			return nil
+		},
+	})
+
+	outportBlock := createOutportBlockWithEvents(args.Marshaller, 4, true)
+	newEvent := &transaction.Event{Address: []byte("newAddr"), Identifier: []byte("newIdentifier")}
+	outportBlock.TransactionPool.Logs[0].Log.Events = append(outportBlock.TransactionPool.Logs[0].Log.Events, newEvent)
+
+	err := sn.Notify(outportBlock)
+	require.Nil(t, err)
+	require.Len(t, notifiedEvents, 1)
+	require.Equal(t, identifier, notifiedEvents[0].GetIdentifier())
+
+	err = sn.UpdateSubscribedEvents(nil)
+	require.Equal(t, errNoSubscribedEvent, err)
+
+	err = sn.UpdateSubscribedEvents([]SubscribedEvent{
+		{
+			Identifier: []byte("newIdentifier"),
+			Addresses:  map[string]string{"newAddr": "erd1new"},
+		},
+	})
+	require.Nil(t, err)
+
+	err = sn.Notify(outportBlock)
+	require.Nil(t, err)
+	require.Equal(t, []data.EventHandler{newEvent}, notifiedEvents)
+}
+
+func TestSovereignNotifier_ShadowSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	outportBlock := createOutportBlockWithEvents(&testscommon.MarshallerMock{}, 4, true)
+	outportBlock.BlockData.HeaderHash = []byte("headerHash")
+	activeEvent := outportBlock.TransactionPool.Logs[0].Log.Events[0]
+	shadowEvent := &transaction.Event{Address: []byte("newAddr"), Identifier: []byte("newIdentifier")}
+	outportBlock.TransactionPool.Logs[0].Log.Events = append(outportBlock.TransactionPool.Logs[0].Log.Events, shadowEvent)
+	shadowSubscribedEvents := []SubscribedEvent{
+		{
+			Identifier: []byte("newIdentifier"),
+			Addresses:  map[string]string{"newAddr": "erd1new"},
+		},
+	}
+
+	t.Run("disabled shadow subscriptions should not compare blocks", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		err := sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, shadowMetrics{}, sn.shadow.getMetrics())
+	})
+
+	t.Run("should deliver only the active events and report the differences", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.ShadowSubscribedEvents = shadowSubscribedEvents
+		sn, err := NewSovereignNotifier(args)
+		require.Nil(t, err)
+
+		var notifiedEvents []data.EventHandler
+		_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedEvents = header.GetIncomingEventHandlers()
+				return nil
+			},
+		})
+
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, []data.EventHandler{activeEvent}, notifiedEvents)
+		require.Equal(t, shadowMetrics{
+			NumComparedBlocks:        1,
+			NumBlocksWithDifferences: 1,
+			NumAddedEvents:           1,
+			NumRemovedEvents:         1,
+			LastDifferentHeaderHash:  []byte("headerHash"),
+		}, sn.shadow.getMetrics())
+
+		err = sn.UpdateShadowSubscribedEvents(append(shadowSubscribedEvents, args.SubscribedEvents...))
+		require.Nil(t, err)
+		require.Equal(t, shadowMetrics{}, sn.shadow.getMetrics())
+
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, shadowMetrics{
+			NumComparedBlocks:        1,
+			NumBlocksWithDifferences: 1,
+			NumAddedEvents:           1,
+			LastDifferentHeaderHash:  []byte("headerHash"),
+		}, sn.shadow.getMetrics())
+
+		err = sn.UpdateShadowSubscribedEvents(args.SubscribedEvents)
+		require.Nil(t, err)
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, shadowMetrics{NumComparedBlocks: 1}, sn.shadow.getMetrics())
+	})
+
+	t.Run("update with invalid or no events", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.ShadowSubscribedEvents = shadowSubscribedEvents
+		sn, _ := NewSovereignNotifier(args)
+
+		err := sn.UpdateShadowSubscribedEvents([]SubscribedEvent{{Addresses: map[string]string{"addr": "erd1addr"}}})
+		require.ErrorIs(t, err, errNoSubscribedIdentifier)
+		require.NotNil(t, sn.shadow.getEventsMatcher())
+
+		err = sn.UpdateShadowSubscribedEvents(nil)
+		require.Nil(t, err)
+		require.Nil(t, sn.shadow.getEventsMatcher())
+
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, shadowMetrics{}, sn.shadow.getMetrics())
+	})
+}
+
+func TestSovereignNotifier_ComputeIncomingHeaderHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the hash of the notified header", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		args.HeaderVerifier = &testscommon.HeaderVerifierStub{
+			VerifyHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64) error {
+				require.Fail(t, "header should not be verified when computing the hash")
+				return nil
+			},
+		}
+		sn, _ := NewSovereignNotifier(args)
+
+		outportBlock := createOutportBlockWithEvents(args.Marshaller, 4, true)
+		hash, err := sn.ComputeIncomingHeaderHash(outportBlock)
+		require.Nil(t, err)
+
+		args.HeaderVerifier = &testscommon.HeaderVerifierStub{}
+		sn, _ = NewSovereignNotifier(args)
+		var notifiedHash []byte
+		_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedHash = headerHash
+				return nil
+			},
+		})
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, notifiedHash, hash)
+	})
+
+	t.Run("invalid outport block, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		hash, err := sn.ComputeIncomingHeaderHash(nil)
+		require.Equal(t, errNilOutportBlock, err)
+		require.Nil(t, hash)
+
+		outportBlock := createOutportBlockWithEvents(&testscommon.MarshallerMock{}, 4, true)
+		outportBlock.BlockData.HeaderType = string(core.ShardHeaderV1)
+		hash, err = sn.ComputeIncomingHeaderHash(outportBlock)
+		require.ErrorIs(t, err, errInvalidHeaderTypeReceived)
+		require.Nil(t, hash)
+	})
+}
+
+func TestSovereignNotifier_RegisterHandlerWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid notification mode, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		err := sn.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{Mode: "mode"})
+		require.True(t, errors.Is(err, errInvalidNotificationMode))
+		require.True(t, strings.Contains(err.Error(), "mode"))
+	})
+
+	t.Run("batched mode without size or interval, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		err := sn.RegisterHandlerWithPolicy(&testscommon.HeadersBatchSubscriberStub{}, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders})
+		require.Equal(t, errInvalidBatchPolicy, err)
+	})
+
+	t.Run("batched mode for a subscriber without batch support, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		policy := process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 2}
+		err := sn.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, policy)
+		require.Equal(t, errNotHeadersBatchSubscriber, err)
+	})
+
+	t.Run("nil subscriber, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		sn, _ := NewSovereignNotifier(createArgs())
+		err := sn.RegisterHandlerWithPolicy(nil, process.NotificationPolicy{Mode: process.NotifyAllHeaders})
+		require.Equal(t, errNilHeaderSubscriber, err)
+	})
+}
+
+func TestSovereignNotifier_NotifyWithPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all headers and headers with events subscribers", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		allNonces := make([]uint64, 0)
+		_ = sn.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				allNonces = append(allNonces, header.GetHeaderHandler().GetNonce())
+				return nil
+			},
+		}, process.NotificationPolicy{Mode: process.NotifyAllHeaders})
+
+		withEventsNonces := make([]uint64, 0)
+		_ = sn.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				require.NotEmpty(t, header.GetIncomingEventHandlers())
+				withEventsNonces = append(withEventsNonces, header.GetHeaderHandler().GetNonce())
+				return nil
+			},
+		}, process.NotificationPolicy{Mode: process.NotifyHeadersWithEvents})
+
+		for nonce := uint64(1); nonce <= 4; nonce++ {
+			err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, nonce, nonce%2 == 0))
+			require.Nil(t, err)
+		}
+
+		require.Equal(t, []uint64{1, 2, 3, 4}, allNonces)
+		require.Equal(t, []uint64{2, 4}, withEventsNonces)
+	})
+
+	t.Run("batched subscriber should receive batches of batch size", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		batches := make([][]uint64, 0)
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				require.Equal(t, len(headers), len(headersHashes))
+
+				nonces := make([]uint64, 0, len(headers))
+				for _, header := range headers {
+					nonces = append(nonces, header.GetHeaderHandler().GetNonce())
+				}
+				batches = append(batches, nonces)
+				return nil
+			},
+		}
+		subscriber.AddHeaderCalled = func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+			require.Fail(t, "should not notify single headers")
+			return nil
+		}
+		policy := process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 2}
+		err := sn.RegisterHandlerWithPolicy(subscriber, policy)
+		require.Nil(t, err)
+
+		for nonce := uint64(1); nonce <= 5; nonce++ {
+			err = sn.Notify(createOutportBlockWithEvents(args.Marshaller, nonce, false))
+			require.Nil(t, err)
+		}
+
+		require.Equal(t, [][]uint64{{1, 2}, {3, 4}}, batches)
+	})
+
+	t.Run("batched subscriber should receive incomplete batch after batch interval", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		mutBatches := sync.Mutex{}
+		batches := make([][]byte, 0)
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				mutBatches.Lock()
+				batches = append(batches, headersHashes...)
+				mutBatches.Unlock()
+				return nil
+			},
+		}
+		policy := process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 10, BatchInterval: 10 * time.Millisecond}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, policy)
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, true))
+		require.Nil(t, err)
+
+		require.Eventually(t, func() bool {
+			mutBatches.Lock()
+			defer mutBatches.Unlock()
+
+			return len(batches) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("batched accounts subscriber should receive accounts for the last header in batch", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		subscribedAccounts := map[string]*alteredAccount.AlteredAccount{
+			"erd1a": {Address: "erd1a", Balance: "1"},
+		}
+		args.AccountsTracker = &testscommon.AccountsTrackerStub{
+			GetUpdatedAccountsCalled: func(_ map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount {
+				return subscribedAccounts
+			},
+		}
+		sn, _ := NewSovereignNotifier(args)
+
+		var lastHash []byte
+		wasAddAccountsCalled := false
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				lastHash = headersHashes[len(headersHashes)-1]
+				return nil
+			},
+		}
+		subscriber.AddAccountsCalled = func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+			wasAddAccountsCalled = true
+			require.Equal(t, lastHash, headerHash)
+			require.Equal(t, subscribedAccounts, accounts)
+			return nil
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 2})
+
+		for nonce := uint64(1); nonce <= 2; nonce++ {
+			err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, nonce, false))
+			require.Nil(t, err)
+		}
+		require.True(t, wasAddAccountsCalled)
+	})
+
+	t.Run("batch subscriber cannot add headers, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		errAddHeaders := errors.New("cannot add headers")
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				return errAddHeaders
+			},
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 1})
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, false))
+		require.Equal(t, errAddHeaders, err)
+	})
+
+	t.Run("failed batch should be kept and notified with the next header", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		errAddHeaders := errors.New("cannot add headers")
+		batches := make([][]uint64, 0)
+		numCalls := 0
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				numCalls++
+				if numCalls == 1 {
+					return errAddHeaders
+				}
+
+				nonces := make([]uint64, 0, len(headers))
+				for _, header := range headers {
+					nonces = append(nonces, header.GetHeaderHandler().GetNonce())
+				}
+				batches = append(batches, nonces)
+				return nil
+			},
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 2})
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, false))
+		require.Nil(t, err)
+		err = sn.Notify(createOutportBlockWithEvents(args.Marshaller, 2, false))
+		require.Equal(t, errAddHeaders, err)
+		err = sn.Notify(createOutportBlockWithEvents(args.Marshaller, 3, false))
+		require.Nil(t, err)
+
+		require.Equal(t, [][]uint64{{1, 2, 3}}, batches)
+	})
+
+	t.Run("retried header should not be added again to the batch", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		batches := make([][]uint64, 0)
+		numCalls := 0
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				numCalls++
+				if numCalls == 1 {
+					return errors.New("cannot add headers")
+				}
+
+				nonces := make([]uint64, 0, len(headers))
+				for _, header := range headers {
+					nonces = append(nonces, header.GetHeaderHandler().GetNonce())
+				}
+				batches = append(batches, nonces)
+				return nil
+			},
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 2})
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, false))
+		require.Nil(t, err)
+		outportBlock := createOutportBlockWithEvents(args.Marshaller, 2, false)
+		err = sn.Notify(outportBlock)
+		require.NotNil(t, err)
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+
+		require.Equal(t, [][]uint64{{1, 2}}, batches)
+		require.Nil(t, sn.Close())
+		require.Len(t, batches, 1)
+	})
+
+	t.Run("batch with failed accounts notification should be kept without notifying its headers again", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		numAddHeadersCalls := 0
+		numAddAccountsCalls := 0
+		errAddAccounts := errors.New("cannot add accounts")
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				numAddHeadersCalls++
+				return nil
+			},
+		}
+		subscriber.AddAccountsCalled = func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+			numAddAccountsCalls++
+			if numAddAccountsCalls == 1 {
+				return errAddAccounts
+			}
+			return nil
+		}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 1})
+
+		outportBlock := createOutportBlockWithEvents(args.Marshaller, 1, false)
+		err := sn.Notify(outportBlock)
+		require.Equal(t, errAddAccounts, err)
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+		require.Nil(t, sn.Close())
+
+		require.Equal(t, 1, numAddHeadersCalls)
+		require.Equal(t, 2, numAddAccountsCalls)
+	})
+
+	t.Run("failed batch on timeout should be retried", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		mutBatches := sync.Mutex{}
+		numCalls := 0
+		batches := make([][]byte, 0)
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				mutBatches.Lock()
+				defer mutBatches.Unlock()
+
+				numCalls++
+				if numCalls == 1 {
+					return errors.New("cannot add headers")
+				}
+
+				batches = append(batches, headersHashes...)
+				return nil
+			},
+		}
+		policy := process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 10, BatchInterval: 10 * time.Millisecond}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, policy)
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, true))
+		require.Nil(t, err)
+
+		require.Eventually(t, func() bool {
+			mutBatches.Lock()
+			defer mutBatches.Unlock()
+
+			return len(batches) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("pending batch should be notified on close", func(t *testing.T) {
+		t.Parallel()
+
+		args := createArgs()
+		sn, _ := NewSovereignNotifier(args)
+
+		batches := make([][]byte, 0)
+		subscriber := &testscommon.HeadersBatchSubscriberStub{
+			AddHeadersCalled: func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+				batches = append(batches, headersHashes...)
+				return nil
+			},
+		}
+		policy := process.NotificationPolicy{Mode: process.NotifyBatchedHeaders, BatchSize: 10, BatchInterval: time.Hour}
+		_ = sn.RegisterHandlerWithPolicy(subscriber, policy)
+
+		err := sn.Notify(createOutportBlockWithEvents(args.Marshaller, 1, true))
+		require.Nil(t, err)
+		require.Empty(t, batches)
+
+		err = sn.Close()
+		require.Nil(t, err)
+		require.Len(t, batches, 1)
+
+		err = sn.Close()
+		require.Nil(t, err)
+		require.Len(t, batches, 1)
+	})
+}
+
+func TestSovereignNotifier_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	addr1 := []byte("addr1")
+	headerV2 := &block.HeaderV2{
+		Header:            &block.Header{},
+		ScheduledRootHash: []byte("root hash"),
+	}
+	incomingHeader := &sovereign.IncomingHeader{
+		Header: headerV2,
+		IncomingEvents: []*transaction.Event{
+			{
+				Address:    addr1,
+				Identifier: identifier,
+			},
+		},
+	}
+
+	args := createArgs()
+	args.SubscribedEvents = []SubscribedEvent{
+		{
+			Identifier: identifier,
+			Addresses: map[string]string{
+				string(addr1): string(addr1),
+			},
+		},
+	}
+
+	extendedShardHeaderHash, err := core.CalculateHash(args.Marshaller, args.Hasher, incomingHeader)
+	require.Nil(t, err)
+
+	sn, _ := NewSovereignNotifier(args)
+
+	headerBytes, err := args.Marshaller.Marshal(headerV2)
+	require.Nil(t, err)
+
+	outportBlock := &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderHash:  []byte("hash"),
+			HeaderBytes: headerBytes,
+			HeaderType:  string(core.ShardHeaderV2),
+		},
+		TransactionPool: &outport.TransactionPool{
+			Logs: []*outport.LogData{
+				{
+					TxHash: "txHash",
+					Log: &transaction.Log{
+						Events: []*transaction.Event{
+							{
+								Address:    addr1,
+								Identifier: identifier,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	n := 100
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		switch i % 2 {
+		case 0:
+			go func() {
+				errNotify := sn.Notify(outportBlock)
+				require.Nil(t, errNotify)
+			}()
+		case 1:
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				handler := &testscommon.HeaderSubscriberStub{
+					AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+						require.Equal(t, extendedShardHeaderHash, headerHash)
+						require.Equal(t, incomingHeader, header)
+
+						return nil
+					},
+				}
+
+				errRegister := sn.RegisterHandler(handler)
+				require.Nil(t, errRegister)
+			}()
+		default:
+			require.Fail(t, "should not have entered here")
+		}
+	}
+
+	wg.Wait()
+
+	sn.headersNotifier.mutSubscribers.RLock()
+	defer sn.headersNotifier.mutSubscribers.RUnlock()
+	require.Equal(t, n/2, len(sn.headersNotifier.subscribers))
+}
+
+func createSubscribedEvents(numSubscriptions int) []SubscribedEvent {
+	subscribedEvents := make([]SubscribedEvent, 0, numSubscriptions)
+	for i := 0; i < numSubscriptions; i++ {
+		subscribedEvents = append(subscribedEvents, SubscribedEvent{
+			Identifier: []byte(fmt.Sprintf("identifier%d", i)),
+			Addresses: map[string]string{
+				fmt.Sprintf("decodedAddr%d", i): fmt.Sprintf("encodedAddr%d", i),
+			},
+		})
+	}
+
+	return subscribedEvents
+}
+
+// createLogs creates logs in which each fourth event is subscribed, spread over all the subscriptions
+func createLogs(numLogs int, numEventsPerLog int, numSubscriptions int) []*outport.LogData {
+	logsData := make([]*outport.LogData, 0, numLogs)
+	for i := 0; i < numLogs; i++ {
+		events := make([]*transaction.Event, 0, numEventsPerLog)
+		for j := 0; j < numEventsPerLog; j++ {
+			subIdx := (i*numEventsPerLog + j) % numSubscriptions
+			address := []byte(fmt.Sprintf("decodedAddr%d", subIdx))
+			if j%4 != 0 {
+				address = []byte("notSubscribedAddr")
+			}
+
+			events = append(events, &transaction.Event{
+				Address:    address,
+				Identifier: []byte(fmt.Sprintf("identifier%d", subIdx)),
+				Data:       []byte(fmt.Sprintf("data%d-%d", i, j)),
+			})
+		}
+
+		logsData = append(logsData, &outport.LogData{
+			TxHash: fmt.Sprintf("txHash%d", i),
+			Log:    &transaction.Log{Events: events},
+		})
+	}
+
+	return logsData
+}
+
+func TestSovereignNotifier_ParallelEventsExtraction(t *testing.T) {
+	t.Parallel()
+
+	numSubscriptions := 10
+	logsData := createLogs(minLogsForParallelExtraction*3+7, 8, numSubscriptions)
+
+	args := createArgs()
+	args.SubscribedEvents = createSubscribedEvents(numSubscriptions)
+	sequentialNotifier, _ := NewSovereignNotifier(args)
+	expectedEvents := sequentialNotifier.createIncomingEvents(logsData, sequentialNotifier.eventsMatcher)
+	require.Len(t, expectedEvents, len(logsData)*2)
+
+	for _, numWorkers := range []uint32{2, 3, 8, 1000} {
+		args.NumExtractionWorkers = numWorkers
+		parallelNotifier, _ := NewSovereignNotifier(args)
+		require.Equal(t, expectedEvents, parallelNotifier.createIncomingEvents(logsData, parallelNotifier.eventsMatcher), "workers: %d", numWorkers)
+	}
+}
+
+func TestEventsMatcher_IsSubscribed(t *testing.T) {
+	t.Parallel()
+
+	matcher := newEventsMatcher([]SubscribedEvent{
+		{Identifier: []byte("deposit"), Addresses: map[string]string{"addr1": "encoded1"}},
+		{Identifier: []byte("deposit"), Addresses: map[string]string{"addr2": "encoded2"}},
+		{Identifier: []byte("send"), Addresses: map[string]string{"addr3": "encoded3"}},
+	})
+
+	require.True(t, matcher.isSubscribed(&transaction.Event{Identifier: []byte("deposit"), Address: []byte("addr1")}, ""))
+	require.True(t, matcher.isSubscribed(&transaction.Event{Identifier: []byte("deposit"), Address: []byte("addr2")}, ""))
+	require.True(t, matcher.isSubscribed(&transaction.Event{Identifier: []byte("send"), Address: []byte("addr3")}, ""))
+	require.False(t, matcher.isSubscribed(&transaction.Event{Identifier: []byte("send"), Address: []byte("addr1")}, ""))
+	require.False(t, matcher.isSubscribed(&transaction.Event{Identifier: []byte("other"), Address: []byte("addr1")}, ""))
+	require.False(t, matcher.isSubscribed(nil, ""))
+}
+
+// linearIsSubscribed is the matching by scanning all the subscriptions, used as baseline in benchmarks
+func linearIsSubscribed(subscribedEvents []SubscribedEvent, event *transaction.Event) bool {
+	for _, subEvent := range subscribedEvents {
+		if !bytes.Equal(event.GetIdentifier(), subEvent.Identifier) {
+			continue
+		}
+
+		_, found := subEvent.Addresses[string(event.GetAddress())]
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+func BenchmarkEventsMatching(b *testing.B) {
+	for _, numSubscriptions := range []int{1, 10, 100} {
+		subscribedEvents := createSubscribedEvents(numSubscriptions)
+		logsData := createLogs(1000, 10, numSubscriptions)
+		matcher := newEventsMatcher(subscribedEvents)
+
+		b.Run(fmt.Sprintf("linear/subscriptions=%d", numSubscriptions), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, logData := range logsData {
+					for _, event := range logData.Log.Events {
+						_ = linearIsSubscribed(subscribedEvents, event)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("indexed/subscriptions=%d", numSubscriptions), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, logData := range logsData {
+					for _, event := range logData.Log.Events {
+						_ = matcher.isSubscribed(event, logData.TxHash)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSovereignNotifier_CreateIncomingEvents(b *testing.B) {
+	numSubscriptions := 100
+	logsData := createLogs(10000, 10, numSubscriptions)
+
+	for _, numWorkers := range []uint32{1, 2, 4, 8} {
+		args := createArgs()
+		args.SubscribedEvents = createSubscribedEvents(numSubscriptions)
+		args.NumExtractionWorkers = numWorkers
+		sn, _ := NewSovereignNotifier(args)
+
+		b.Run(fmt.Sprintf("workers=%d", numWorkers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = sn.createIncomingEvents(logsData, sn.eventsMatcher)
+			}
+		})
+	}
+}
+
+func TestSovereignNotifier_CanonicalEventsOrderAcrossObservers(t *testing.T) {
+	t.Parallel()
+
+	createEvent := func(data string) *transaction.Event {
+		return &transaction.Event{Address: []byte("encodedAddr"), Identifier: identifier, Data: []byte(data)}
+	}
+	createLog := func(txHash []byte, events ...*transaction.Event) *outport.LogData {
+		return &outport.LogData{TxHash: hex.EncodeToString(txHash), Log: &transaction.Log{Events: events}}
+	}
+
+	tx1, tx2, tx3, tx4, tx5 := []byte("tx1"), []byte("tx2"), []byte("tx3"), []byte("tx4"), []byte("tx5")
+	log1 := createLog(tx1, createEvent("tx1 event0"), createEvent("tx1 event1"))
+	log2 := createLog(tx2, createEvent("tx2 event0"))
+	log3 := createLog(tx3, createEvent("tx3 event0"))
+	log4 := createLog(tx4, createEvent("tx4 event0"))
+	log5 := createLog(tx5, createEvent("tx5 event0"))
+	log2Second := createLog(tx2, createEvent("tx2 second log event0"))
+
+	args := createArgs()
+	createObserverBlock := func(logsData []*outport.LogData) *outport.OutportBlock {
+		outportBlock := createOutportBlockWithEvents(args.Marshaller, 4, false)
+		outportBlock.TransactionPool.Transactions = map[string]*outport.TxInfo{
+			hex.EncodeToString(tx3): {ExecutionOrder: 0},
+			hex.EncodeToString(tx2): {ExecutionOrder: 2},
+		}
+		outportBlock.TransactionPool.SmartContractResults = map[string]*outport.SCRInfo{
+			hex.EncodeToString(tx1): {ExecutionOrder: 1},
+		}
+		outportBlock.TransactionPool.Rewards = map[string]*outport.RewardInfo{
+			hex.EncodeToString(tx4): {ExecutionOrder: 3},
+		}
+		outportBlock.TransactionPool.Logs = logsData
+
+		return outportBlock
+	}
+
+	observerLogs := [][]*outport.LogData{
+		{log1, log2, log2Second, log3, log4, log5},
+		{log5, log4, log3, log2, log2Second, log1},
+		{log2, log5, log1, log4, log2Second, log3},
+	}
+
+	// tx5 is not found in the pool, so its log is placed last
+	expectedEvents := []*transaction.Event{
+		log3.Log.Events[0],
+		log1.Log.Events[0],
+		log1.Log.Events[1],
+		log2.Log.Events[0],
+		log2Second.Log.Events[0],
+		log4.Log.Events[0],
+		log5.Log.Events[0],
+	}
+
+	var expectedHash []byte
+	for idx, logsData := range observerLogs {
+		sn, _ := NewSovereignNotifier(args)
+		var notifiedHeader sovereign.IncomingHeaderHandler
+		var notifiedHash []byte
+		_ = sn.RegisterHandler(&testscommon.HeaderSubscriberStub{
+			AddHeaderCalled: func(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+				notifiedHeader = header
+				notifiedHash = headerHash
+				return nil
+			},
+		})
+
+		receivedLogs := make([]*outport.LogData, len(logsData))
+		copy(receivedLogs, logsData)
+		outportBlock := createObserverBlock(receivedLogs)
+
+		computedHash, err := sn.ComputeIncomingHeaderHash(outportBlock)
+		require.Nil(t, err)
+		err = sn.Notify(outportBlock)
+		require.Nil(t, err)
+
+		require.Equal(t, expectedEvents, notifiedHeader.(*sovereign.IncomingHeader).IncomingEvents, "observer %d", idx)
+		require.Equal(t, computedHash, notifiedHash)
+		require.Equal(t, logsData, outportBlock.TransactionPool.Logs, "received logs should not be reordered")
+		if idx == 0 {
+			expectedHash = notifiedHash
+			continue
+		}
+		require.Equal(t, expectedHash, notifiedHash, "observer %d", idx)
+	}
 }
@@ -0,0 +1,279 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// subscription holds a subscriber together with its notification policy and, in batched mode, its pending batch. The
+// delivered steps of each header are tracked, so that a retried notification only delivers what the subscriber missed
+type subscription struct {
+	handler    process.IncomingHeaderSubscriber
+	policy     process.NotificationPolicy
+	deliveries *deliveryTracker
+
+	mutBatch        sync.Mutex
+	batchHashes     [][]byte
+	batchHeaders    []sovereign.IncomingHeaderHandler
+	batchMiniBlocks [][]*block.MiniBlock
+	batchAccounts   map[string]*alteredAccount.AlteredAccount
+	batchTimer      *time.Timer
+	isClosed        bool
+}
+
+func newSubscription(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) (*subscription, error) {
+	err := checkNotificationPolicy(handler, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription{
+		handler:    handler,
+		policy:     policy,
+		deliveries: newDeliveryTracker(),
+	}, nil
+}
+
+func checkNotificationPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	switch policy.Mode {
+	case process.NotifyAllHeaders, process.NotifyHeadersWithEvents:
+		return nil
+	case process.NotifyBatchedHeaders:
+		if policy.BatchSize == 0 && policy.BatchInterval == 0 {
+			return errInvalidBatchPolicy
+		}
+
+		_, ok := handler.(process.HeadersBatchSubscriber)
+		if !ok {
+			return errNotHeadersBatchSubscriber
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errInvalidNotificationMode, policy.Mode)
+	}
+}
+
+func (sub *subscription) notify(
+	header sovereign.IncomingHeaderHandler,
+	headerHash []byte,
+	miniBlocks []*block.MiniBlock,
+	accounts map[string]*alteredAccount.AlteredAccount,
+) error {
+	if !sub.isShardSubscribed(header) {
+		log.Trace("skipped notifying header of a not subscribed shard", "hash", hex.EncodeToString(headerHash))
+		return nil
+	}
+
+	switch sub.policy.Mode {
+	case process.NotifyHeadersWithEvents:
+		if len(header.GetIncomingEventHandlers()) == 0 && len(miniBlocks) == 0 {
+			log.Trace("skipped notifying header without incoming events", "hash", hex.EncodeToString(headerHash))
+			return nil
+		}
+
+		return sub.notifyHeader(header, headerHash, miniBlocks, accounts)
+	case process.NotifyBatchedHeaders:
+		return sub.addToBatch(header, headerHash, miniBlocks, accounts)
+	default:
+		return sub.notifyHeader(header, headerHash, miniBlocks, accounts)
+	}
+}
+
+func (sub *subscription) isShardSubscribed(header sovereign.IncomingHeaderHandler) bool {
+	if len(sub.policy.ShardIDs) == 0 {
+		return true
+	}
+
+	headerHandler := header.GetHeaderHandler()
+	if check.IfNil(headerHandler) {
+		return false
+	}
+
+	return containsShard(sub.policy.ShardIDs, headerHandler.GetShardID())
+}
+
+func (sub *subscription) notifyHeader(
+	header sovereign.IncomingHeaderHandler,
+	headerHash []byte,
+	miniBlocks []*block.MiniBlock,
+	accounts map[string]*alteredAccount.AlteredAccount,
+) error {
+	step := sub.deliveries.getStep(headerHash)
+	if step < headerDelivered {
+		err := sub.handler.AddHeader(headerHash, header)
+		if err != nil {
+			return err
+		}
+		sub.deliveries.saveStep(headerHash, headerDelivered)
+	}
+
+	if step < miniBlocksDelivered {
+		err := sub.notifyMiniBlocks(headerHash, miniBlocks)
+		if err != nil {
+			return err
+		}
+		sub.deliveries.saveStep(headerHash, miniBlocksDelivered)
+	}
+
+	if step < accountsDelivered {
+		err := sub.notifyAccounts(headerHash, accounts)
+		if err != nil {
+			return err
+		}
+		sub.deliveries.saveStep(headerHash, accountsDelivered)
+	}
+
+	return nil
+}
+
+func (sub *subscription) notifyMiniBlocks(headerHash []byte, miniBlocks []*block.MiniBlock) error {
+	miniBlocksHandler, ok := sub.handler.(process.MiniBlocksSubscriber)
+	if !ok {
+		return nil
+	}
+
+	return miniBlocksHandler.AddIncomingMiniBlocks(headerHash, miniBlocks)
+}
+
+func (sub *subscription) notifyAccounts(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+	accountsHandler, ok := sub.handler.(process.AccountsSubscriber)
+	if !ok {
+		return nil
+	}
+
+	return accountsHandler.AddAccounts(headerHash, accounts)
+}
+
+func (sub *subscription) addToBatch(
+	header sovereign.IncomingHeaderHandler,
+	headerHash []byte,
+	miniBlocks []*block.MiniBlock,
+	accounts map[string]*alteredAccount.AlteredAccount,
+) error {
+	sub.mutBatch.Lock()
+	defer sub.mutBatch.Unlock()
+
+	// a retried notification should not add the header again, if it is still pending or was already delivered
+	isKnownHeader := sub.deliveries.getStep(headerHash) > noStepDelivered || sub.isInBatch(headerHash)
+	if !isKnownHeader {
+		sub.batchHashes = append(sub.batchHashes, headerHash)
+		sub.batchHeaders = append(sub.batchHeaders, header)
+		sub.batchMiniBlocks = append(sub.batchMiniBlocks, miniBlocks)
+		sub.batchAccounts = accounts
+	}
+
+	if sub.policy.BatchSize != 0 && len(sub.batchHeaders) >= int(sub.policy.BatchSize) {
+		return sub.flushBatch()
+	}
+
+	sub.startBatchTimerIfNeeded()
+
+	return nil
+}
+
+// isInBatch should be called under mutBatch
+func (sub *subscription) isInBatch(headerHash []byte) bool {
+	for _, batchHash := range sub.batchHashes {
+		if bytes.Equal(batchHash, headerHash) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startBatchTimerIfNeeded should be called under mutBatch
+func (sub *subscription) startBatchTimerIfNeeded() {
+	if sub.policy.BatchInterval == 0 || sub.batchTimer != nil || sub.isClosed {
+		return
+	}
+
+	sub.batchTimer = time.AfterFunc(sub.policy.BatchInterval, sub.flushBatchOnTimeout)
+}
+
+// flushBatchOnTimeout retries the notification on the next timeout, if it fails
+func (sub *subscription) flushBatchOnTimeout() {
+	sub.mutBatch.Lock()
+	defer sub.mutBatch.Unlock()
+
+	err := sub.flushBatch()
+	if err != nil {
+		log.Error("could not notify headers batch on timeout, will retry", "error", err)
+		sub.startBatchTimerIfNeeded()
+	}
+}
+
+// flushBatch should be called under mutBatch, so that batches are notified in order. The batch is kept until the
+// subscriber accepts its headers, miniblocks and accounts, to be notified again by the next flush. The already
+// delivered steps are not notified again
+func (sub *subscription) flushBatch() error {
+	if sub.batchTimer != nil {
+		sub.batchTimer.Stop()
+		sub.batchTimer = nil
+	}
+
+	numHeaders := len(sub.batchHeaders)
+	if numHeaders == 0 {
+		return nil
+	}
+
+	log.Debug("notifying incoming headers batch", "num headers", numHeaders)
+
+	hashes, headers, miniBlocks, accounts := sub.batchHashes, sub.batchHeaders, sub.batchMiniBlocks, sub.batchAccounts
+	lastHash := hashes[numHeaders-1]
+	if sub.deliveries.getStep(lastHash) < headerDelivered {
+		err := sub.handler.(process.HeadersBatchSubscriber).AddHeaders(hashes, headers)
+		if err != nil {
+			return err
+		}
+		for _, headerHash := range hashes {
+			sub.deliveries.saveStep(headerHash, headerDelivered)
+		}
+	}
+
+	for idx, headerHash := range hashes {
+		if sub.deliveries.getStep(headerHash) >= miniBlocksDelivered {
+			continue
+		}
+
+		err := sub.notifyMiniBlocks(headerHash, miniBlocks[idx])
+		if err != nil {
+			return err
+		}
+		sub.deliveries.saveStep(headerHash, miniBlocksDelivered)
+	}
+
+	if sub.deliveries.getStep(lastHash) < accountsDelivered {
+		err := sub.notifyAccounts(lastHash, accounts)
+		if err != nil {
+			return err
+		}
+		for _, headerHash := range hashes {
+			sub.deliveries.saveStep(headerHash, accountsDelivered)
+		}
+	}
+
+	sub.batchHashes, sub.batchHeaders, sub.batchMiniBlocks, sub.batchAccounts = nil, nil, nil, nil
+
+	return nil
+}
+
+// close will notify the pending batch, if any, and stop batching on timeout
+func (sub *subscription) close() error {
+	sub.mutBatch.Lock()
+	defer sub.mutBatch.Unlock()
+
+	sub.isClosed = true
+
+	return sub.flushBatch()
+}
@@ -0,0 +1,15 @@
+package observers
+
+import "errors"
+
+var errEmptySource = errors.New("empty source provided")
+
+var errNilPayloadHandler = errors.New("nil payload handler provided")
+
+var errNilSourcesHealthTracker = errors.New("nil sources health tracker provided")
+
+var errNoClients = errors.New("no clients provided")
+
+var errNilClient = errors.New("nil client provided")
+
+var errInvalidCheckInterval = errors.New("invalid check interval provided")
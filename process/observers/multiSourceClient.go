@@ -0,0 +1,57 @@
+package observers
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+type multiSourceClient struct {
+	clients         []process.WSClient
+	payloadHandlers []process.PayloadHandler
+}
+
+// NewMultiSourceClient creates a client which groups the websocket clients of all upstream observers together with
+// the payload handlers they feed
+func NewMultiSourceClient(clients []process.WSClient, payloadHandlers []process.PayloadHandler) (*multiSourceClient, error) {
+	if len(clients) == 0 {
+		return nil, errNoClients
+	}
+	for _, client := range clients {
+		if client == nil {
+			return nil, errNilClient
+		}
+	}
+	for _, payloadHandler := range payloadHandlers {
+		if check.IfNil(payloadHandler) {
+			return nil, errNilPayloadHandler
+		}
+	}
+
+	return &multiSourceClient{
+		clients:         clients,
+		payloadHandlers: payloadHandlers,
+	}, nil
+}
+
+// Close will close all clients and then their payload handlers. It returns the last encountered error
+func (msc *multiSourceClient) Close() error {
+	var lastErr error
+	for _, client := range msc.clients {
+		err := client.Close()
+		if err != nil {
+			log.Warn("could not close client", "error", err)
+			lastErr = err
+		}
+	}
+
+	for _, payloadHandler := range msc.payloadHandlers {
+		err := payloadHandler.Close()
+		if err != nil {
+			log.Warn("could not close payload handler", "error", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
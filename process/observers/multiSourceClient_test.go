@@ -0,0 +1,78 @@
+package observers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiSourceClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		client, err := NewMultiSourceClient([]process.WSClient{&testscommon.WSClientStub{}}, []process.PayloadHandler{&testscommon.PayloadHandlerStub{}})
+		require.Nil(t, err)
+		require.NotNil(t, client)
+	})
+
+	t.Run("no clients, should return error", func(t *testing.T) {
+		client, err := NewMultiSourceClient(nil, []process.PayloadHandler{&testscommon.PayloadHandlerStub{}})
+		require.Equal(t, errNoClients, err)
+		require.Nil(t, client)
+	})
+
+	t.Run("nil client, should return error", func(t *testing.T) {
+		client, err := NewMultiSourceClient([]process.WSClient{nil}, []process.PayloadHandler{&testscommon.PayloadHandlerStub{}})
+		require.Equal(t, errNilClient, err)
+		require.Nil(t, client)
+	})
+
+	t.Run("nil payload handler, should return error", func(t *testing.T) {
+		client, err := NewMultiSourceClient([]process.WSClient{&testscommon.WSClientStub{}}, []process.PayloadHandler{nil})
+		require.Equal(t, errNilPayloadHandler, err)
+		require.Nil(t, client)
+	})
+}
+
+func TestMultiSourceClient_Close(t *testing.T) {
+	t.Parallel()
+
+	closed := make([]string, 0)
+	errClose := errors.New("cannot close")
+	clients := []process.WSClient{
+		&testscommon.WSClientStub{
+			CloseCalled: func() error {
+				closed = append(closed, "client1")
+				return errClose
+			},
+		},
+		&testscommon.WSClientStub{
+			CloseCalled: func() error {
+				closed = append(closed, "client2")
+				return nil
+			},
+		},
+	}
+	payloadHandlers := []process.PayloadHandler{
+		&testscommon.PayloadHandlerStub{
+			CloseCalled: func() error {
+				closed = append(closed, "payload handler1")
+				return nil
+			},
+		},
+		&testscommon.PayloadHandlerStub{
+			CloseCalled: func() error {
+				closed = append(closed, "payload handler2")
+				return nil
+			},
+		},
+	}
+	client, _ := NewMultiSourceClient(clients, payloadHandlers)
+
+	err := client.Close()
+	require.Equal(t, errClose, err)
+	require.Equal(t, []string{"client1", "client2", "payload handler1", "payload handler2"}, closed)
+}
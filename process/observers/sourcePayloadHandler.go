@@ -0,0 +1,65 @@
+package observers
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/check"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// ArgsSourcePayloadHandler is a struct placeholder for args needed to create a source payload handler
+type ArgsSourcePayloadHandler struct {
+	Source               string
+	PayloadHandler       process.PayloadHandler
+	SourcesHealthTracker process.SourcesHealthTracker
+}
+
+type sourcePayloadHandler struct {
+	source               string
+	payloadHandler       process.PayloadHandler
+	sourcesHealthTracker process.SourcesHealthTracker
+}
+
+// NewSourcePayloadHandler creates a payload handler for a single upstream observer, which forwards payloads to a
+// payload handler shared by all observers and records the health of its source
+func NewSourcePayloadHandler(args ArgsSourcePayloadHandler) (*sourcePayloadHandler, error) {
+	if len(args.Source) == 0 {
+		return nil, errEmptySource
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, errNilPayloadHandler
+	}
+	if check.IfNil(args.SourcesHealthTracker) {
+		return nil, errNilSourcesHealthTracker
+	}
+
+	args.SourcesHealthTracker.AddSource(args.Source)
+
+	return &sourcePayloadHandler{
+		source:               args.Source,
+		payloadHandler:       args.PayloadHandler,
+		sourcesHealthTracker: args.SourcesHealthTracker,
+	}, nil
+}
+
+// ProcessPayload will process the payload with the shared payload handler and record the result
+func (sph *sourcePayloadHandler) ProcessPayload(payload []byte, topic string, version uint32) error {
+	err := sph.payloadHandler.ProcessPayload(payload, topic, version)
+	if err != nil {
+		log.Debug("could not process payload", "source", sph.source, "topic", topic, "error", err)
+		sph.sourcesHealthTracker.RecordError(sph.source, err)
+		return err
+	}
+
+	sph.sourcesHealthTracker.RecordPayload(sph.source)
+	return nil
+}
+
+// Close does nothing, since the shared payload handler is closed once all sources are closed
+func (sph *sourcePayloadHandler) Close() error {
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sph *sourcePayloadHandler) IsInterfaceNil() bool {
+	return sph == nil
+}
@@ -0,0 +1,94 @@
+package observers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createSourcePayloadHandlerArgs() ArgsSourcePayloadHandler {
+	return ArgsSourcePayloadHandler{
+		Source:               "ws://localhost:22111",
+		PayloadHandler:       &testscommon.PayloadHandlerStub{},
+		SourcesHealthTracker: NewSourcesHealthTracker(0),
+	}
+}
+
+func TestNewSourcePayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		args := createSourcePayloadHandlerArgs()
+		handler, err := NewSourcePayloadHandler(args)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(handler))
+
+		_, found := args.SourcesHealthTracker.(*sourcesHealthTracker).GetSourcesHealth()[args.Source]
+		require.True(t, found)
+	})
+
+	t.Run("empty source, should return error", func(t *testing.T) {
+		args := createSourcePayloadHandlerArgs()
+		args.Source = ""
+		handler, err := NewSourcePayloadHandler(args)
+		require.Equal(t, errEmptySource, err)
+		require.Nil(t, handler)
+	})
+
+	t.Run("nil payload handler, should return error", func(t *testing.T) {
+		args := createSourcePayloadHandlerArgs()
+		args.PayloadHandler = nil
+		handler, err := NewSourcePayloadHandler(args)
+		require.Equal(t, errNilPayloadHandler, err)
+		require.Nil(t, handler)
+	})
+
+	t.Run("nil sources health tracker, should return error", func(t *testing.T) {
+		args := createSourcePayloadHandlerArgs()
+		args.SourcesHealthTracker = nil
+		handler, err := NewSourcePayloadHandler(args)
+		require.Equal(t, errNilSourcesHealthTracker, err)
+		require.Nil(t, handler)
+	})
+}
+
+func TestSourcePayloadHandler_ProcessPayload(t *testing.T) {
+	t.Parallel()
+
+	errProcess := errors.New("cannot process")
+	args := createSourcePayloadHandlerArgs()
+	args.PayloadHandler = &testscommon.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			if topic == "invalid" {
+				return errProcess
+			}
+
+			require.Equal(t, []byte("payload"), payload)
+			require.Equal(t, uint32(1), version)
+			return nil
+		},
+		CloseCalled: func() error {
+			require.Fail(t, "shared payload handler should not be closed")
+			return nil
+		},
+	}
+	tracker := NewSourcesHealthTracker(0)
+	args.SourcesHealthTracker = tracker
+	handler, _ := NewSourcePayloadHandler(args)
+
+	err := handler.ProcessPayload([]byte("payload"), "topic", 1)
+	require.Nil(t, err)
+
+	err = handler.ProcessPayload([]byte("payload"), "invalid", 1)
+	require.Equal(t, errProcess, err)
+
+	sourceHealth := tracker.GetSourcesHealth()[args.Source]
+	require.Equal(t, uint64(2), sourceHealth.NumPayloads)
+	require.Equal(t, uint64(1), sourceHealth.NumErrors)
+	require.Equal(t, errProcess.Error(), sourceHealth.LastError)
+
+	require.Nil(t, handler.Close())
+}
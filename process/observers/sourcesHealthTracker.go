@@ -0,0 +1,188 @@
+package observers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("notifier-observers-process")
+
+// SourceHealth holds the health of an upstream observer. LastPayloadAt is the time of the last successfully processed
+// payload, while NumPayloads counts all received payloads, including the ones which could not be processed
+type SourceHealth struct {
+	LastPayloadAt time.Time
+	LastErrorAt   time.Time
+	NumPayloads   uint64
+	NumErrors     uint64
+	LastError     string
+	IsHealthy     bool
+}
+
+type sourcesHealthTracker struct {
+	unhealthyTimeout time.Duration
+	startTime        time.Time
+
+	mutSources sync.RWMutex
+	sources    map[string]*SourceHealth
+
+	// reportedHealth is accessed only by the monitoring go routine
+	reportedHealth map[string]bool
+	cancel         context.CancelFunc
+}
+
+// NewSourcesHealthTracker creates a tracker which keeps the health of each upstream observer. A source is considered
+// healthy if it sent a payload which was successfully processed in the last unhealthyTimeout duration. A zero timeout
+// disables this check
+func NewSourcesHealthTracker(unhealthyTimeout time.Duration) *sourcesHealthTracker {
+	return &sourcesHealthTracker{
+		unhealthyTimeout: unhealthyTimeout,
+		startTime:        time.Now(),
+		sources:          make(map[string]*SourceHealth),
+		reportedHealth:   make(map[string]bool),
+	}
+}
+
+// NewMonitoredSourcesHealthTracker creates a sources health tracker which also checks the health of the sources every
+// check interval, logging the sources which become unhealthy or recover. The checks are stopped on Close
+func NewMonitoredSourcesHealthTracker(unhealthyTimeout time.Duration, checkInterval time.Duration) (*sourcesHealthTracker, error) {
+	if checkInterval <= 0 {
+		return nil, errInvalidCheckInterval
+	}
+
+	sht := NewSourcesHealthTracker(unhealthyTimeout)
+	ctx, cancel := context.WithCancel(context.Background())
+	sht.cancel = cancel
+
+	go sht.monitor(ctx, checkInterval)
+
+	return sht, nil
+}
+
+func (sht *sourcesHealthTracker) monitor(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("sources health tracker is closing")
+			return
+		case now := <-ticker.C:
+			sht.reportSourcesHealth(now)
+		}
+	}
+}
+
+// reportSourcesHealth logs the sources whose health changed since the previous report. Sources which did not send any
+// payload yet are reported as unhealthy only after the unhealthy timeout elapsed since the tracker was created
+func (sht *sourcesHealthTracker) reportSourcesHealth(now time.Time) {
+	for source, health := range sht.GetSourcesHealth() {
+		isStarting := health.LastPayloadAt.IsZero() && now.Sub(sht.startTime) < sht.unhealthyTimeout
+		isHealthy := health.IsHealthy || isStarting
+
+		wasHealthy, isReported := sht.reportedHealth[source]
+		if !isReported {
+			wasHealthy = true
+		}
+		sht.reportedHealth[source] = isHealthy
+		if isHealthy == wasHealthy {
+			continue
+		}
+
+		if isHealthy {
+			log.Info("observer recovered", "source", source, "num payloads", health.NumPayloads)
+			continue
+		}
+
+		log.Warn("observer is unhealthy",
+			"source", source,
+			"last payload at", health.LastPayloadAt,
+			"num payloads", health.NumPayloads,
+			"num errors", health.NumErrors,
+			"last error at", health.LastErrorAt,
+			"last error", health.LastError)
+	}
+}
+
+// AddSource will start tracking the provided source, if not already tracked
+func (sht *sourcesHealthTracker) AddSource(source string) {
+	sht.mutSources.Lock()
+	sht.getOrAddSource(source)
+	sht.mutSources.Unlock()
+}
+
+func (sht *sourcesHealthTracker) getOrAddSource(source string) *SourceHealth {
+	health, found := sht.sources[source]
+	if !found {
+		health = &SourceHealth{}
+		sht.sources[source] = health
+	}
+
+	return health
+}
+
+// RecordPayload will record a successfully processed payload received from the provided source
+func (sht *sourcesHealthTracker) RecordPayload(source string) {
+	sht.mutSources.Lock()
+	defer sht.mutSources.Unlock()
+
+	health := sht.getOrAddSource(source)
+	health.LastPayloadAt = time.Now()
+	health.NumPayloads++
+}
+
+// RecordError will record a payload received from the provided source which could not be processed
+func (sht *sourcesHealthTracker) RecordError(source string, err error) {
+	sht.mutSources.Lock()
+	defer sht.mutSources.Unlock()
+
+	health := sht.getOrAddSource(source)
+	health.LastErrorAt = time.Now()
+	health.NumPayloads++
+	health.NumErrors++
+	health.LastError = err.Error()
+}
+
+// GetSourcesHealth returns the health of each tracked source, which is also periodically reported by a monitored tracker
+func (sht *sourcesHealthTracker) GetSourcesHealth() map[string]SourceHealth {
+	sht.mutSources.RLock()
+	defer sht.mutSources.RUnlock()
+
+	now := time.Now()
+	sourcesHealth := make(map[string]SourceHealth, len(sht.sources))
+	for source, health := range sht.sources {
+		healthCopy := *health
+		healthCopy.IsHealthy = sht.isHealthy(health, now)
+		sourcesHealth[source] = healthCopy
+	}
+
+	return sourcesHealth
+}
+
+func (sht *sourcesHealthTracker) isHealthy(health *SourceHealth, now time.Time) bool {
+	if health.LastPayloadAt.IsZero() {
+		return false
+	}
+	if sht.unhealthyTimeout == 0 {
+		return true
+	}
+
+	return now.Sub(health.LastPayloadAt) < sht.unhealthyTimeout
+}
+
+// Close will stop the health checks of a monitored tracker
+func (sht *sourcesHealthTracker) Close() error {
+	if sht.cancel != nil {
+		sht.cancel()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sht *sourcesHealthTracker) IsInterfaceNil() bool {
+	return sht == nil
+}
@@ -0,0 +1,137 @@
+package observers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSourcesHealthTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSourcesHealthTracker(time.Minute)
+	require.False(t, check.IfNil(tracker))
+	require.Empty(t, tracker.GetSourcesHealth())
+}
+
+func TestSourcesHealthTracker_GetSourcesHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sources without payloads are unhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewSourcesHealthTracker(time.Minute)
+		tracker.AddSource("source1")
+		tracker.AddSource("source1")
+
+		sourcesHealth := tracker.GetSourcesHealth()
+		require.Equal(t, map[string]SourceHealth{"source1": {}}, sourcesHealth)
+	})
+
+	t.Run("payloads and errors are recorded", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewSourcesHealthTracker(time.Minute)
+		tracker.AddSource("source1")
+		tracker.RecordPayload("source1")
+		tracker.RecordPayload("source2")
+		tracker.RecordError("source2", errors.New("local error"))
+
+		sourcesHealth := tracker.GetSourcesHealth()
+		require.Len(t, sourcesHealth, 2)
+
+		require.True(t, sourcesHealth["source1"].IsHealthy)
+		require.Equal(t, uint64(1), sourcesHealth["source1"].NumPayloads)
+		require.Zero(t, sourcesHealth["source1"].NumErrors)
+
+		require.True(t, sourcesHealth["source2"].IsHealthy)
+		require.Equal(t, uint64(2), sourcesHealth["source2"].NumPayloads)
+		require.Equal(t, uint64(1), sourcesHealth["source2"].NumErrors)
+		require.Equal(t, "local error", sourcesHealth["source2"].LastError)
+	})
+
+	t.Run("sources with only failed payloads are unhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewSourcesHealthTracker(time.Minute)
+		tracker.RecordError("source1", errors.New("local error"))
+
+		sourceHealth := tracker.GetSourcesHealth()["source1"]
+		require.False(t, sourceHealth.IsHealthy)
+		require.True(t, sourceHealth.LastPayloadAt.IsZero())
+		require.False(t, sourceHealth.LastErrorAt.IsZero())
+
+		tracker.RecordPayload("source1")
+		lastPayloadAt := tracker.GetSourcesHealth()["source1"].LastPayloadAt
+		tracker.RecordError("source1", errors.New("local error"))
+		require.Equal(t, lastPayloadAt, tracker.GetSourcesHealth()["source1"].LastPayloadAt)
+	})
+
+	t.Run("sources without recent payloads are unhealthy", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := NewSourcesHealthTracker(time.Minute)
+		tracker.RecordPayload("source1")
+		tracker.sources["source1"].LastPayloadAt = time.Now().Add(-time.Hour)
+
+		require.False(t, tracker.GetSourcesHealth()["source1"].IsHealthy)
+
+		tracker.unhealthyTimeout = 0
+		require.True(t, tracker.GetSourcesHealth()["source1"].IsHealthy)
+	})
+}
+
+func TestNewMonitoredSourcesHealthTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid check interval should error", func(t *testing.T) {
+		t.Parallel()
+
+		tracker, err := NewMonitoredSourcesHealthTracker(time.Minute, 0)
+		require.Nil(t, tracker)
+		require.Equal(t, errInvalidCheckInterval, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		tracker, err := NewMonitoredSourcesHealthTracker(time.Minute, time.Millisecond)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(tracker))
+
+		tracker.RecordPayload("source1")
+		time.Sleep(10 * time.Millisecond)
+		require.Nil(t, tracker.Close())
+		require.Nil(t, tracker.Close())
+	})
+}
+
+func TestSourcesHealthTracker_ReportSourcesHealth(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewSourcesHealthTracker(time.Minute)
+	tracker.AddSource("source1")
+	tracker.RecordPayload("source2")
+
+	// sources without payloads are not reported while starting
+	now := time.Now()
+	tracker.reportSourcesHealth(now)
+	require.Equal(t, map[string]bool{"source1": true, "source2": true}, tracker.reportedHealth)
+
+	tracker.reportSourcesHealth(now.Add(time.Hour))
+	require.Equal(t, map[string]bool{"source1": false, "source2": true}, tracker.reportedHealth)
+
+	tracker.sources["source2"].LastPayloadAt = time.Now().Add(-time.Hour)
+	tracker.reportSourcesHealth(now.Add(time.Hour))
+	require.Equal(t, map[string]bool{"source1": false, "source2": false}, tracker.reportedHealth)
+
+	tracker.RecordPayload("source1")
+	tracker.RecordPayload("source2")
+	tracker.reportSourcesHealth(now.Add(time.Hour))
+	require.Equal(t, map[string]bool{"source1": true, "source2": true}, tracker.reportedHealth)
+
+	require.Nil(t, tracker.Close())
+}
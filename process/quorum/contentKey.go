@@ -0,0 +1,64 @@
+package quorum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+)
+
+// notifiedContent holds the parts of a finalized block from which the notified data is created
+type notifiedContent struct {
+	IncomingHeaderHash []byte
+	AlteredAccounts    map[string]*alteredAccount.AlteredAccount
+	MiniBlocks         []*block.MiniBlock
+	Transactions       map[string]*outport.TxInfo
+	Scrs               map[string]*outport.SCRInfo
+}
+
+// computeContentKey returns the key by which the observers votes for a block are compared. Besides the incoming header
+// hash, it covers the altered accounts and the miniblocks, together with the pool transactions and smart contract
+// results they contain, so only data agreed by the quorum of observers is notified
+func computeContentKey(incomingHeaderHash []byte, outportBlock *outport.OutportBlock) (string, error) {
+	blockData := outportBlock.GetBlockData()
+	miniBlocks := make([]*block.MiniBlock, 0)
+	miniBlocks = append(miniBlocks, blockData.GetBody().GetMiniBlocks()...)
+	miniBlocks = append(miniBlocks, blockData.GetIntraShardMiniBlocks()...)
+
+	content := &notifiedContent{
+		IncomingHeaderHash: incomingHeaderHash,
+		AlteredAccounts:    outportBlock.AlteredAccounts,
+		MiniBlocks:         miniBlocks,
+		Transactions:       make(map[string]*outport.TxInfo),
+		Scrs:               make(map[string]*outport.SCRInfo),
+	}
+
+	pool := outportBlock.GetTransactionPool()
+	for _, miniBlock := range miniBlocks {
+		for _, txHash := range miniBlock.GetTxHashes() {
+			encodedTxHash := hex.EncodeToString(txHash)
+			txInfo, found := pool.GetTransactions()[encodedTxHash]
+			if found {
+				content.Transactions[encodedTxHash] = txInfo
+				continue
+			}
+
+			scrInfo, found := pool.GetSmartContractResults()[encodedTxHash]
+			if found {
+				content.Scrs[encodedTxHash] = scrInfo
+			}
+		}
+	}
+
+	// the map keys are sorted when marshalled, so the key does not depend on the iteration order
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(contentBytes)
+	return string(hash[:]), nil
+}
@@ -0,0 +1,21 @@
+package quorum
+
+import "errors"
+
+var errNilSovereignNotifier = errors.New("nil sovereign notifier provided")
+
+var errNilAccountsTracker = errors.New("nil accounts tracker provided")
+
+var errNilValidatorsTracker = errors.New("nil validators tracker provided")
+
+var errNilLivenessTracker = errors.New("nil liveness tracker provided")
+
+var errInvalidQuorum = errors.New("invalid quorum provided")
+
+var errEmptySource = errors.New("empty source provided")
+
+var errNotIncomingHeaderHashComputer = errors.New("sovereign notifier does not compute incoming header hashes")
+
+var errNotPolicyHandlerRegisterer = errors.New("sovereign notifier does not support notification policies")
+
+var errNotHeartbeatNotifier = errors.New("sovereign notifier does not notify heartbeats")
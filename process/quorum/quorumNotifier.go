@@ -0,0 +1,273 @@
+package quorum
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	logger "github.com/multiversx/mx-chain-logger-go"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/accounts"
+)
+
+var log = logger.GetOrCreate("notifier-quorum-process")
+
+// numBlocksToKeep is the number of recent header hashes for which observers votes are kept
+const numBlocksToKeep = 1000
+
+// numDataVotesToKeep is the number of recent rounds, validators sets and ratings for which observers votes are kept
+const numDataVotesToKeep = 1000
+
+// ArgsQuorumNotifier is a struct placeholder for args needed to create a quorum notifier. The trackers are shared by
+// all observers and are updated only with the data agreed by the quorum
+type ArgsQuorumNotifier struct {
+	Notifier          process.SovereignNotifier
+	AccountsTracker   process.AccountsTracker
+	ValidatorsTracker process.ValidatorsTracker
+	LivenessTracker   process.LivenessTracker
+	Quorum            uint32
+}
+
+// SourceComponents holds the components to be used by the indexer of a single observer in quorum mode
+type SourceComponents struct {
+	Notifier          process.SovereignNotifier
+	AccountsTracker   process.AccountsTracker
+	ValidatorsTracker process.ValidatorsTracker
+	LivenessTracker   process.LivenessTracker
+}
+
+type contentVotes struct {
+	outportBlock *outport.OutportBlock
+	sources      map[string]struct{}
+}
+
+type blockVotes struct {
+	votesByContent  map[string]*contentVotes
+	notifiedContent string
+}
+
+type quorumNotifier struct {
+	notifier          process.SovereignNotifier
+	queuingNotifier   process.QueuingNotifier
+	hashComputer      process.IncomingHeaderHashComputer
+	accountsTracker   process.AccountsTracker
+	validatorsTracker process.ValidatorsTracker
+	livenessTracker   process.LivenessTracker
+	quorum            int
+
+	mutVotes       sync.Mutex
+	blocks         map[string]*blockVotes
+	blocksQueue    []string
+	dataVotes      map[string]map[string]struct{}
+	dataVotesQueue []string
+}
+
+// NewQuorumNotifier creates a notifier which forwards a finalized block to the provided notifier only once the
+// configured quorum of observers finalized blocks with identical contents. The contents are compared by incoming header
+// hash, altered accounts and miniblocks, so the provided notifier should also implement
+// process.IncomingHeaderHashComputer. The accounts of the notified block and the rounds, validators sets and ratings
+// reported by the quorum of observers are saved in the provided trackers. If the provided notifier also implements
+// process.QueuingNotifier, the notified block is tracked once delivered
+func NewQuorumNotifier(args ArgsQuorumNotifier) (*quorumNotifier, error) {
+	if check.IfNil(args.Notifier) {
+		return nil, errNilSovereignNotifier
+	}
+	hashComputer, ok := args.Notifier.(process.IncomingHeaderHashComputer)
+	if !ok {
+		return nil, errNotIncomingHeaderHashComputer
+	}
+	if check.IfNil(args.AccountsTracker) {
+		return nil, errNilAccountsTracker
+	}
+	if check.IfNil(args.ValidatorsTracker) {
+		return nil, errNilValidatorsTracker
+	}
+	if check.IfNil(args.LivenessTracker) {
+		return nil, errNilLivenessTracker
+	}
+	if args.Quorum == 0 {
+		return nil, errInvalidQuorum
+	}
+
+	queuingNotifier, _ := args.Notifier.(process.QueuingNotifier)
+
+	return &quorumNotifier{
+		notifier:          args.Notifier,
+		queuingNotifier:   queuingNotifier,
+		hashComputer:      hashComputer,
+		accountsTracker:   args.AccountsTracker,
+		validatorsTracker: args.ValidatorsTracker,
+		livenessTracker:   args.LivenessTracker,
+		quorum:            int(args.Quorum),
+		blocks:            make(map[string]*blockVotes),
+		dataVotes:         make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// SourceNotifier returns a notifier to be used by the indexer of the provided observer
+func (qn *quorumNotifier) SourceNotifier(source string) (process.SovereignNotifier, error) {
+	if len(source) == 0 {
+		return nil, errEmptySource
+	}
+
+	return &sourceNotifier{
+		source:         source,
+		quorumNotifier: qn,
+	}, nil
+}
+
+// SourceComponents returns the notifier and the trackers to be used by the indexer of the provided observer. The
+// observer keeps its accounts in its own indexer, while its rounds, validators sets and ratings are only votes
+func (qn *quorumNotifier) SourceComponents(source string) (*SourceComponents, error) {
+	notifier, err := qn.SourceNotifier(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceComponents{
+		Notifier:        notifier,
+		AccountsTracker: accounts.NewDisabledAccountsTracker(),
+		ValidatorsTracker: &sourceValidatorsTracker{
+			source:         source,
+			quorumNotifier: qn,
+		},
+		LivenessTracker: &sourceLivenessTracker{
+			source:         source,
+			quorumNotifier: qn,
+		},
+	}, nil
+}
+
+func (qn *quorumNotifier) notifyFromSource(source string, outportBlock *outport.OutportBlock) error {
+	incomingHeaderHash, err := qn.hashComputer.ComputeIncomingHeaderHash(outportBlock)
+	if err != nil {
+		return err
+	}
+
+	content, err := computeContentKey(incomingHeaderHash, outportBlock)
+	if err != nil {
+		return err
+	}
+
+	qn.mutVotes.Lock()
+	defer qn.mutVotes.Unlock()
+
+	headerHash := string(outportBlock.BlockData.HeaderHash)
+	votes := qn.getOrAddBlockVotes(headerHash)
+
+	contentVote, found := votes.votesByContent[content]
+	if !found {
+		contentVote = &contentVotes{
+			outportBlock: outportBlock,
+			sources:      make(map[string]struct{}),
+		}
+		votes.votesByContent[content] = contentVote
+	}
+	contentVote.sources[source] = struct{}{}
+
+	if len(votes.votesByContent) > 1 {
+		log.Warn("observers finalized different contents for the same header",
+			"header hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash),
+			"source", source,
+			"incoming header hash", hex.EncodeToString(incomingHeaderHash),
+			"num different contents", len(votes.votesByContent))
+	}
+
+	if len(votes.notifiedContent) != 0 {
+		return nil
+	}
+
+	numVotes := len(contentVote.sources)
+	if numVotes < qn.quorum {
+		log.Debug("waiting for quorum",
+			"header hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash),
+			"num votes", numVotes,
+			"quorum", qn.quorum)
+		return nil
+	}
+
+	// if the block can not be notified, it is notified again on the next vote for its content
+	notifiedBlock := contentVote.outportBlock
+	err = qn.notify(notifiedBlock, func() {
+		qn.livenessTracker.SaveFinalizedBlock(notifiedBlock.BlockData.HeaderHash)
+		qn.accountsTracker.UpdateAccounts(notifiedBlock.AlteredAccounts)
+	})
+	if err != nil {
+		return err
+	}
+
+	votes.notifiedContent = content
+
+	return nil
+}
+
+// notify calls the delivery handler once the block was delivered, which for a queuing notifier may happen after the
+// block was queued
+func (qn *quorumNotifier) notify(outportBlock *outport.OutportBlock, deliveryHandler func()) error {
+	if !check.IfNil(qn.queuingNotifier) {
+		return qn.queuingNotifier.NotifyWithDeliveryHandler(outportBlock, deliveryHandler)
+	}
+
+	err := qn.notifier.Notify(outportBlock)
+	if err != nil {
+		return err
+	}
+
+	deliveryHandler()
+
+	return nil
+}
+
+func (qn *quorumNotifier) getOrAddBlockVotes(headerHash string) *blockVotes {
+	votes, found := qn.blocks[headerHash]
+	if found {
+		return votes
+	}
+
+	votes = &blockVotes{
+		votesByContent: make(map[string]*contentVotes),
+	}
+	qn.blocks[headerHash] = votes
+	qn.blocksQueue = append(qn.blocksQueue, headerHash)
+
+	if len(qn.blocksQueue) > numBlocksToKeep {
+		delete(qn.blocks, qn.blocksQueue[0])
+		qn.blocksQueue = qn.blocksQueue[1:]
+	}
+
+	return votes
+}
+
+// voteData records the vote of the source for the data identified by the provided key and returns true only for the
+// vote which reaches the quorum, so that the agreed data is saved once
+func (qn *quorumNotifier) voteData(source string, key string) bool {
+	qn.mutVotes.Lock()
+	defer qn.mutVotes.Unlock()
+
+	sources, found := qn.dataVotes[key]
+	if !found {
+		sources = make(map[string]struct{})
+		qn.dataVotes[key] = sources
+		qn.dataVotesQueue = append(qn.dataVotesQueue, key)
+
+		if len(qn.dataVotesQueue) > numDataVotesToKeep {
+			delete(qn.dataVotes, qn.dataVotesQueue[0])
+			qn.dataVotesQueue = qn.dataVotesQueue[1:]
+		}
+	}
+
+	_, hasVoted := sources[source]
+	if hasVoted {
+		return false
+	}
+	sources[source] = struct{}{}
+
+	return len(sources) == qn.quorum
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (qn *quorumNotifier) IsInterfaceNil() bool {
+	return qn == nil
+}
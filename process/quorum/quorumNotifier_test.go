@@ -0,0 +1,546 @@
+package quorum
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+func createArgs(notifier process.SovereignNotifier, quorum uint32) ArgsQuorumNotifier {
+	return ArgsQuorumNotifier{
+		Notifier:          notifier,
+		AccountsTracker:   &testscommon.AccountsTrackerStub{},
+		ValidatorsTracker: &testscommon.ValidatorsTrackerStub{},
+		LivenessTracker:   &testscommon.LivenessTrackerStub{},
+		Quorum:            quorum,
+	}
+}
+
+func createOutportBlock(headerHash string, content string) *outport.OutportBlock {
+	return &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderHash:  []byte(headerHash),
+			HeaderBytes: []byte(content),
+		},
+	}
+}
+
+// createNotifierStub uses the header bytes as incoming header hash and records the notified blocks
+func createNotifierStub(notified *[]*outport.OutportBlock) *testscommon.SovereignNotifierStub {
+	return &testscommon.SovereignNotifierStub{
+		ComputeIncomingHeaderHashCalled: func(outportBlock *outport.OutportBlock) ([]byte, error) {
+			return outportBlock.BlockData.HeaderBytes, nil
+		},
+		NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+			*notified = append(*notified, finalizedBlock)
+			return nil
+		},
+	}
+}
+
+func createSourceNotifiers(t *testing.T, qn *quorumNotifier, numSources int) []process.SovereignNotifier {
+	sourceNotifiers := make([]process.SovereignNotifier, 0, numSources)
+	for i := 0; i < numSources; i++ {
+		sourceNotifier, err := qn.SourceNotifier(fmt.Sprintf("source%d", i))
+		require.Nil(t, err)
+		sourceNotifiers = append(sourceNotifiers, sourceNotifier)
+	}
+
+	return sourceNotifiers
+}
+
+func TestNewQuorumNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		qn, err := NewQuorumNotifier(createArgs(&testscommon.SovereignNotifierStub{}, 2))
+		require.Nil(t, err)
+		require.False(t, check.IfNil(qn))
+	})
+
+	t.Run("nil notifier, should return error", func(t *testing.T) {
+		qn, err := NewQuorumNotifier(createArgs(nil, 2))
+		require.Equal(t, errNilSovereignNotifier, err)
+		require.Nil(t, qn)
+	})
+
+	t.Run("notifier without incoming header hash computer, should return error", func(t *testing.T) {
+		notifier := struct {
+			process.SovereignNotifier
+		}{
+			SovereignNotifier: &testscommon.SovereignNotifierStub{},
+		}
+		qn, err := NewQuorumNotifier(createArgs(notifier, 2))
+		require.Equal(t, errNotIncomingHeaderHashComputer, err)
+		require.Nil(t, qn)
+	})
+
+	t.Run("nil accounts tracker, should return error", func(t *testing.T) {
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.AccountsTracker = nil
+		qn, err := NewQuorumNotifier(args)
+		require.Equal(t, errNilAccountsTracker, err)
+		require.Nil(t, qn)
+	})
+
+	t.Run("nil validators tracker, should return error", func(t *testing.T) {
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.ValidatorsTracker = nil
+		qn, err := NewQuorumNotifier(args)
+		require.Equal(t, errNilValidatorsTracker, err)
+		require.Nil(t, qn)
+	})
+
+	t.Run("nil liveness tracker, should return error", func(t *testing.T) {
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.LivenessTracker = nil
+		qn, err := NewQuorumNotifier(args)
+		require.Equal(t, errNilLivenessTracker, err)
+		require.Nil(t, qn)
+	})
+
+	t.Run("zero quorum, should return error", func(t *testing.T) {
+		qn, err := NewQuorumNotifier(createArgs(&testscommon.SovereignNotifierStub{}, 0))
+		require.Equal(t, errInvalidQuorum, err)
+		require.Nil(t, qn)
+	})
+}
+
+func TestQuorumNotifier_SourceNotifier(t *testing.T) {
+	t.Parallel()
+
+	qn, _ := NewQuorumNotifier(createArgs(&testscommon.SovereignNotifierStub{}, 2))
+
+	sourceNotifier, err := qn.SourceNotifier("")
+	require.Equal(t, errEmptySource, err)
+	require.Nil(t, sourceNotifier)
+
+	sourceNotifier, err = qn.SourceNotifier("source")
+	require.Nil(t, err)
+	require.False(t, check.IfNil(sourceNotifier))
+}
+
+func TestQuorumNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("block is notified once the quorum is reached", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]*outport.OutportBlock, 0)
+		qn, _ := NewQuorumNotifier(createArgs(createNotifierStub(&notified), 2))
+		sourceNotifiers := createSourceNotifiers(t, qn, 3)
+
+		outportBlock := createOutportBlock("hash", "content")
+		err := sourceNotifiers[0].Notify(outportBlock)
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		// the same source voting again should not count
+		err = sourceNotifiers[0].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		err = sourceNotifiers[1].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Equal(t, []*outport.OutportBlock{outportBlock}, notified)
+
+		err = sourceNotifiers[2].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Len(t, notified, 1)
+	})
+
+	t.Run("different contents for the same header should not reach quorum together", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]*outport.OutportBlock, 0)
+		qn, _ := NewQuorumNotifier(createArgs(createNotifierStub(&notified), 2))
+		sourceNotifiers := createSourceNotifiers(t, qn, 3)
+
+		err := sourceNotifiers[0].Notify(createOutportBlock("hash", "malicious content"))
+		require.Nil(t, err)
+		err = sourceNotifiers[1].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		err = sourceNotifiers[2].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Len(t, notified, 1)
+		require.Equal(t, []byte("content"), notified[0].BlockData.HeaderBytes)
+	})
+
+	t.Run("different accounts or miniblocks for the same incoming header should not reach quorum together", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]*outport.OutportBlock, 0)
+		qn, _ := NewQuorumNotifier(createArgs(createNotifierStub(&notified), 2))
+		sourceNotifiers := createSourceNotifiers(t, qn, 4)
+
+		outportBlock := createOutportBlock("hash", "content")
+		outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+			"addr": {Address: "addr", Balance: "10"},
+		}
+		outportBlock.BlockData.Body = &block.Body{
+			MiniBlocks: []*block.MiniBlock{{TxHashes: [][]byte{[]byte("tx")}}},
+		}
+		outportBlock.TransactionPool = &outport.TransactionPool{
+			Transactions: map[string]*outport.TxInfo{
+				hex.EncodeToString([]byte("tx")): {ExecutionOrder: 1},
+			},
+		}
+
+		differentAccounts := createOutportBlock("hash", "content")
+		differentAccounts.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{
+			"addr": {Address: "addr", Balance: "1000"},
+		}
+		differentAccounts.BlockData.Body = outportBlock.BlockData.Body
+		differentAccounts.TransactionPool = outportBlock.TransactionPool
+		err := sourceNotifiers[0].Notify(differentAccounts)
+		require.Nil(t, err)
+
+		differentMiniBlocks := createOutportBlock("hash", "content")
+		differentMiniBlocks.AlteredAccounts = outportBlock.AlteredAccounts
+		differentMiniBlocks.BlockData.Body = &block.Body{
+			MiniBlocks: []*block.MiniBlock{{TxHashes: [][]byte{[]byte("other tx")}}},
+		}
+		differentMiniBlocks.TransactionPool = outportBlock.TransactionPool
+		err = sourceNotifiers[1].Notify(differentMiniBlocks)
+		require.Nil(t, err)
+
+		differentTxs := createOutportBlock("hash", "content")
+		differentTxs.AlteredAccounts = outportBlock.AlteredAccounts
+		differentTxs.BlockData.Body = outportBlock.BlockData.Body
+		differentTxs.TransactionPool = &outport.TransactionPool{
+			Transactions: map[string]*outport.TxInfo{
+				hex.EncodeToString([]byte("tx")): {ExecutionOrder: 2},
+			},
+		}
+		err = sourceNotifiers[2].Notify(differentTxs)
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		err = sourceNotifiers[3].Notify(outportBlock)
+		require.Nil(t, err)
+		require.Empty(t, notified)
+
+		err = sourceNotifiers[0].Notify(outportBlock)
+		require.Nil(t, err)
+		require.Equal(t, []*outport.OutportBlock{outportBlock}, notified)
+	})
+
+	t.Run("incoming header hash error, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		errCompute := errors.New("cannot compute hash")
+		notifier := &testscommon.SovereignNotifierStub{
+			ComputeIncomingHeaderHashCalled: func(outportBlock *outport.OutportBlock) ([]byte, error) {
+				return nil, errCompute
+			},
+		}
+		qn, _ := NewQuorumNotifier(createArgs(notifier, 1))
+		sourceNotifiers := createSourceNotifiers(t, qn, 1)
+
+		err := sourceNotifiers[0].Notify(createOutportBlock("hash", "content"))
+		require.Equal(t, errCompute, err)
+	})
+
+	t.Run("notify error should be retried on the next vote", func(t *testing.T) {
+		t.Parallel()
+
+		errNotify := errors.New("cannot notify")
+		numNotifyCalls := 0
+		notifier := &testscommon.SovereignNotifierStub{
+			ComputeIncomingHeaderHashCalled: func(outportBlock *outport.OutportBlock) ([]byte, error) {
+				return outportBlock.BlockData.HeaderBytes, nil
+			},
+			NotifyCalled: func(finalizedBlock *outport.OutportBlock) error {
+				numNotifyCalls++
+				if numNotifyCalls == 1 {
+					return errNotify
+				}
+
+				return nil
+			},
+		}
+		finalizedHashes := make([][]byte, 0)
+		args := createArgs(notifier, 2)
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		sourceNotifiers := createSourceNotifiers(t, qn, 3)
+
+		err := sourceNotifiers[0].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		err = sourceNotifiers[1].Notify(createOutportBlock("hash", "content"))
+		require.Equal(t, errNotify, err)
+		require.Empty(t, finalizedHashes)
+
+		err = sourceNotifiers[1].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Equal(t, 2, numNotifyCalls)
+		require.Equal(t, [][]byte{[]byte("hash")}, finalizedHashes)
+
+		err = sourceNotifiers[2].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Equal(t, 2, numNotifyCalls)
+	})
+
+	t.Run("trackers are updated only with the notified block", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]*outport.OutportBlock, 0)
+		finalizedHashes := make([][]byte, 0)
+		updatedAccounts := make([]map[string]*alteredAccount.AlteredAccount, 0)
+		args := createArgs(createNotifierStub(&notified), 2)
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		args.AccountsTracker = &testscommon.AccountsTrackerStub{
+			UpdateAccountsCalled: func(alteredAccounts map[string]*alteredAccount.AlteredAccount) {
+				updatedAccounts = append(updatedAccounts, alteredAccounts)
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		sourceNotifiers := createSourceNotifiers(t, qn, 3)
+
+		maliciousBlock := createOutportBlock("hash", "malicious content")
+		maliciousBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{"erd1a": {Address: "erd1a", Balance: "100"}}
+		err := sourceNotifiers[0].Notify(maliciousBlock)
+		require.Nil(t, err)
+
+		outportBlock := createOutportBlock("hash", "content")
+		outportBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{"erd1a": {Address: "erd1a", Balance: "1"}}
+		err = sourceNotifiers[1].Notify(outportBlock)
+		require.Nil(t, err)
+		require.Empty(t, finalizedHashes)
+		require.Empty(t, updatedAccounts)
+
+		sameBlock := createOutportBlock("hash", "content")
+		sameBlock.AlteredAccounts = map[string]*alteredAccount.AlteredAccount{"erd1a": {Address: "erd1a", Balance: "1"}}
+		err = sourceNotifiers[2].Notify(sameBlock)
+		require.Nil(t, err)
+		require.Equal(t, [][]byte{[]byte("hash")}, finalizedHashes)
+		require.Equal(t, []map[string]*alteredAccount.AlteredAccount{outportBlock.AlteredAccounts}, updatedAccounts)
+	})
+
+	t.Run("queued block should update the trackers once delivered", func(t *testing.T) {
+		t.Parallel()
+
+		var deliver func()
+		notified := make([]*outport.OutportBlock, 0)
+		finalizedHashes := make([][]byte, 0)
+		args := createArgs(&testscommon.QueuingNotifierStub{
+			SovereignNotifierStub: *createNotifierStub(&notified),
+			NotifyWithDeliveryHandlerCalled: func(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error {
+				deliver = deliveryHandler
+				return nil
+			},
+		}, 2)
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveFinalizedBlockCalled: func(headerHash []byte) {
+				finalizedHashes = append(finalizedHashes, headerHash)
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		sourceNotifiers := createSourceNotifiers(t, qn, 2)
+
+		err := sourceNotifiers[0].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		err = sourceNotifiers[1].Notify(createOutportBlock("hash", "content"))
+		require.Nil(t, err)
+		require.Empty(t, notified)
+		require.Empty(t, finalizedHashes)
+
+		deliver()
+		require.Equal(t, [][]byte{[]byte("hash")}, finalizedHashes)
+	})
+
+	t.Run("old blocks votes are removed", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make([]*outport.OutportBlock, 0)
+		qn, _ := NewQuorumNotifier(createArgs(createNotifierStub(&notified), 2))
+		sourceNotifiers := createSourceNotifiers(t, qn, 1)
+
+		for i := 0; i < numBlocksToKeep+1; i++ {
+			err := sourceNotifiers[0].Notify(createOutportBlock(fmt.Sprintf("hash%d", i), "content"))
+			require.Nil(t, err)
+		}
+
+		require.Len(t, qn.blocks, numBlocksToKeep)
+		require.Len(t, qn.blocksQueue, numBlocksToKeep)
+		_, found := qn.blocks["hash0"]
+		require.False(t, found)
+	})
+}
+
+func TestSourceNotifier_DelegatesToUnderlyingNotifier(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	notifier := &testscommon.SovereignNotifierStub{
+		ComputeIncomingHeaderHashCalled: func(outportBlock *outport.OutportBlock) ([]byte, error) {
+			calls = append(calls, "compute")
+			return nil, nil
+		},
+		RegisterHandlerCalled: func(handler process.IncomingHeaderSubscriber) error {
+			calls = append(calls, "register")
+			return nil
+		},
+		RegisterHandlerWithPolicyCalled: func(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+			calls = append(calls, "register with policy")
+			return nil
+		},
+		NotifyHeartbeatCalled: func(heartbeat *process.Heartbeat) error {
+			calls = append(calls, "heartbeat")
+			return nil
+		},
+	}
+	qn, _ := NewQuorumNotifier(createArgs(notifier, 1))
+	sourceNotifierHandler, _ := qn.SourceNotifier("source")
+	srcNotifier := sourceNotifierHandler.(*sourceNotifier)
+
+	_, _ = srcNotifier.ComputeIncomingHeaderHash(&outport.OutportBlock{})
+	_ = srcNotifier.RegisterHandler(&testscommon.HeaderSubscriberStub{})
+	_ = srcNotifier.RegisterHandlerWithPolicy(&testscommon.HeaderSubscriberStub{}, process.NotificationPolicy{})
+	_ = srcNotifier.NotifyHeartbeat(&process.Heartbeat{})
+	require.Equal(t, []string{"compute", "register", "register with policy", "heartbeat"}, calls)
+}
+
+func TestQuorumNotifier_SourceComponents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty source, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		qn, _ := NewQuorumNotifier(createArgs(&testscommon.SovereignNotifierStub{}, 2))
+		components, err := qn.SourceComponents("")
+		require.Equal(t, errEmptySource, err)
+		require.Nil(t, components)
+	})
+
+	t.Run("rounds are saved once reported by the quorum", func(t *testing.T) {
+		t.Parallel()
+
+		savedRounds := make([]*outport.RoundsInfo, 0)
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			SaveRoundsInfoCalled: func(roundsInfo *outport.RoundsInfo) {
+				savedRounds = append(savedRounds, roundsInfo)
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		components0, _ := qn.SourceComponents("source0")
+		components1, _ := qn.SourceComponents("source1")
+
+		round1 := &outport.RoundInfo{Round: 1, ShardId: 1, BlockWasProposed: true}
+		round2 := &outport.RoundInfo{Round: 2, ShardId: 1, BlockWasProposed: true}
+		components0.LivenessTracker.SaveRoundsInfo(&outport.RoundsInfo{ShardID: 1, RoundsInfo: []*outport.RoundInfo{round1, round2}})
+		components0.LivenessTracker.SaveRoundsInfo(&outport.RoundsInfo{ShardID: 1, RoundsInfo: []*outport.RoundInfo{round1}})
+		require.Empty(t, savedRounds)
+
+		fakeRound2 := &outport.RoundInfo{Round: 2, ShardId: 1}
+		components1.LivenessTracker.SaveRoundsInfo(&outport.RoundsInfo{ShardID: 1, RoundsInfo: []*outport.RoundInfo{round1, fakeRound2}})
+		require.Equal(t, []*outport.RoundsInfo{{ShardID: 1, RoundsInfo: []*outport.RoundInfo{round1}}}, savedRounds)
+
+		// finalized blocks are saved only by the quorum notifier
+		components0.LivenessTracker.SaveFinalizedBlock([]byte("hash"))
+	})
+
+	t.Run("validators sets and ratings are saved once reported by the quorum", func(t *testing.T) {
+		t.Parallel()
+
+		savedPubKeys := make([]*outport.ValidatorsPubKeys, 0)
+		savedRatings := make([]*outport.ValidatorsRating, 0)
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.ValidatorsTracker = &testscommon.ValidatorsTrackerStub{
+			SaveValidatorsPubKeysCalled: func(validatorsPubKeys *outport.ValidatorsPubKeys) {
+				savedPubKeys = append(savedPubKeys, validatorsPubKeys)
+			},
+			SaveValidatorsRatingCalled: func(validatorsRating *outport.ValidatorsRating) {
+				savedRatings = append(savedRatings, validatorsRating)
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		components0, _ := qn.SourceComponents("source0")
+		components1, _ := qn.SourceComponents("source1")
+
+		pubKeys := &outport.PubKeys{Keys: [][]byte{[]byte("pk1"), []byte("pk2")}}
+		components0.ValidatorsTracker.SaveValidatorsPubKeys(&outport.ValidatorsPubKeys{
+			Epoch:                  1,
+			ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{0: pubKeys, 1: {Keys: [][]byte{[]byte("pk3")}}},
+		})
+		components1.ValidatorsTracker.SaveValidatorsPubKeys(&outport.ValidatorsPubKeys{
+			Epoch:                  1,
+			ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{0: pubKeys, 1: {Keys: [][]byte{[]byte("fake")}}},
+		})
+		require.Equal(t, []*outport.ValidatorsPubKeys{{
+			Epoch:                  1,
+			ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{0: pubKeys},
+		}}, savedPubKeys)
+
+		components0.ValidatorsTracker.SaveValidatorsRating(&outport.ValidatorsRating{
+			ShardID:              0,
+			Epoch:                1,
+			ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{{PublicKey: "pk1", Rating: 50}, {PublicKey: "pk2", Rating: 60}},
+		})
+		require.Empty(t, savedRatings)
+
+		// the ratings order does not matter
+		validatorsRating := &outport.ValidatorsRating{
+			ShardID:              0,
+			Epoch:                1,
+			ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{{PublicKey: "pk2", Rating: 60}, {PublicKey: "pk1", Rating: 50}},
+		}
+		components1.ValidatorsTracker.SaveValidatorsRating(validatorsRating)
+		require.Equal(t, []*outport.ValidatorsRating{validatorsRating}, savedRatings)
+	})
+
+	t.Run("getters and close use the shared trackers", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]string, 0)
+		args := createArgs(&testscommon.SovereignNotifierStub{}, 2)
+		args.ValidatorsTracker = &testscommon.ValidatorsTrackerStub{
+			GetValidatorsPubKeysCalled: func(shardID uint32, epoch uint32) ([][]byte, error) {
+				calls = append(calls, "get pub keys")
+				return nil, nil
+			},
+			GetValidatorsRatingsCalled: func(shardID uint32, epoch uint32) (map[string]float32, error) {
+				calls = append(calls, "get ratings")
+				return nil, nil
+			},
+		}
+		args.LivenessTracker = &testscommon.LivenessTrackerStub{
+			CloseCalled: func() error {
+				calls = append(calls, "close")
+				return nil
+			},
+		}
+		qn, _ := NewQuorumNotifier(args)
+		components, err := qn.SourceComponents("source")
+		require.Nil(t, err)
+		require.False(t, check.IfNil(components.Notifier))
+		require.False(t, check.IfNil(components.AccountsTracker))
+		require.False(t, check.IfNil(components.ValidatorsTracker))
+		require.False(t, check.IfNil(components.LivenessTracker))
+
+		_, _ = components.ValidatorsTracker.GetValidatorsPubKeys(0, 1)
+		_, _ = components.ValidatorsTracker.GetValidatorsRatings(0, 1)
+		_ = components.LivenessTracker.Close()
+		require.Equal(t, []string{"get pub keys", "get ratings", "close"}, calls)
+	})
+}
@@ -0,0 +1,53 @@
+package quorum
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// sourceNotifier is the notifier used by the indexer of a single observer, which votes for the finalized blocks
+type sourceNotifier struct {
+	source         string
+	quorumNotifier *quorumNotifier
+}
+
+// Notify will vote for the finalized block on behalf of the observer
+func (sn *sourceNotifier) Notify(finalizedBlock *outport.OutportBlock) error {
+	return sn.quorumNotifier.notifyFromSource(sn.source, finalizedBlock)
+}
+
+// ComputeIncomingHeaderHash will compute the incoming header hash with the underlying notifier
+func (sn *sourceNotifier) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	return sn.quorumNotifier.hashComputer.ComputeIncomingHeaderHash(outportBlock)
+}
+
+// RegisterHandler will register the handler in the underlying notifier
+func (sn *sourceNotifier) RegisterHandler(handler process.IncomingHeaderSubscriber) error {
+	return sn.quorumNotifier.notifier.RegisterHandler(handler)
+}
+
+// RegisterHandlerWithPolicy will register the handler in the underlying notifier, if it supports notification policies
+func (sn *sourceNotifier) RegisterHandlerWithPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	registerer, ok := sn.quorumNotifier.notifier.(process.PolicyHandlerRegisterer)
+	if !ok {
+		return errNotPolicyHandlerRegisterer
+	}
+
+	return registerer.RegisterHandlerWithPolicy(handler, policy)
+}
+
+// NotifyHeartbeat will notify the heartbeat through the underlying notifier, if it notifies heartbeats
+func (sn *sourceNotifier) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	heartbeatNotifier, ok := sn.quorumNotifier.notifier.(process.HeartbeatNotifier)
+	if !ok {
+		return errNotHeartbeatNotifier
+	}
+
+	return heartbeatNotifier.NotifyHeartbeat(heartbeat)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sn *sourceNotifier) IsInterfaceNil() bool {
+	return sn == nil
+}
@@ -0,0 +1,140 @@
+package quorum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+)
+
+// sourceValidatorsTracker is the validators tracker used by the indexer of a single observer, which votes for the
+// validators sets and ratings of each shard and epoch
+type sourceValidatorsTracker struct {
+	source         string
+	quorumNotifier *quorumNotifier
+}
+
+// SaveValidatorsPubKeys will vote for the validators set of each shard on behalf of the observer. A validators set is
+// saved once reported by the quorum of observers
+func (svt *sourceValidatorsTracker) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) {
+	for shardID, pubKeys := range validatorsPubKeys.ShardValidatorsPubKeys {
+		if pubKeys == nil {
+			continue
+		}
+
+		key := fmt.Sprintf("validators-%d-%d-%s", shardID, validatorsPubKeys.Epoch, hashStrings(encodePubKeys(pubKeys.Keys)))
+		if !svt.quorumNotifier.voteData(svt.source, key) {
+			continue
+		}
+
+		log.Debug("quorum reached for validators set", "shard", shardID, "epoch", validatorsPubKeys.Epoch)
+		svt.quorumNotifier.validatorsTracker.SaveValidatorsPubKeys(&outport.ValidatorsPubKeys{
+			ShardID:                validatorsPubKeys.ShardID,
+			ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{shardID: pubKeys},
+			Epoch:                  validatorsPubKeys.Epoch,
+		})
+	}
+}
+
+func encodePubKeys(pubKeys [][]byte) []string {
+	encodedPubKeys := make([]string, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		encodedPubKeys = append(encodedPubKeys, hex.EncodeToString(pubKey))
+	}
+
+	return encodedPubKeys
+}
+
+// SaveValidatorsRating will vote for the validators ratings on behalf of the observer. The ratings are saved once
+// reported by the quorum of observers, regardless of their order
+func (svt *sourceValidatorsTracker) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) {
+	ratings := make([]string, 0, len(validatorsRating.ValidatorsRatingInfo))
+	for _, ratingInfo := range validatorsRating.ValidatorsRatingInfo {
+		if ratingInfo == nil {
+			continue
+		}
+
+		ratings = append(ratings, fmt.Sprintf("%s:%v", ratingInfo.PublicKey, ratingInfo.Rating))
+	}
+	sort.Strings(ratings)
+
+	key := fmt.Sprintf("ratings-%d-%d-%s", validatorsRating.ShardID, validatorsRating.Epoch, hashStrings(ratings))
+	if !svt.quorumNotifier.voteData(svt.source, key) {
+		return
+	}
+
+	log.Debug("quorum reached for validators ratings", "shard", validatorsRating.ShardID, "epoch", validatorsRating.Epoch)
+	svt.quorumNotifier.validatorsTracker.SaveValidatorsRating(validatorsRating)
+}
+
+// hashStrings keeps the votes keys short, since validators sets and ratings can be large
+func hashStrings(values []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(values, ",")))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetValidatorsPubKeys returns the validators set agreed by the quorum of observers
+func (svt *sourceValidatorsTracker) GetValidatorsPubKeys(shardID uint32, epoch uint32) ([][]byte, error) {
+	return svt.quorumNotifier.validatorsTracker.GetValidatorsPubKeys(shardID, epoch)
+}
+
+// GetValidatorsRatings returns the validators ratings agreed by the quorum of observers
+func (svt *sourceValidatorsTracker) GetValidatorsRatings(shardID uint32, epoch uint32) (map[string]float32, error) {
+	return svt.quorumNotifier.validatorsTracker.GetValidatorsRatings(shardID, epoch)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (svt *sourceValidatorsTracker) IsInterfaceNil() bool {
+	return svt == nil
+}
+
+// sourceLivenessTracker is the liveness tracker used by the indexer of a single observer, which votes for the rounds
+// of each shard. The finalized blocks are saved by the quorum notifier once notified
+type sourceLivenessTracker struct {
+	source         string
+	quorumNotifier *quorumNotifier
+}
+
+// SaveRoundsInfo will vote for each round on behalf of the observer. A round is saved once reported by the quorum of
+// observers
+func (slt *sourceLivenessTracker) SaveRoundsInfo(roundsInfo *outport.RoundsInfo) {
+	for _, roundInfo := range roundsInfo.RoundsInfo {
+		if roundInfo == nil {
+			continue
+		}
+
+		key := fmt.Sprintf("round-%d-%d-%d-%d-%t-%v",
+			roundInfo.ShardId,
+			roundInfo.Round,
+			roundInfo.Epoch,
+			roundInfo.Timestamp,
+			roundInfo.BlockWasProposed,
+			roundInfo.SignersIndexes,
+		)
+		if !slt.quorumNotifier.voteData(slt.source, key) {
+			continue
+		}
+
+		slt.quorumNotifier.livenessTracker.SaveRoundsInfo(&outport.RoundsInfo{
+			ShardID:    roundsInfo.ShardID,
+			RoundsInfo: []*outport.RoundInfo{roundInfo},
+		})
+	}
+}
+
+// SaveFinalizedBlock does nothing, since only the blocks notified by the quorum notifier are saved
+func (slt *sourceLivenessTracker) SaveFinalizedBlock(_ []byte) {
+}
+
+// Close will close the shared liveness tracker
+func (slt *sourceLivenessTracker) Close() error {
+	return slt.quorumNotifier.livenessTracker.Close()
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (slt *sourceLivenessTracker) IsInterfaceNil() bool {
+	return slt == nil
+}
@@ -0,0 +1,5 @@
+package sharding
+
+import "errors"
+
+var errInvalidNumberOfShards = errors.New("the number of shards must be greater than zero")
@@ -0,0 +1,87 @@
+package sharding
+
+import (
+	"math"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+type multiShardCoordinator struct {
+	maskHigh       uint32
+	maskLow        uint32
+	numberOfShards uint32
+}
+
+// NewMultiShardCoordinator creates a shard coordinator which computes the shard of an address the same way as the
+// main chain nodes, for the provided number of shards
+func NewMultiShardCoordinator(numberOfShards uint32) (*multiShardCoordinator, error) {
+	if numberOfShards < 1 {
+		return nil, errInvalidNumberOfShards
+	}
+
+	maskHigh, maskLow := calculateMasks(numberOfShards)
+
+	return &multiShardCoordinator{
+		maskHigh:       maskHigh,
+		maskLow:        maskLow,
+		numberOfShards: numberOfShards,
+	}, nil
+}
+
+// calculateMasks returns the masks applied on the last bytes of an address to compute its shard
+func calculateMasks(numberOfShards uint32) (uint32, uint32) {
+	if numberOfShards == 1 {
+		return 0, 0
+	}
+
+	n := math.Ceil(math.Log2(float64(numberOfShards)))
+	return (1 << uint(n)) - 1, (1 << uint(n-1)) - 1
+}
+
+// ComputeId returns the shard id of the provided address. Smart contracts deployed on the metachain are placed in the
+// metachain shard
+func (msc *multiShardCoordinator) ComputeId(address []byte) uint32 {
+	var bytesNeed int
+	switch {
+	case msc.numberOfShards <= math.MaxUint8+1:
+		bytesNeed = 1
+	case msc.numberOfShards <= math.MaxUint16+1:
+		bytesNeed = 2
+	case msc.numberOfShards <= 1<<24:
+		bytesNeed = 3
+	default:
+		bytesNeed = 4
+	}
+
+	startingIndex := 0
+	if len(address) > bytesNeed {
+		startingIndex = len(address) - bytesNeed
+	}
+
+	buffNeeded := address[startingIndex:]
+	if core.IsSmartContractOnMetachain(buffNeeded, address) {
+		return core.MetachainShardId
+	}
+
+	addr := uint32(0)
+	for i := 0; i < len(buffNeeded); i++ {
+		addr = addr<<8 + uint32(buffNeeded[i])
+	}
+
+	shard := addr & msc.maskHigh
+	if shard > msc.numberOfShards-1 {
+		shard = addr & msc.maskLow
+	}
+
+	return shard
+}
+
+// NumberOfShards returns the number of shards
+func (msc *multiShardCoordinator) NumberOfShards() uint32 {
+	return msc.numberOfShards
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (msc *multiShardCoordinator) IsInterfaceNil() bool {
+	return msc == nil
+}
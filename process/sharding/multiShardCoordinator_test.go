@@ -0,0 +1,68 @@
+package sharding
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/require"
+)
+
+func createAddress(lastByte byte) []byte {
+	address := make([]byte, 32)
+	address[0] = 1
+	address[31] = lastByte
+
+	return address
+}
+
+func TestNewMultiShardCoordinator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero shards, should return error", func(t *testing.T) {
+		coordinator, err := NewMultiShardCoordinator(0)
+		require.Equal(t, errInvalidNumberOfShards, err)
+		require.Nil(t, coordinator)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		coordinator, err := NewMultiShardCoordinator(3)
+		require.Nil(t, err)
+		require.False(t, check.IfNil(coordinator))
+		require.Equal(t, uint32(3), coordinator.NumberOfShards())
+	})
+}
+
+func TestMultiShardCoordinator_ComputeId(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single shard", func(t *testing.T) {
+		coordinator, _ := NewMultiShardCoordinator(1)
+		for lastByte := 0; lastByte < 256; lastByte++ {
+			require.Equal(t, uint32(0), coordinator.ComputeId(createAddress(byte(lastByte))))
+		}
+	})
+
+	t.Run("three shards", func(t *testing.T) {
+		coordinator, _ := NewMultiShardCoordinator(3)
+		require.Equal(t, uint32(0), coordinator.ComputeId(createAddress(0x00)))
+		require.Equal(t, uint32(1), coordinator.ComputeId(createAddress(0x01)))
+		require.Equal(t, uint32(2), coordinator.ComputeId(createAddress(0x02)))
+		require.Equal(t, uint32(1), coordinator.ComputeId(createAddress(0x03)))
+		require.Equal(t, uint32(0), coordinator.ComputeId(createAddress(0xFC)))
+		require.Equal(t, uint32(1), coordinator.ComputeId(createAddress(0xFF)))
+	})
+
+	t.Run("four shards", func(t *testing.T) {
+		coordinator, _ := NewMultiShardCoordinator(4)
+		require.Equal(t, uint32(3), coordinator.ComputeId(createAddress(0x03)))
+		require.Equal(t, uint32(3), coordinator.ComputeId(createAddress(0xFF)))
+	})
+
+	t.Run("metachain smart contract", func(t *testing.T) {
+		coordinator, _ := NewMultiShardCoordinator(3)
+		esdtSystemSC, _ := hex.DecodeString("000000000000000000010000000000000000000000000000000000000002ffff")
+		require.Equal(t, core.MetachainShardId, coordinator.ComputeId(esdtSystemSC))
+	})
+}
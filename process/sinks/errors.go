@@ -0,0 +1,11 @@
+package sinks
+
+import "errors"
+
+var errNilIncomingHeaderFormatter = errors.New("nil incoming header formatter provided")
+
+var errNilWriter = errors.New("nil writer provided")
+
+var errEmptyFilePath = errors.New("empty file path provided")
+
+var errNilIncomingHeader = errors.New("nil incoming header provided")
@@ -0,0 +1,35 @@
+package sinks
+
+import "github.com/multiversx/mx-chain-core-go/core/check"
+
+const bytesInMb = 1024 * 1024
+
+// ArgsFileSink is a struct placeholder for args needed to create a file sink
+type ArgsFileSink struct {
+	Path                    string
+	MaxFileSizeMb           uint32
+	MaxBackups              uint32
+	IncomingHeaderFormatter IncomingHeaderFormatter
+}
+
+// NewFileSink creates a subscriber which appends each notified incoming header to the provided NDJSON file. The file
+// is rotated once it reaches the maximum size, keeping the provided number of rotated files. If the maximum size is 0,
+// the file is never rotated
+func NewFileSink(args ArgsFileSink) (*ndjsonSink, error) {
+	if check.IfNil(args.IncomingHeaderFormatter) {
+		return nil, errNilIncomingHeaderFormatter
+	}
+
+	writer, err := newRotatingFileWriter(args.Path, int64(args.MaxFileSizeMb)*bytesInMb, int(args.MaxBackups))
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := NewNDJSONSink(writer, args.IncomingHeaderFormatter)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+
+	return sink, nil
+}
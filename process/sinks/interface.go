@@ -0,0 +1,13 @@
+package sinks
+
+import (
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+)
+
+// IncomingHeaderFormatter should render incoming headers in human-readable form
+type IncomingHeaderFormatter interface {
+	FormatIncomingHeader(incomingHeaderHash []byte, incomingHeader sovereign.IncomingHeaderHandler) (*formatter.IncomingHeader, error)
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,99 @@
+package sinks
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("notifier-sinks-process")
+
+type ndjsonSink struct {
+	headerFormatter IncomingHeaderFormatter
+
+	mutWriter sync.Mutex
+	writer    io.WriteCloser
+}
+
+// NewNDJSONSink creates a subscriber which appends each notified incoming header to the provided writer, formatted
+// as a single line of JSON. The writer is closed when the sink is closed
+func NewNDJSONSink(writer io.WriteCloser, headerFormatter IncomingHeaderFormatter) (*ndjsonSink, error) {
+	if writer == nil {
+		return nil, errNilWriter
+	}
+	if check.IfNil(headerFormatter) {
+		return nil, errNilIncomingHeaderFormatter
+	}
+
+	return &ndjsonSink{
+		headerFormatter: headerFormatter,
+		writer:          writer,
+	}, nil
+}
+
+// NewStdoutSink creates a subscriber which prints each notified incoming header to stdout, formatted as a single
+// line of JSON. Stdout is not closed when the sink is closed
+func NewStdoutSink(headerFormatter IncomingHeaderFormatter) (*ndjsonSink, error) {
+	return NewNDJSONSink(&nopCloser{Writer: os.Stdout}, headerFormatter)
+}
+
+// AddHeader writes the notified incoming header as a new line. Failures are only logged, so that the sink never fails
+// the notification of the other subscribers
+func (sink *ndjsonSink) AddHeader(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+	if check.IfNil(header) {
+		return errNilIncomingHeader
+	}
+
+	err := sink.writeHeader(headerHash, header)
+	if err != nil {
+		log.Error("could not write incoming header to sink", "incoming header hash", hex.EncodeToString(headerHash), "error", err)
+	}
+
+	return nil
+}
+
+func (sink *ndjsonSink) writeHeader(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+	formattedHeader, err := sink.headerFormatter.FormatIncomingHeader(headerHash, header)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(formattedHeader)
+	if err != nil {
+		return err
+	}
+
+	sink.mutWriter.Lock()
+	defer sink.mutWriter.Unlock()
+
+	_, err = sink.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying writer
+func (sink *ndjsonSink) Close() error {
+	sink.mutWriter.Lock()
+	defer sink.mutWriter.Unlock()
+
+	return sink.writer.Close()
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sink *ndjsonSink) IsInterfaceNil() bool {
+	return sink == nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+// Close does nothing
+func (nc *nopCloser) Close() error {
+	return nil
+}
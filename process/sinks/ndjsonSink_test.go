@@ -0,0 +1,217 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/formatter"
+)
+
+type writeCloserMock struct {
+	bytes.Buffer
+	writeErr error
+	closed   bool
+}
+
+func (wcm *writeCloserMock) Write(data []byte) (int, error) {
+	if wcm.writeErr != nil {
+		return 0, wcm.writeErr
+	}
+
+	return wcm.Buffer.Write(data)
+}
+
+func (wcm *writeCloserMock) Close() error {
+	wcm.closed = true
+	return nil
+}
+
+func createHeaderFormatter(t *testing.T) IncomingHeaderFormatter {
+	addressPubkeyConverter, _ := pubkeyConverter.NewBech32PubkeyConverter(32, "erd")
+	headerFormatter, err := formatter.NewIncomingHeaderFormatter(addressPubkeyConverter)
+	require.Nil(t, err)
+
+	return headerFormatter
+}
+
+func createIncomingHeader(nonce uint64) *sovereign.IncomingHeader {
+	return &sovereign.IncomingHeader{
+		Header: &block.HeaderV2{
+			Header: &block.Header{
+				Nonce: nonce,
+				Round: nonce + 1,
+			},
+		},
+		IncomingEvents: []*transaction.Event{
+			{
+				Address:    bytes.Repeat([]byte{1}, 32),
+				Identifier: []byte("deposit"),
+				Topics:     [][]byte{[]byte("WEGLD-bd4d79")},
+			},
+		},
+	}
+}
+
+func readLines(t *testing.T, output string) []*formatter.IncomingHeader {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	headers := make([]*formatter.IncomingHeader, 0, len(lines))
+	for _, line := range lines {
+		header := &formatter.IncomingHeader{}
+		err := json.Unmarshal([]byte(line), header)
+		require.Nil(t, err)
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+func TestNewNDJSONSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil writer should error", func(t *testing.T) {
+		sink, err := NewNDJSONSink(nil, createHeaderFormatter(t))
+		require.Equal(t, errNilWriter, err)
+		require.Nil(t, sink)
+	})
+
+	t.Run("nil formatter should error", func(t *testing.T) {
+		sink, err := NewNDJSONSink(&writeCloserMock{}, nil)
+		require.Equal(t, errNilIncomingHeaderFormatter, err)
+		require.Nil(t, sink)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		sink, err := NewNDJSONSink(&writeCloserMock{}, createHeaderFormatter(t))
+		require.Nil(t, err)
+		require.False(t, check.IfNil(sink))
+	})
+}
+
+func TestNewStdoutSink(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewStdoutSink(createHeaderFormatter(t))
+	require.Nil(t, err)
+	require.False(t, check.IfNil(sink))
+	require.Nil(t, sink.Close())
+}
+
+func TestNdjsonSink_AddHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil header should error", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &writeCloserMock{}
+		sink, _ := NewNDJSONSink(writer, createHeaderFormatter(t))
+
+		err := sink.AddHeader([]byte("hash"), nil)
+		require.Equal(t, errNilIncomingHeader, err)
+		require.Zero(t, writer.Len())
+	})
+
+	t.Run("write error should only be logged", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &writeCloserMock{writeErr: errors.New("write error")}
+		sink, _ := NewNDJSONSink(writer, createHeaderFormatter(t))
+
+		err := sink.AddHeader([]byte("hash"), createIncomingHeader(1))
+		require.Nil(t, err)
+
+		writer.writeErr = nil
+		err = sink.AddHeader([]byte("hash"), createIncomingHeader(2))
+		require.Nil(t, err)
+		require.Equal(t, 1, strings.Count(writer.String(), "\n"))
+	})
+
+	t.Run("should write one line per header", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &writeCloserMock{}
+		sink, _ := NewNDJSONSink(writer, createHeaderFormatter(t))
+
+		err := sink.AddHeader([]byte{0xaa}, createIncomingHeader(4))
+		require.Nil(t, err)
+		err = sink.AddHeader([]byte{0xbb}, createIncomingHeader(5))
+		require.Nil(t, err)
+
+		headers := readLines(t, writer.String())
+		require.Len(t, headers, 2)
+		require.Equal(t, "aa", headers[0].IncomingHeaderHash)
+		require.Equal(t, uint64(4), headers[0].Nonce)
+		require.Equal(t, "bb", headers[1].IncomingHeaderHash)
+		require.Equal(t, uint64(6), headers[1].Round)
+		require.Len(t, headers[1].Events, 1)
+		require.Equal(t, "deposit", headers[1].Events[0].Identifier)
+		require.Equal(t, "WEGLD-bd4d79", headers[1].Events[0].Topics[0].Text)
+
+		err = sink.Close()
+		require.Nil(t, err)
+		require.True(t, writer.closed)
+	})
+}
+
+func TestNewFileSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil formatter should error without creating the file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		sink, err := NewFileSink(ArgsFileSink{Path: path})
+		require.Equal(t, errNilIncomingHeaderFormatter, err)
+		require.Nil(t, sink)
+
+		_, err = os.Stat(path)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("empty path should error", func(t *testing.T) {
+		t.Parallel()
+
+		sink, err := NewFileSink(ArgsFileSink{IncomingHeaderFormatter: createHeaderFormatter(t)})
+		require.Equal(t, errEmptyFilePath, err)
+		require.Nil(t, sink)
+	})
+
+	t.Run("should append headers to the file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "output", "headers.ndjson")
+		args := ArgsFileSink{
+			Path:                    path,
+			MaxFileSizeMb:           1,
+			MaxBackups:              2,
+			IncomingHeaderFormatter: createHeaderFormatter(t),
+		}
+		sink, err := NewFileSink(args)
+		require.Nil(t, err)
+		require.Nil(t, sink.AddHeader([]byte{0xaa}, createIncomingHeader(4)))
+		require.Nil(t, sink.Close())
+
+		sink, err = NewFileSink(args)
+		require.Nil(t, err)
+		require.Nil(t, sink.AddHeader([]byte{0xbb}, createIncomingHeader(5)))
+		require.Nil(t, sink.Close())
+
+		content, err := os.ReadFile(path)
+		require.Nil(t, err)
+		headers := readLines(t, string(content))
+		require.Len(t, headers, 2)
+		require.Equal(t, uint64(4), headers[0].Nonce)
+		require.Equal(t, uint64(5), headers[1].Nonce)
+	})
+}
@@ -0,0 +1,70 @@
+package sinks
+
+import (
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/sovereign"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+type reloadableSink struct {
+	mutSinks    sync.RWMutex
+	headerSinks []process.IncomingHeaderSink
+}
+
+// NewReloadableSink creates a subscriber which forwards each notified incoming header to the provided sinks, which can
+// be replaced while the notifier is running
+func NewReloadableSink(headerSinks []process.IncomingHeaderSink) *reloadableSink {
+	return &reloadableSink{
+		headerSinks: headerSinks,
+	}
+}
+
+// AddHeader forwards the notified incoming header to the current sinks
+func (rs *reloadableSink) AddHeader(headerHash []byte, header sovereign.IncomingHeaderHandler) error {
+	rs.mutSinks.RLock()
+	defer rs.mutSinks.RUnlock()
+
+	for _, sink := range rs.headerSinks {
+		err := sink.AddHeader(headerHash, header)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplaceSinks replaces the current sinks with the provided ones, then closes the replaced sinks
+func (rs *reloadableSink) ReplaceSinks(headerSinks []process.IncomingHeaderSink) {
+	rs.mutSinks.Lock()
+	replacedSinks := rs.headerSinks
+	rs.headerSinks = headerSinks
+	rs.mutSinks.Unlock()
+
+	closeSinks(replacedSinks)
+	log.Info("replaced incoming header sinks", "num sinks", len(headerSinks))
+}
+
+// Close closes the current sinks
+func (rs *reloadableSink) Close() error {
+	rs.mutSinks.Lock()
+	defer rs.mutSinks.Unlock()
+
+	closeSinks(rs.headerSinks)
+	rs.headerSinks = nil
+
+	return nil
+}
+
+func closeSinks(headerSinks []process.IncomingHeaderSink) {
+	for _, sink := range headerSinks {
+		log.LogIfError(sink.Close())
+	}
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (rs *reloadableSink) IsInterfaceNil() bool {
+	return rs == nil
+}
@@ -0,0 +1,72 @@
+package sinks
+
+import (
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/require"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+func TestNewReloadableSink(t *testing.T) {
+	t.Parallel()
+
+	sink := NewReloadableSink(nil)
+	require.False(t, check.IfNil(sink))
+	require.Nil(t, sink.AddHeader([]byte("hash"), createIncomingHeader(1)))
+	require.Nil(t, sink.Close())
+}
+
+func TestReloadableSink_AddHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil header should error", func(t *testing.T) {
+		t.Parallel()
+
+		writer := &writeCloserMock{}
+		ndjson, _ := NewNDJSONSink(writer, createHeaderFormatter(t))
+		sink := NewReloadableSink([]process.IncomingHeaderSink{ndjson})
+
+		err := sink.AddHeader([]byte("hash"), nil)
+		require.Equal(t, errNilIncomingHeader, err)
+		require.Zero(t, writer.Len())
+	})
+
+	t.Run("should forward the header to all the sinks", func(t *testing.T) {
+		t.Parallel()
+
+		firstWriter := &writeCloserMock{}
+		firstSink, _ := NewNDJSONSink(firstWriter, createHeaderFormatter(t))
+		secondWriter := &writeCloserMock{}
+		secondSink, _ := NewNDJSONSink(secondWriter, createHeaderFormatter(t))
+		sink := NewReloadableSink([]process.IncomingHeaderSink{firstSink, secondSink})
+
+		err := sink.AddHeader([]byte("hash"), createIncomingHeader(1))
+		require.Nil(t, err)
+		require.Len(t, readLines(t, firstWriter.String()), 1)
+		require.Len(t, readLines(t, secondWriter.String()), 1)
+	})
+}
+
+func TestReloadableSink_ReplaceSinks(t *testing.T) {
+	t.Parallel()
+
+	oldWriter := &writeCloserMock{}
+	oldSink, _ := NewNDJSONSink(oldWriter, createHeaderFormatter(t))
+	sink := NewReloadableSink([]process.IncomingHeaderSink{oldSink})
+
+	newWriter := &writeCloserMock{}
+	newSink, _ := NewNDJSONSink(newWriter, createHeaderFormatter(t))
+	sink.ReplaceSinks([]process.IncomingHeaderSink{newSink})
+	require.True(t, oldWriter.closed)
+	require.False(t, newWriter.closed)
+
+	err := sink.AddHeader([]byte("hash"), createIncomingHeader(1))
+	require.Nil(t, err)
+	require.Zero(t, oldWriter.Len())
+	require.Len(t, readLines(t, newWriter.String()), 1)
+
+	require.Nil(t, sink.Close())
+	require.True(t, newWriter.closed)
+}
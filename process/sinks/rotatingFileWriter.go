@@ -0,0 +1,122 @@
+package sinks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const filePermissions = 0644
+
+// rotatingFileWriter appends to a file which is rotated once it would exceed the maximum size. Rotated files are
+// renamed with a numeric suffix, the most recent being <path>.1, and the oldest ones are removed. It is not safe for
+// concurrent use
+type rotatingFileWriter struct {
+	path        string
+	maxFileSize int64
+	maxBackups  int
+	file        *os.File
+	size        int64
+}
+
+func newRotatingFileWriter(path string, maxFileSize int64, maxBackups int) (*rotatingFileWriter, error) {
+	if len(path) == 0 {
+		return nil, errEmptyFilePath
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &rotatingFileWriter{
+		path:        path,
+		maxFileSize: maxFileSize,
+		maxBackups:  maxBackups,
+	}
+	err = writer.openFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (writer *rotatingFileWriter) openFile() error {
+	file, err := os.OpenFile(writer.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	writer.file = file
+	writer.size = info.Size()
+
+	return nil
+}
+
+// Write appends the provided data to the current file, after rotating it if the data would not fit. The data is
+// never split between files, so a file exceeds the maximum size only if it holds a single write or if its rotation
+// failed
+func (writer *rotatingFileWriter) Write(data []byte) (int, error) {
+	shouldRotate := writer.maxFileSize > 0 && writer.size > 0 && writer.size+int64(len(data)) > writer.maxFileSize
+	if shouldRotate {
+		err := writer.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := writer.file.Write(data)
+	writer.size += int64(n)
+
+	return n, err
+}
+
+// rotate reopens the file even if the rotation failed, so that the writer is not left closed. The rotation is retried
+// on the next write
+func (writer *rotatingFileWriter) rotate() error {
+	err := writer.file.Close()
+	if err == nil {
+		err = writer.shiftBackups()
+	}
+	if err != nil {
+		log.Warn("could not rotate the sink file, appending to the current file", "path", writer.path, "error", err)
+	}
+
+	return writer.openFile()
+}
+
+func (writer *rotatingFileWriter) shiftBackups() error {
+	if writer.maxBackups == 0 {
+		return os.Remove(writer.path)
+	}
+
+	err := os.Remove(writer.backupPath(writer.maxBackups))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := writer.maxBackups - 1; i > 0; i-- {
+		err = os.Rename(writer.backupPath(i), writer.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(writer.path, writer.backupPath(1))
+}
+
+func (writer *rotatingFileWriter) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", writer.path, index)
+}
+
+// Close closes the current file
+func (writer *rotatingFileWriter) Close() error {
+	return writer.file.Close()
+}
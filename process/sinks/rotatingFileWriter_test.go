@@ -0,0 +1,132 @@
+package sinks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+
+	return string(content)
+}
+
+func TestRotatingFileWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should rotate when the data would exceed the maximum size", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		writer, err := newRotatingFileWriter(path, 10, 2)
+		require.Nil(t, err)
+
+		for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+			n, errWrite := writer.Write([]byte(line))
+			require.Nil(t, errWrite)
+			require.Equal(t, len(line), n)
+		}
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line4\n", readFile(t, path))
+		require.Equal(t, "line3\n", readFile(t, path+".1"))
+		require.Equal(t, "line2\n", readFile(t, path+".2"))
+		_, err = os.Stat(path + ".3")
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("data larger than the maximum size should not be split", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		writer, err := newRotatingFileWriter(path, 4, 1)
+		require.Nil(t, err)
+
+		_, err = writer.Write([]byte("line1\n"))
+		require.Nil(t, err)
+		_, err = writer.Write([]byte("line2\n"))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line2\n", readFile(t, path))
+		require.Equal(t, "line1\n", readFile(t, path+".1"))
+	})
+
+	t.Run("no backups should truncate the file on rotation", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		writer, err := newRotatingFileWriter(path, 10, 0)
+		require.Nil(t, err)
+
+		_, err = writer.Write([]byte("line1\n"))
+		require.Nil(t, err)
+		_, err = writer.Write([]byte("line2\n"))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line2\n", readFile(t, path))
+		_, err = os.Stat(path + ".1")
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("zero maximum size should never rotate", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		writer, err := newRotatingFileWriter(path, 0, 1)
+		require.Nil(t, err)
+
+		_, err = writer.Write([]byte("line1\n"))
+		require.Nil(t, err)
+		_, err = writer.Write([]byte("line2\n"))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line1\nline2\n", readFile(t, path))
+	})
+
+	t.Run("existing file size should be taken into account", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		require.Nil(t, os.WriteFile(path, []byte("line1\n"), filePermissions))
+
+		writer, err := newRotatingFileWriter(path, 10, 1)
+		require.Nil(t, err)
+		_, err = writer.Write([]byte("line2\n"))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line2\n", readFile(t, path))
+		require.Equal(t, "line1\n", readFile(t, path+".1"))
+	})
+
+	t.Run("failed rotation should keep appending to the current file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "headers.ndjson")
+		writer, err := newRotatingFileWriter(path, 10, 1)
+		require.Nil(t, err)
+		_, err = writer.Write([]byte("line1\n"))
+		require.Nil(t, err)
+
+		// a non-empty directory can not be replaced by the rotated file
+		require.Nil(t, os.MkdirAll(filepath.Join(path+".1", "dir"), os.ModePerm))
+		_, err = writer.Write([]byte("line2\n"))
+		require.Nil(t, err)
+		require.Equal(t, "line1\nline2\n", readFile(t, path))
+
+		require.Nil(t, os.RemoveAll(path+".1"))
+		_, err = writer.Write([]byte("line3\n"))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		require.Equal(t, "line3\n", readFile(t, path))
+		require.Equal(t, "line1\nline2\n", readFile(t, path+".1"))
+	})
+}
@@ -0,0 +1,56 @@
+package validators
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/hashing"
+)
+
+const uint64Size = 8
+
+type consensusGroupSelector struct {
+	hasher hashing.Hasher
+}
+
+func newConsensusGroupSelector(hasher hashing.Hasher) *consensusGroupSelector {
+	return &consensusGroupSelector{
+		hasher: hasher,
+	}
+}
+
+// computeConsensusGroup selects the positions in the validators list of the consensus group members for a round. The
+// randomness is the previous random seed of the header. Each member is picked by hashing its position in the group with
+// the round randomness, the already selected validators being skipped, so all validators have the same chances
+func (cgs *consensusGroupSelector) computeConsensusGroup(
+	randomness []byte,
+	round uint64,
+	numValidators int,
+	consensusGroupSize int,
+) (map[uint64]struct{}, error) {
+	if consensusGroupSize <= 0 || consensusGroupSize > numValidators {
+		return nil, fmt.Errorf("%w: %d, num validators: %d", errInvalidConsensusGroupSize, consensusGroupSize, numValidators)
+	}
+
+	roundRandomness := fmt.Sprintf("%d-%s", round, randomness)
+	consensusGroup := make(map[uint64]struct{}, consensusGroupSize)
+	buffIndex := make([]byte, uint64Size)
+	for i := 0; len(consensusGroup) < consensusGroupSize; i++ {
+		binary.BigEndian.PutUint64(buffIndex, uint64(i))
+		indexHash := cgs.hasher.Compute(string(buffIndex) + roundRandomness)
+		if len(indexHash) < uint64Size {
+			return nil, errHashTooShort
+		}
+
+		index := binary.BigEndian.Uint64(indexHash) % uint64(numValidators)
+		_, selected := consensusGroup[index]
+		for selected {
+			index = (index + 1) % uint64(numValidators)
+			_, selected = consensusGroup[index]
+		}
+
+		consensusGroup[index] = struct{}{}
+	}
+
+	return consensusGroup, nil
+}
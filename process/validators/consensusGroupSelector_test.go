@@ -0,0 +1,63 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusGroupSelector_ComputeConsensusGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid consensus group size, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		selector := newConsensusGroupSelector(sha256.NewSha256())
+
+		consensusGroup, err := selector.computeConsensusGroup([]byte("randomness"), 1, 4, 0)
+		require.True(t, errors.Is(err, errInvalidConsensusGroupSize))
+		require.Nil(t, consensusGroup)
+
+		consensusGroup, err = selector.computeConsensusGroup([]byte("randomness"), 1, 4, 5)
+		require.True(t, errors.Is(err, errInvalidConsensusGroupSize))
+		require.Nil(t, consensusGroup)
+	})
+
+	t.Run("should select distinct validators", func(t *testing.T) {
+		t.Parallel()
+
+		selector := newConsensusGroupSelector(sha256.NewSha256())
+
+		consensusGroup, err := selector.computeConsensusGroup([]byte("randomness"), 1, 10, 7)
+		require.Nil(t, err)
+		require.Len(t, consensusGroup, 7)
+		for index := range consensusGroup {
+			require.Less(t, index, uint64(10))
+		}
+
+		sameConsensusGroup, _ := selector.computeConsensusGroup([]byte("randomness"), 1, 10, 7)
+		require.Equal(t, consensusGroup, sameConsensusGroup)
+	})
+
+	t.Run("whole validators set should be selected", func(t *testing.T) {
+		t.Parallel()
+
+		selector := newConsensusGroupSelector(sha256.NewSha256())
+
+		consensusGroup, err := selector.computeConsensusGroup([]byte("randomness"), 1, 4, 4)
+		require.Nil(t, err)
+		require.Equal(t, map[uint64]struct{}{0: {}, 1: {}, 2: {}, 3: {}}, consensusGroup)
+	})
+
+	t.Run("different rounds should select different groups", func(t *testing.T) {
+		t.Parallel()
+
+		selector := newConsensusGroupSelector(sha256.NewSha256())
+
+		consensusGroup1, _ := selector.computeConsensusGroup([]byte("randomness"), 1, 100, 10)
+		consensusGroup2, _ := selector.computeConsensusGroup([]byte("randomness"), 2, 100, 10)
+		require.NotEqual(t, consensusGroup1, consensusGroup2)
+	})
+}
@@ -0,0 +1,22 @@
+package validators
+
+import "github.com/multiversx/mx-chain-core-go/data"
+
+type disabledHeaderVerifier struct {
+}
+
+// NewDisabledHeaderVerifier creates a header verifier which accepts all headers, used when signature verification
+// is not enabled
+func NewDisabledHeaderVerifier() *disabledHeaderVerifier {
+	return &disabledHeaderVerifier{}
+}
+
+// VerifyHeader returns nil
+func (dhv *disabledHeaderVerifier) VerifyHeader(_ data.HeaderHandler, _ []uint64) error {
+	return nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (dhv *disabledHeaderVerifier) IsInterfaceNil() bool {
+	return dhv == nil
+}
@@ -0,0 +1,33 @@
+package validators
+
+import "errors"
+
+var errNilMarshaller = errors.New("nil marshaller provided")
+
+var errNilHasher = errors.New("nil hasher provided")
+
+var errNilMultiSigVerifier = errors.New("nil multi signature verifier provided")
+
+var errNilValidatorsTracker = errors.New("nil validators tracker provided")
+
+var errNilHeader = errors.New("nil header provided")
+
+var errValidatorsNotFound = errors.New("validators not found")
+
+var errNoSigners = errors.New("no signers provided")
+
+var errInvalidSignerIndex = errors.New("invalid signer index")
+
+var errSignersBitmapMismatch = errors.New("number of signers does not match the pub keys bitmap")
+
+var errNilSignature = errors.New("nil aggregated signature")
+
+var errNotEnoughSigners = errors.New("not enough signers")
+
+var errInvalidValidatorsFile = errors.New("invalid validators file")
+
+var errInvalidConsensusGroupSize = errors.New("invalid consensus group size")
+
+var errHashTooShort = errors.New("hash too short to select the consensus group")
+
+var errSignerNotInConsensusGroup = errors.New("signer not in the consensus group")
@@ -0,0 +1,182 @@
+package validators
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+)
+
+// ArgsHeaderSignatureVerifier is a struct placeholder for args needed to create a header signature verifier. If a
+// consensus group size is 0 or greater than the number of validators, the whole validators set is the consensus group
+type ArgsHeaderSignatureVerifier struct {
+	Marshaller             marshal.Marshalizer
+	Hasher                 hashing.Hasher
+	MultiSigVerifier       process.MultiSigVerifier
+	ValidatorsTracker      process.ValidatorsTracker
+	ConsensusGroupSize     uint32
+	MetaConsensusGroupSize uint32
+}
+
+type headerSignatureVerifier struct {
+	marshaller             marshal.Marshalizer
+	hasher                 hashing.Hasher
+	multiSigVerifier       process.MultiSigVerifier
+	validatorsTracker      process.ValidatorsTracker
+	consensusGroupSelector *consensusGroupSelector
+	consensusGroupSize     uint32
+	metaConsensusGroupSize uint32
+}
+
+// NewHeaderSignatureVerifier creates a verifier which checks that headers carry a valid aggregated signature
+// from at least 2/3+1 of the consensus group selected for the header's round, out of the tracked validators set of
+// the header's shard and epoch
+func NewHeaderSignatureVerifier(args ArgsHeaderSignatureVerifier) (*headerSignatureVerifier, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, errNilMarshaller
+	}
+	if check.IfNil(args.Hasher) {
+		return nil, errNilHasher
+	}
+	if check.IfNil(args.MultiSigVerifier) {
+		return nil, errNilMultiSigVerifier
+	}
+	if check.IfNil(args.ValidatorsTracker) {
+		return nil, errNilValidatorsTracker
+	}
+
+	return &headerSignatureVerifier{
+		marshaller:             args.Marshaller,
+		hasher:                 args.Hasher,
+		multiSigVerifier:       args.MultiSigVerifier,
+		validatorsTracker:      args.ValidatorsTracker,
+		consensusGroupSelector: newConsensusGroupSelector(args.Hasher),
+		consensusGroupSize:     args.ConsensusGroupSize,
+		metaConsensusGroupSize: args.MetaConsensusGroupSize,
+	}, nil
+}
+
+// VerifyHeader will verify the header's aggregated signature. Signers indexes are the positions of the consensus
+// signers in the shard's validators list, as provided by the observer in the outport block.
+func (hsv *headerSignatureVerifier) VerifyHeader(header data.HeaderHandler, signersIndexes []uint64) error {
+	if check.IfNil(header) {
+		return errNilHeader
+	}
+	if len(header.GetSignature()) == 0 {
+		return errNilSignature
+	}
+
+	signersPubKeys, err := hsv.getSignersPubKeys(header, signersIndexes)
+	if err != nil {
+		return err
+	}
+
+	hash, err := hsv.computeHashWithoutSignatures(header)
+	if err != nil {
+		return err
+	}
+
+	return hsv.multiSigVerifier.VerifyAggregatedSig(signersPubKeys, hash, header.GetSignature())
+}
+
+func (hsv *headerSignatureVerifier) getSignersPubKeys(header data.HeaderHandler, signersIndexes []uint64) ([][]byte, error) {
+	if len(signersIndexes) == 0 {
+		return nil, errNoSigners
+	}
+
+	numSignersInBitmap := 0
+	for _, b := range header.GetPubKeysBitmap() {
+		numSignersInBitmap += bits.OnesCount8(b)
+	}
+	if numSignersInBitmap != len(signersIndexes) {
+		return nil, fmt.Errorf("%w, num signers: %d, num signers in bitmap: %d",
+			errSignersBitmapMismatch, len(signersIndexes), numSignersInBitmap)
+	}
+
+	validatorsPubKeys, err := hsv.validatorsTracker.GetValidatorsPubKeys(header.GetShardID(), header.GetEpoch())
+	if err != nil {
+		return nil, err
+	}
+
+	consensusGroupSize := hsv.getConsensusGroupSize(header.GetShardID(), len(validatorsPubKeys))
+	minNumSigners := getMinNumSigners(consensusGroupSize)
+	if len(signersIndexes) < minNumSigners {
+		return nil, fmt.Errorf("%w, num signers: %d, min num signers: %d, consensus group size: %d",
+			errNotEnoughSigners, len(signersIndexes), minNumSigners, consensusGroupSize)
+	}
+
+	consensusGroup, err := hsv.consensusGroupSelector.computeConsensusGroup(
+		header.GetPrevRandSeed(),
+		header.GetRound(),
+		len(validatorsPubKeys),
+		consensusGroupSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signersPubKeys := make([][]byte, 0, len(signersIndexes))
+	for _, index := range signersIndexes {
+		if index >= uint64(len(validatorsPubKeys)) {
+			return nil, fmt.Errorf("%w: %d, num validators: %d", errInvalidSignerIndex, index, len(validatorsPubKeys))
+		}
+		_, isConsensusMember := consensusGroup[index]
+		if !isConsensusMember {
+			return nil, fmt.Errorf("%w, signer index: %d, round: %d", errSignerNotInConsensusGroup, index, header.GetRound())
+		}
+
+		signersPubKeys = append(signersPubKeys, validatorsPubKeys[index])
+	}
+
+	return signersPubKeys, nil
+}
+
+// getConsensusGroupSize returns the configured consensus group size of the shard, capped to the number of validators
+func (hsv *headerSignatureVerifier) getConsensusGroupSize(shardID uint32, numValidators int) int {
+	consensusGroupSize := hsv.consensusGroupSize
+	if shardID == core.MetachainShardId {
+		consensusGroupSize = hsv.metaConsensusGroupSize
+	}
+	if consensusGroupSize == 0 || int(consensusGroupSize) > numValidators {
+		return numValidators
+	}
+
+	return int(consensusGroupSize)
+}
+
+// getMinNumSigners returns the consensus threshold of a consensus group, 2/3+1 of its size
+func getMinNumSigners(numValidators int) int {
+	return numValidators*2/3 + 1
+}
+
+// computeHashWithoutSignatures computes the header hash signed by the consensus group, the same way a node does
+func (hsv *headerSignatureVerifier) computeHashWithoutSignatures(header data.HeaderHandler) ([]byte, error) {
+	headerCopy := header.ShallowClone()
+	err := headerCopy.SetSignature(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = headerCopy.SetPubKeysBitmap(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = headerCopy.SetLeaderSignature(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.CalculateHash(hsv.marshaller, hsv.hasher, headerCopy)
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (hsv *headerSignatureVerifier) IsInterfaceNil() bool {
+	return hsv == nil
+}
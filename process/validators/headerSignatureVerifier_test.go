@@ -0,0 +1,286 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+var validatorsPubKeys = [][]byte{[]byte("pk0"), []byte("pk1"), []byte("pk2"), []byte("pk3")}
+
+func createHeaderSignatureVerifierArgs() ArgsHeaderSignatureVerifier {
+	return ArgsHeaderSignatureVerifier{
+		Marshaller:       &testscommon.MarshallerMock{},
+		Hasher:           sha256.NewSha256(),
+		MultiSigVerifier: &testscommon.MultiSigVerifierStub{},
+		ValidatorsTracker: &testscommon.ValidatorsTrackerStub{
+			GetValidatorsPubKeysCalled: func(shardID uint32, epoch uint32) ([][]byte, error) {
+				return validatorsPubKeys, nil
+			},
+		},
+	}
+}
+
+func createSignedHeader() *block.HeaderV2 {
+	return &block.HeaderV2{
+		Header: &block.Header{
+			Nonce:           4,
+			ShardID:         1,
+			Epoch:           2,
+			PubKeysBitmap:   []byte{13},
+			Signature:       []byte("signature"),
+			LeaderSignature: []byte("leader signature"),
+		},
+	}
+}
+
+func TestNewHeaderSignatureVerifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		verifier, err := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(verifier))
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createHeaderSignatureVerifierArgs()
+		args.Marshaller = nil
+		verifier, err := NewHeaderSignatureVerifier(args)
+		require.Equal(t, errNilMarshaller, err)
+		require.Nil(t, verifier)
+	})
+
+	t.Run("nil hasher, should return error", func(t *testing.T) {
+		args := createHeaderSignatureVerifierArgs()
+		args.Hasher = nil
+		verifier, err := NewHeaderSignatureVerifier(args)
+		require.Equal(t, errNilHasher, err)
+		require.Nil(t, verifier)
+	})
+
+	t.Run("nil multi sig verifier, should return error", func(t *testing.T) {
+		args := createHeaderSignatureVerifierArgs()
+		args.MultiSigVerifier = nil
+		verifier, err := NewHeaderSignatureVerifier(args)
+		require.Equal(t, errNilMultiSigVerifier, err)
+		require.Nil(t, verifier)
+	})
+
+	t.Run("nil validators tracker, should return error", func(t *testing.T) {
+		args := createHeaderSignatureVerifierArgs()
+		args.ValidatorsTracker = nil
+		verifier, err := NewHeaderSignatureVerifier(args)
+		require.Equal(t, errNilValidatorsTracker, err)
+		require.Nil(t, verifier)
+	})
+}
+
+func TestHeaderSignatureVerifier_VerifyHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid signature, should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createHeaderSignatureVerifierArgs()
+		header := createSignedHeader()
+
+		headerWithoutSignatures := createSignedHeader()
+		headerWithoutSignatures.Header.Signature = nil
+		headerWithoutSignatures.Header.PubKeysBitmap = nil
+		headerWithoutSignatures.Header.LeaderSignature = nil
+		expectedHash, _ := core.CalculateHash(args.Marshaller, args.Hasher, headerWithoutSignatures)
+
+		wasVerifyCalled := false
+		args.MultiSigVerifier = &testscommon.MultiSigVerifierStub{
+			VerifyAggregatedSigCalled: func(pubKeysSigners [][]byte, message []byte, aggSig []byte) error {
+				wasVerifyCalled = true
+				require.Equal(t, [][]byte{[]byte("pk0"), []byte("pk2"), []byte("pk3")}, pubKeysSigners)
+				require.Equal(t, expectedHash, message)
+				require.Equal(t, []byte("signature"), aggSig)
+				return nil
+			},
+		}
+		args.ValidatorsTracker = &testscommon.ValidatorsTrackerStub{
+			GetValidatorsPubKeysCalled: func(shardID uint32, epoch uint32) ([][]byte, error) {
+				require.Equal(t, uint32(1), shardID)
+				require.Equal(t, uint32(2), epoch)
+				return validatorsPubKeys, nil
+			},
+		}
+		verifier, _ := NewHeaderSignatureVerifier(args)
+
+		err := verifier.VerifyHeader(header, []uint64{0, 2, 3})
+		require.Nil(t, err)
+		require.True(t, wasVerifyCalled)
+		require.Equal(t, []byte("signature"), header.GetSignature())
+	})
+
+	t.Run("invalid signature, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		errVerify := errors.New("invalid signature")
+		args := createHeaderSignatureVerifierArgs()
+		args.MultiSigVerifier = &testscommon.MultiSigVerifierStub{
+			VerifyAggregatedSigCalled: func(pubKeysSigners [][]byte, message []byte, aggSig []byte) error {
+				return errVerify
+			},
+		}
+		verifier, _ := NewHeaderSignatureVerifier(args)
+
+		err := verifier.VerifyHeader(createSignedHeader(), []uint64{0, 2, 3})
+		require.Equal(t, errVerify, err)
+	})
+
+	t.Run("nil header, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, _ := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+
+		err := verifier.VerifyHeader(nil, []uint64{0, 2, 3})
+		require.Equal(t, errNilHeader, err)
+	})
+
+	t.Run("no signature, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, _ := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+		header := createSignedHeader()
+		header.Header.Signature = nil
+
+		err := verifier.VerifyHeader(header, []uint64{0, 2, 3})
+		require.Equal(t, errNilSignature, err)
+	})
+
+	t.Run("no signers, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, _ := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+
+		err := verifier.VerifyHeader(createSignedHeader(), nil)
+		require.Equal(t, errNoSigners, err)
+	})
+
+	t.Run("signers do not match bitmap, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, _ := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+
+		err := verifier.VerifyHeader(createSignedHeader(), []uint64{0})
+		require.True(t, errors.Is(err, errSignersBitmapMismatch))
+	})
+
+	t.Run("less than 2/3+1 signers, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		wasVerifyCalled := false
+		args := createHeaderSignatureVerifierArgs()
+		args.MultiSigVerifier = &testscommon.MultiSigVerifierStub{
+			VerifyAggregatedSigCalled: func(pubKeysSigners [][]byte, message []byte, aggSig []byte) error {
+				wasVerifyCalled = true
+				return nil
+			},
+		}
+		verifier, _ := NewHeaderSignatureVerifier(args)
+		header := createSignedHeader()
+		header.Header.PubKeysBitmap = []byte{5}
+
+		err := verifier.VerifyHeader(header, []uint64{0, 2})
+		require.True(t, errors.Is(err, errNotEnoughSigners))
+		require.False(t, wasVerifyCalled)
+	})
+
+	t.Run("consensus group smaller than the validators set, should work", func(t *testing.T) {
+		t.Parallel()
+
+		eligiblePubKeys := make([][]byte, 0, 10)
+		for i := 0; i < 10; i++ {
+			eligiblePubKeys = append(eligiblePubKeys, []byte{byte(i)})
+		}
+
+		args := createHeaderSignatureVerifierArgs()
+		args.ConsensusGroupSize = 4
+		args.ValidatorsTracker = &testscommon.ValidatorsTrackerStub{
+			GetValidatorsPubKeysCalled: func(shardID uint32, epoch uint32) ([][]byte, error) {
+				return eligiblePubKeys, nil
+			},
+		}
+		verifier, _ := NewHeaderSignatureVerifier(args)
+
+		header := createSignedHeader()
+		header.Header.Round = 7
+		header.Header.PrevRandSeed = []byte("prev rand seed")
+		consensusGroup, _ := newConsensusGroupSelector(args.Hasher).computeConsensusGroup(header.Header.PrevRandSeed, 7, 10, 4)
+		consensusMembers := make([]uint64, 0, 4)
+		nonMember := uint64(0)
+		for i := uint64(0); i < 10; i++ {
+			_, isMember := consensusGroup[i]
+			if isMember {
+				consensusMembers = append(consensusMembers, i)
+				continue
+			}
+			nonMember = i
+		}
+
+		// 3 signers reach the threshold of a 4 members consensus group, but not of the 10 validators set
+		err := verifier.VerifyHeader(header, consensusMembers[:3])
+		require.Nil(t, err)
+
+		header.Header.PubKeysBitmap = []byte{3}
+		err = verifier.VerifyHeader(header, consensusMembers[:2])
+		require.True(t, errors.Is(err, errNotEnoughSigners))
+
+		header.Header.PubKeysBitmap = []byte{7}
+		err = verifier.VerifyHeader(header, []uint64{consensusMembers[0], consensusMembers[1], nonMember})
+		require.True(t, errors.Is(err, errSignerNotInConsensusGroup))
+	})
+
+	t.Run("metachain header should use the metachain consensus group size", func(t *testing.T) {
+		t.Parallel()
+
+		args := createHeaderSignatureVerifierArgs()
+		args.ConsensusGroupSize = 1
+		args.MetaConsensusGroupSize = 4
+		verifier, _ := NewHeaderSignatureVerifier(args)
+		header := createSignedHeader()
+		header.Header.ShardID = core.MetachainShardId
+		header.Header.PubKeysBitmap = []byte{5}
+
+		err := verifier.VerifyHeader(header, []uint64{0, 2})
+		require.True(t, errors.Is(err, errNotEnoughSigners))
+	})
+
+	t.Run("validators not found, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createHeaderSignatureVerifierArgs()
+		args.ValidatorsTracker = NewValidatorsTracker()
+		verifier, _ := NewHeaderSignatureVerifier(args)
+
+		err := verifier.VerifyHeader(createSignedHeader(), []uint64{0, 2, 3})
+		require.True(t, errors.Is(err, errValidatorsNotFound))
+	})
+
+	t.Run("signer index out of range, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		verifier, _ := NewHeaderSignatureVerifier(createHeaderSignatureVerifierArgs())
+
+		err := verifier.VerifyHeader(createSignedHeader(), []uint64{0, 2, 4})
+		require.True(t, errors.Is(err, errInvalidSignerIndex))
+	})
+}
+
+func TestDisabledHeaderVerifier_VerifyHeader(t *testing.T) {
+	t.Parallel()
+
+	verifier := NewDisabledHeaderVerifier()
+	require.False(t, check.IfNil(verifier))
+	require.Nil(t, verifier.VerifyHeader(nil, nil))
+}
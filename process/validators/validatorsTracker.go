@@ -0,0 +1,196 @@
+package validators
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("notifier-validators-process")
+
+// numEpochsToKeep allows headers from the previous epochs to still be verified after an epoch change
+const numEpochsToKeep = 3
+
+type validatorsTracker struct {
+	mutValidators sync.RWMutex
+	pubKeys       map[uint32]map[uint32][][]byte
+	ratings       map[uint32]map[uint32]map[string]float32
+	latestEpoch   uint32
+	filePath      string
+}
+
+// NewValidatorsTracker creates a tracker which keeps the validators set and their ratings for each shard and epoch
+func NewValidatorsTracker() *validatorsTracker {
+	return &validatorsTracker{
+		pubKeys: make(map[uint32]map[uint32][][]byte),
+		ratings: make(map[uint32]map[uint32]map[string]float32),
+	}
+}
+
+// NewValidatorsTrackerWithStorage creates a validators tracker which also saves the validators public keys in the
+// provided file. The public keys saved by a previous run are loaded, since the validators sets are received only at
+// the start of an epoch, and a restart in the middle of an epoch would otherwise leave them unknown until the next one
+func NewValidatorsTrackerWithStorage(filePath string) (*validatorsTracker, error) {
+	vt := NewValidatorsTracker()
+	vt.filePath = filePath
+
+	err := vt.loadPubKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return vt, nil
+}
+
+func (vt *validatorsTracker) loadPubKeys() error {
+	buff, err := os.ReadFile(vt.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Debug("no saved validators pub keys found", "path", vt.filePath)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pubKeys := make(map[uint32]map[uint32][][]byte)
+	err = json.Unmarshal(buff, &pubKeys)
+	if err != nil {
+		return fmt.Errorf("%w, path: %s, error: %s", errInvalidValidatorsFile, vt.filePath, err.Error())
+	}
+
+	for epoch, shardsPubKeys := range pubKeys {
+		vt.pubKeys[epoch] = shardsPubKeys
+		vt.updateLatestEpoch(epoch)
+	}
+
+	log.Info("loaded saved validators pub keys", "path", vt.filePath, "latest epoch", vt.latestEpoch)
+
+	return nil
+}
+
+// savePubKeys writes the tracked public keys in a temporary file which then replaces the previous one, so that a
+// failed write does not corrupt the saved validators sets
+func (vt *validatorsTracker) savePubKeys() error {
+	buff, err := json.Marshal(vt.pubKeys)
+	if err != nil {
+		return err
+	}
+
+	tmpFilePath := vt.filePath + ".tmp"
+	err = os.WriteFile(tmpFilePath, buff, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, vt.filePath)
+}
+
+// SaveValidatorsPubKeys will save the validators public keys of each shard for the provided epoch
+func (vt *validatorsTracker) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) {
+	vt.mutValidators.Lock()
+	defer vt.mutValidators.Unlock()
+
+	epoch := validatorsPubKeys.Epoch
+	shardsPubKeys := make(map[uint32][][]byte, len(validatorsPubKeys.ShardValidatorsPubKeys))
+	for shardID, pubKeys := range validatorsPubKeys.ShardValidatorsPubKeys {
+		shardsPubKeys[shardID] = pubKeys.GetKeys()
+		log.Debug("saved validators pub keys", "shard", shardID, "epoch", epoch, "num validators", len(pubKeys.GetKeys()))
+	}
+
+	vt.pubKeys[epoch] = shardsPubKeys
+	vt.updateLatestEpoch(epoch)
+
+	if len(vt.filePath) == 0 {
+		return
+	}
+
+	err := vt.savePubKeys()
+	if err != nil {
+		log.Error("could not save validators pub keys", "path", vt.filePath, "epoch", epoch, "error", err.Error())
+	}
+}
+
+// SaveValidatorsRating will save the validators ratings of a shard for the provided epoch
+func (vt *validatorsTracker) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) {
+	vt.mutValidators.Lock()
+	defer vt.mutValidators.Unlock()
+
+	epoch := validatorsRating.Epoch
+	ratings := make(map[string]float32, len(validatorsRating.ValidatorsRatingInfo))
+	for _, ratingInfo := range validatorsRating.ValidatorsRatingInfo {
+		ratings[ratingInfo.PublicKey] = ratingInfo.Rating
+	}
+
+	_, found := vt.ratings[epoch]
+	if !found {
+		vt.ratings[epoch] = make(map[uint32]map[string]float32)
+	}
+
+	vt.ratings[epoch][validatorsRating.ShardID] = ratings
+	vt.updateLatestEpoch(epoch)
+
+	log.Debug("saved validators ratings", "shard", validatorsRating.ShardID, "epoch", epoch, "num validators", len(ratings))
+}
+
+func (vt *validatorsTracker) updateLatestEpoch(epoch uint32) {
+	if epoch <= vt.latestEpoch {
+		return
+	}
+
+	vt.latestEpoch = epoch
+	if epoch < numEpochsToKeep {
+		return
+	}
+
+	for storedEpoch := range vt.pubKeys {
+		if storedEpoch <= epoch-numEpochsToKeep {
+			delete(vt.pubKeys, storedEpoch)
+		}
+	}
+	for storedEpoch := range vt.ratings {
+		if storedEpoch <= epoch-numEpochsToKeep {
+			delete(vt.ratings, storedEpoch)
+		}
+	}
+}
+
+// GetValidatorsPubKeys returns the validators public keys of a shard for the provided epoch
+func (vt *validatorsTracker) GetValidatorsPubKeys(shardID uint32, epoch uint32) ([][]byte, error) {
+	vt.mutValidators.RLock()
+	defer vt.mutValidators.RUnlock()
+
+	pubKeys, found := vt.pubKeys[epoch][shardID]
+	if !found {
+		return nil, fmt.Errorf("%w for shard: %d, epoch: %d", errValidatorsNotFound, shardID, epoch)
+	}
+
+	return pubKeys, nil
+}
+
+// GetValidatorsRatings returns the validators ratings of a shard for the provided epoch, mapped by public key
+func (vt *validatorsTracker) GetValidatorsRatings(shardID uint32, epoch uint32) (map[string]float32, error) {
+	vt.mutValidators.RLock()
+	defer vt.mutValidators.RUnlock()
+
+	ratings, found := vt.ratings[epoch][shardID]
+	if !found {
+		return nil, fmt.Errorf("%w for shard: %d, epoch: %d", errValidatorsNotFound, shardID, epoch)
+	}
+
+	ratingsCopy := make(map[string]float32, len(ratings))
+	for pubKey, rating := range ratings {
+		ratingsCopy[pubKey] = rating
+	}
+
+	return ratingsCopy, nil
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (vt *validatorsTracker) IsInterfaceNil() bool {
+	return vt == nil
+}
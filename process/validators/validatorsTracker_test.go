@@ -0,0 +1,145 @@
+package validators
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidatorsPubKeys(epoch uint32) *outport.ValidatorsPubKeys {
+	return &outport.ValidatorsPubKeys{
+		Epoch: epoch,
+		ShardValidatorsPubKeys: map[uint32]*outport.PubKeys{
+			0: {Keys: [][]byte{[]byte("pk0"), []byte("pk1")}},
+			1: {Keys: [][]byte{[]byte("pk2")}},
+		},
+	}
+}
+
+func TestNewValidatorsTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewValidatorsTracker()
+	require.False(t, check.IfNil(tracker))
+}
+
+func TestNewValidatorsTrackerWithStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no saved file, should work", func(t *testing.T) {
+		t.Parallel()
+
+		tracker, err := NewValidatorsTrackerWithStorage(filepath.Join(t.TempDir(), "validators.json"))
+		require.Nil(t, err)
+		require.False(t, check.IfNil(tracker))
+	})
+
+	t.Run("saved pub keys should be loaded after a restart", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "validators.json")
+		tracker, _ := NewValidatorsTrackerWithStorage(filePath)
+		tracker.SaveValidatorsPubKeys(createValidatorsPubKeys(4))
+
+		restartedTracker, err := NewValidatorsTrackerWithStorage(filePath)
+		require.Nil(t, err)
+
+		pubKeys, err := restartedTracker.GetValidatorsPubKeys(0, 4)
+		require.Nil(t, err)
+		require.Equal(t, [][]byte{[]byte("pk0"), []byte("pk1")}, pubKeys)
+
+		pubKeys, err = restartedTracker.GetValidatorsPubKeys(1, 4)
+		require.Nil(t, err)
+		require.Equal(t, [][]byte{[]byte("pk2")}, pubKeys)
+	})
+
+	t.Run("invalid saved file, should return error", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "validators.json")
+		err := os.WriteFile(filePath, []byte("not json"), 0644)
+		require.Nil(t, err)
+
+		tracker, err := NewValidatorsTrackerWithStorage(filePath)
+		require.True(t, errors.Is(err, errInvalidValidatorsFile))
+		require.Nil(t, tracker)
+	})
+}
+
+func TestValidatorsTracker_SaveValidatorsPubKeys(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewValidatorsTracker()
+	tracker.SaveValidatorsPubKeys(createValidatorsPubKeys(4))
+
+	pubKeys, err := tracker.GetValidatorsPubKeys(0, 4)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("pk0"), []byte("pk1")}, pubKeys)
+
+	pubKeys, err = tracker.GetValidatorsPubKeys(1, 4)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("pk2")}, pubKeys)
+
+	pubKeys, err = tracker.GetValidatorsPubKeys(2, 4)
+	require.True(t, errors.Is(err, errValidatorsNotFound))
+	require.Nil(t, pubKeys)
+
+	pubKeys, err = tracker.GetValidatorsPubKeys(0, 5)
+	require.True(t, errors.Is(err, errValidatorsNotFound))
+	require.Nil(t, pubKeys)
+}
+
+func TestValidatorsTracker_SaveValidatorsRating(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewValidatorsTracker()
+	tracker.SaveValidatorsRating(&outport.ValidatorsRating{
+		ShardID: 1,
+		Epoch:   4,
+		ValidatorsRatingInfo: []*outport.ValidatorRatingInfo{
+			{PublicKey: "pk1", Rating: 50},
+			{PublicKey: "pk2", Rating: 75.5},
+		},
+	})
+
+	ratings, err := tracker.GetValidatorsRatings(1, 4)
+	require.Nil(t, err)
+	require.Equal(t, map[string]float32{"pk1": 50, "pk2": 75.5}, ratings)
+
+	ratings["pk1"] = 0
+	ratings, _ = tracker.GetValidatorsRatings(1, 4)
+	require.Equal(t, float32(50), ratings["pk1"])
+
+	ratings, err = tracker.GetValidatorsRatings(0, 4)
+	require.True(t, errors.Is(err, errValidatorsNotFound))
+	require.Nil(t, ratings)
+}
+
+func TestValidatorsTracker_OldEpochsAreRemoved(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewValidatorsTracker()
+	for epoch := uint32(1); epoch <= 5; epoch++ {
+		tracker.SaveValidatorsPubKeys(createValidatorsPubKeys(epoch))
+		tracker.SaveValidatorsRating(&outport.ValidatorsRating{ShardID: 0, Epoch: epoch})
+	}
+
+	for epoch := uint32(1); epoch <= 2; epoch++ {
+		_, err := tracker.GetValidatorsPubKeys(0, epoch)
+		require.True(t, errors.Is(err, errValidatorsNotFound))
+		_, err = tracker.GetValidatorsRatings(0, epoch)
+		require.True(t, errors.Is(err, errValidatorsNotFound))
+	}
+
+	for epoch := uint32(3); epoch <= 5; epoch++ {
+		_, err := tracker.GetValidatorsPubKeys(0, epoch)
+		require.Nil(t, err)
+		_, err = tracker.GetValidatorsRatings(0, epoch)
+		require.Nil(t, err)
+	}
+}
@@ -0,0 +1,289 @@
+package simulator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/smartContractResult"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+)
+
+const (
+	addressLen        = 32
+	hashLen           = 32
+	maxEventsPerLog   = 3
+	noiseIdentifier   = "transferValueOnly"
+	numberOfShards    = 3
+	blockTimeInterval = 6
+
+	maxTxsPerMiniBlock = 4
+	scrProbability     = 0.3
+)
+
+type subscribedDeposit struct {
+	identifier []byte
+	addresses  [][]byte
+}
+
+// ArgsBlockGenerator is a struct placeholder for args needed to create a block generator
+type ArgsBlockGenerator struct {
+	Marshaller             marshal.Marshalizer
+	Hasher                 hashing.Hasher
+	Randomness             *rand.Rand
+	SubscribedEvents       []notifier.SubscribedEvent
+	ShardID                uint32
+	NumDepositsPerBlock    uint32
+	NumNoiseEventsPerBlock uint32
+}
+
+type blockGenerator struct {
+	marshaller             marshal.Marshalizer
+	hasher                 hashing.Hasher
+	randomness             *rand.Rand
+	deposits               []*subscribedDeposit
+	shardID                uint32
+	numDepositsPerBlock    uint32
+	numNoiseEventsPerBlock uint32
+}
+
+// NewBlockGenerator creates a generator of synthetic outport blocks containing HeaderV2 headers and
+// logs with both subscribed deposit events and unrelated noise events
+func NewBlockGenerator(args ArgsBlockGenerator) (*blockGenerator, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, core.ErrNilMarshalizer
+	}
+	if check.IfNil(args.Hasher) {
+		return nil, errNilHasher
+	}
+	if args.Randomness == nil {
+		return nil, errNilRandomness
+	}
+	if len(args.SubscribedEvents) == 0 {
+		return nil, errNoSubscribedEvent
+	}
+
+	return &blockGenerator{
+		marshaller:             args.Marshaller,
+		hasher:                 args.Hasher,
+		randomness:             args.Randomness,
+		deposits:               createSubscribedDeposits(args.SubscribedEvents),
+		shardID:                args.ShardID,
+		numDepositsPerBlock:    args.NumDepositsPerBlock,
+		numNoiseEventsPerBlock: args.NumNoiseEventsPerBlock,
+	}, nil
+}
+
+// createSubscribedDeposits sorts the addresses so that blocks generated with the same seed are reproducible
+func createSubscribedDeposits(events []notifier.SubscribedEvent) []*subscribedDeposit {
+	deposits := make([]*subscribedDeposit, 0, len(events))
+	for _, event := range events {
+		addresses := make([][]byte, 0, len(event.Addresses))
+		for decodedAddr := range event.Addresses {
+			addresses = append(addresses, []byte(decodedAddr))
+		}
+
+		sort.Slice(addresses, func(i, j int) bool {
+			return string(addresses[i]) < string(addresses[j])
+		})
+
+		deposits = append(deposits, &subscribedDeposit{
+			identifier: event.Identifier,
+			addresses:  addresses,
+		})
+	}
+
+	return deposits
+}
+
+// GenerateBlock will generate an outport block on top of the provided previous header hash
+func (bg *blockGenerator) GenerateBlock(nonce uint64, round uint64, prevHash []byte) (*outport.OutportBlock, error) {
+	headerV2 := &block.HeaderV2{
+		Header: &block.Header{
+			Nonce:        nonce,
+			Round:        round,
+			ShardID:      bg.shardID,
+			PrevHash:     prevHash,
+			PrevRandSeed: bg.randomBytes(hashLen),
+			RandSeed:     bg.randomBytes(hashLen),
+			RootHash:     bg.randomBytes(hashLen),
+			TimeStamp:    round * blockTimeInterval,
+			ChainID:      []byte("simulator"),
+		},
+		ScheduledRootHash: bg.randomBytes(hashLen),
+	}
+
+	headerBytes, err := bg.marshaller.Marshal(headerV2)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &outport.TransactionPool{
+		Transactions:         make(map[string]*outport.TxInfo),
+		SmartContractResults: make(map[string]*outport.SCRInfo),
+		Logs:                 bg.generateLogs(),
+	}
+	miniBlocks, intraShardMiniBlocks, err := bg.generateExecutedTransactions(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outport.OutportBlock{
+		ShardID: bg.shardID,
+		BlockData: &outport.BlockData{
+			ShardID:              bg.shardID,
+			HeaderBytes:          headerBytes,
+			HeaderType:           string(core.ShardHeaderV2),
+			HeaderHash:           bg.hasher.Compute(string(headerBytes)),
+			Body:                 &block.Body{MiniBlocks: miniBlocks},
+			IntraShardMiniBlocks: intraShardMiniBlocks,
+		},
+		TransactionPool: pool,
+		NumberOfShards:  numberOfShards,
+	}, nil
+}
+
+// generateExecutedTransactions adds to the pool a transaction or a smart contract result, sent to the log address, for
+// each log, executed in a random order. Transactions are placed in the body miniblocks and smart contract results in
+// an intra shard miniblock, each in execution order, so that the miniblocks positions do not reflect the interleaved
+// execution order, as for smart contract results created while executing the block transactions
+func (bg *blockGenerator) generateExecutedTransactions(pool *outport.TransactionPool) ([]*block.MiniBlock, []*block.MiniBlock, error) {
+	txsMiniBlocks := make([]*block.MiniBlock, 0)
+	scrsMiniBlock := &block.MiniBlock{
+		SenderShardID:   bg.shardID,
+		ReceiverShardID: bg.shardID,
+		Type:            block.SmartContractResultBlock,
+	}
+
+	for executionOrder, logIndex := range bg.randomness.Perm(len(pool.Logs)) {
+		logData := pool.Logs[logIndex]
+		txHash, err := hex.DecodeString(logData.TxHash)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if bg.randomness.Float64() < scrProbability {
+			pool.SmartContractResults[logData.TxHash] = &outport.SCRInfo{
+				SmartContractResult: &smartContractResult.SmartContractResult{
+					Value:          big.NewInt(0),
+					RcvAddr:        logData.Log.Address,
+					SndAddr:        bg.randomBytes(addressLen),
+					OriginalTxHash: bg.randomBytes(hashLen),
+				},
+				ExecutionOrder: uint32(executionOrder),
+			}
+			scrsMiniBlock.TxHashes = append(scrsMiniBlock.TxHashes, txHash)
+			continue
+		}
+
+		pool.Transactions[logData.TxHash] = &outport.TxInfo{
+			Transaction: &transaction.Transaction{
+				Nonce:   uint64(bg.randomness.Intn(1000)),
+				Value:   big.NewInt(bg.randomness.Int63()),
+				RcvAddr: logData.Log.Address,
+				SndAddr: bg.randomBytes(addressLen),
+			},
+			ExecutionOrder: uint32(executionOrder),
+		}
+
+		lastIndex := len(txsMiniBlocks) - 1
+		if lastIndex < 0 || len(txsMiniBlocks[lastIndex].TxHashes) == maxTxsPerMiniBlock {
+			txsMiniBlocks = append(txsMiniBlocks, &block.MiniBlock{
+				SenderShardID:   bg.shardID,
+				ReceiverShardID: bg.shardID,
+				Type:            block.TxBlock,
+			})
+			lastIndex++
+		}
+		txsMiniBlocks[lastIndex].TxHashes = append(txsMiniBlocks[lastIndex].TxHashes, txHash)
+	}
+
+	intraShardMiniBlocks := make([]*block.MiniBlock, 0)
+	if len(scrsMiniBlock.TxHashes) > 0 {
+		intraShardMiniBlocks = append(intraShardMiniBlocks, scrsMiniBlock)
+	}
+
+	return txsMiniBlocks, intraShardMiniBlocks, nil
+}
+
+func (bg *blockGenerator) generateLogs() []*outport.LogData {
+	events := make([]*transaction.Event, 0, bg.numDepositsPerBlock+bg.numNoiseEventsPerBlock)
+	for i := uint32(0); i < bg.numDepositsPerBlock; i++ {
+		events = append(events, bg.generateDepositEvent())
+	}
+	for i := uint32(0); i < bg.numNoiseEventsPerBlock; i++ {
+		events = append(events, bg.generateNoiseEvent())
+	}
+
+	bg.randomness.Shuffle(len(events), func(i, j int) {
+		events[i], events[j] = events[j], events[i]
+	})
+
+	logs := make([]*outport.LogData, 0)
+	for len(events) > 0 {
+		numEvents := 1 + bg.randomness.Intn(maxEventsPerLog)
+		if numEvents > len(events) {
+			numEvents = len(events)
+		}
+
+		logs = append(logs, &outport.LogData{
+			TxHash: hex.EncodeToString(bg.randomBytes(hashLen)),
+			Log: &transaction.Log{
+				Address: events[0].Address,
+				Events:  events[:numEvents],
+			},
+		})
+		events = events[numEvents:]
+	}
+
+	return logs
+}
+
+func (bg *blockGenerator) generateDepositEvent() *transaction.Event {
+	deposit := bg.deposits[bg.randomness.Intn(len(bg.deposits))]
+	address := deposit.addresses[bg.randomness.Intn(len(deposit.addresses))]
+
+	return &transaction.Event{
+		Address:    address,
+		Identifier: deposit.identifier,
+		Topics: [][]byte{
+			bg.randomBytes(addressLen),
+			[]byte(fmt.Sprintf("TKN-%06x", bg.randomness.Intn(0xffffff))),
+			big.NewInt(bg.randomness.Int63()).Bytes(),
+		},
+		Data: bg.randomBytes(hashLen),
+	}
+}
+
+func (bg *blockGenerator) generateNoiseEvent() *transaction.Event {
+	return &transaction.Event{
+		Address:    bg.randomBytes(addressLen),
+		Identifier: []byte(noiseIdentifier),
+		Topics: [][]byte{
+			big.NewInt(bg.randomness.Int63()).Bytes(),
+			bg.randomBytes(addressLen),
+		},
+	}
+}
+
+func (bg *blockGenerator) randomBytes(length int) []byte {
+	buff := make([]byte, length)
+	_, _ = bg.randomness.Read(buff)
+
+	return buff
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (bg *blockGenerator) IsInterfaceNil() bool {
+	return bg == nil
+}
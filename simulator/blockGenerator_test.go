@@ -0,0 +1,168 @@
+package simulator
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/hashing/sha256"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/process/notifier"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	depositIdentifier = []byte("deposit")
+	depositAddress    = "depositAddress"
+)
+
+func createBlockGeneratorArgs() ArgsBlockGenerator {
+	return ArgsBlockGenerator{
+		Marshaller: &testscommon.MarshallerMock{},
+		Hasher:     sha256.NewSha256(),
+		Randomness: rand.New(rand.NewSource(1)),
+		SubscribedEvents: []notifier.SubscribedEvent{
+			{
+				Identifier: depositIdentifier,
+				Addresses: map[string]string{
+					depositAddress: "encodedAddr",
+				},
+			},
+		},
+		ShardID:                1,
+		NumDepositsPerBlock:    3,
+		NumNoiseEventsPerBlock: 4,
+	}
+}
+
+func TestNewBlockGenerator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		generator, err := NewBlockGenerator(createBlockGeneratorArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(generator))
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createBlockGeneratorArgs()
+		args.Marshaller = nil
+		generator, err := NewBlockGenerator(args)
+		require.Equal(t, core.ErrNilMarshalizer, err)
+		require.Nil(t, generator)
+	})
+
+	t.Run("nil hasher, should return error", func(t *testing.T) {
+		args := createBlockGeneratorArgs()
+		args.Hasher = nil
+		generator, err := NewBlockGenerator(args)
+		require.Equal(t, errNilHasher, err)
+		require.Nil(t, generator)
+	})
+
+	t.Run("nil randomness, should return error", func(t *testing.T) {
+		args := createBlockGeneratorArgs()
+		args.Randomness = nil
+		generator, err := NewBlockGenerator(args)
+		require.Equal(t, errNilRandomness, err)
+		require.Nil(t, generator)
+	})
+
+	t.Run("no subscribed events, should return error", func(t *testing.T) {
+		args := createBlockGeneratorArgs()
+		args.SubscribedEvents = nil
+		generator, err := NewBlockGenerator(args)
+		require.Equal(t, errNoSubscribedEvent, err)
+		require.Nil(t, generator)
+	})
+}
+
+func TestBlockGenerator_GenerateBlock(t *testing.T) {
+	t.Parallel()
+
+	args := createBlockGeneratorArgs()
+	generator, _ := NewBlockGenerator(args)
+
+	prevHash := []byte("prevHash")
+	outportBlock, err := generator.GenerateBlock(4, 5, prevHash)
+	require.Nil(t, err)
+	require.Equal(t, args.ShardID, outportBlock.ShardID)
+	require.Equal(t, string(core.ShardHeaderV2), outportBlock.BlockData.HeaderType)
+	require.Equal(t, args.Hasher.Compute(string(outportBlock.BlockData.HeaderBytes)), outportBlock.BlockData.HeaderHash)
+
+	headerV2 := &block.HeaderV2{}
+	err = args.Marshaller.Unmarshal(headerV2, outportBlock.BlockData.HeaderBytes)
+	require.Nil(t, err)
+	require.Equal(t, uint64(4), headerV2.GetNonce())
+	require.Equal(t, uint64(5), headerV2.GetRound())
+	require.Equal(t, prevHash, headerV2.GetPrevHash())
+	require.Equal(t, args.ShardID, headerV2.GetShardID())
+
+	numDeposits, numNoiseEvents := 0, 0
+	for _, logData := range outportBlock.TransactionPool.Logs {
+		require.NotEmpty(t, logData.TxHash)
+		for _, event := range logData.Log.Events {
+			switch string(event.Identifier) {
+			case string(depositIdentifier):
+				require.Equal(t, []byte(depositAddress), event.Address)
+				numDeposits++
+			case noiseIdentifier:
+				numNoiseEvents++
+			}
+		}
+	}
+	require.Equal(t, 3, numDeposits)
+	require.Equal(t, 4, numNoiseEvents)
+
+	pool := outportBlock.TransactionPool
+	miniBlocks := append(outportBlock.BlockData.Body.MiniBlocks, outportBlock.BlockData.IntraShardMiniBlocks...)
+	executionOrders := make(map[uint32]struct{})
+	numTxHashes := 0
+	for _, miniBlock := range miniBlocks {
+		for _, txHash := range miniBlock.TxHashes {
+			numTxHashes++
+			encodedTxHash := hex.EncodeToString(txHash)
+			txInfo, isTx := pool.Transactions[encodedTxHash]
+			scrInfo, isSCR := pool.SmartContractResults[encodedTxHash]
+			require.True(t, isTx != isSCR)
+			if isTx {
+				require.Equal(t, block.TxBlock, miniBlock.Type)
+				executionOrders[txInfo.ExecutionOrder] = struct{}{}
+			} else {
+				require.Equal(t, block.SmartContractResultBlock, miniBlock.Type)
+				executionOrders[scrInfo.ExecutionOrder] = struct{}{}
+			}
+		}
+	}
+	require.Equal(t, len(pool.Logs), numTxHashes)
+	require.Equal(t, len(pool.Logs), len(pool.Transactions)+len(pool.SmartContractResults))
+	require.Len(t, executionOrders, len(pool.Logs))
+
+	for _, logData := range pool.Logs {
+		receiver := pool.Transactions[logData.TxHash].GetTransaction().GetRcvAddr()
+		if receiver == nil {
+			receiver = pool.SmartContractResults[logData.TxHash].GetSmartContractResult().GetRcvAddr()
+		}
+		require.Equal(t, logData.Log.Address, receiver)
+	}
+}
+
+func TestBlockGenerator_GenerateBlockSameSeedIsReproducible(t *testing.T) {
+	t.Parallel()
+
+	generator1, _ := NewBlockGenerator(createBlockGeneratorArgs())
+	generator2, _ := NewBlockGenerator(createBlockGeneratorArgs())
+
+	block1, err := generator1.GenerateBlock(1, 1, nil)
+	require.Nil(t, err)
+	block2, err := generator2.GenerateBlock(1, 1, nil)
+	require.Nil(t, err)
+	require.Equal(t, block1, block2)
+
+	block3, err := generator1.GenerateBlock(1, 1, nil)
+	require.Nil(t, err)
+	require.NotEqual(t, block1.BlockData.HeaderHash, block3.BlockData.HeaderHash)
+}
@@ -0,0 +1,17 @@
+package simulator
+
+import "errors"
+
+var errNilOutportSender = errors.New("nil outport sender provided")
+
+var errNilBlockGenerator = errors.New("nil block generator provided")
+
+var errNilRandomness = errors.New("nil randomness source provided")
+
+var errNilHasher = errors.New("nil hasher provided")
+
+var errNoSubscribedEvent = errors.New("no subscribed event provided")
+
+var errInvalidProbability = errors.New("invalid probability provided, should be in [0, 1]")
+
+var errSimulatorClosed = errors.New("simulator closed")
@@ -0,0 +1,27 @@
+package simulator
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+)
+
+// OutportSender defines what an outport data sender should do
+type OutportSender interface {
+	Send(payload []byte, topic string) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// BlockGenerator should be able to generate synthetic outport blocks
+type BlockGenerator interface {
+	GenerateBlock(nonce uint64, round uint64, prevHash []byte) (*outport.OutportBlock, error)
+	IsInterfaceNil() bool
+}
+
+// OutportSimulator defines what an outport observer simulator should do
+type OutportSimulator interface {
+	Run(ctx context.Context) error
+	Close() error
+	IsInterfaceNil() bool
+}
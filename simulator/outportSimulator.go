@@ -0,0 +1,322 @@
+package simulator
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("outport-simulator")
+
+type outportMessage struct {
+	topic   string
+	payload []byte
+}
+
+type chainTip struct {
+	nonce uint64
+	round uint64
+	hash  []byte
+}
+
+// ArgsOutportSimulator is a struct placeholder for args needed to create an outport simulator
+type ArgsOutportSimulator struct {
+	Sender             OutportSender
+	Marshaller         marshal.Marshalizer
+	BlockGenerator     BlockGenerator
+	Randomness         *rand.Rand
+	ShardID            uint32
+	NumBlocks          uint64
+	FinalityDelay      uint32
+	ForkProbability    float64
+	ReorderProbability float64
+	RoundDuration      time.Duration
+	RetryDuration      time.Duration
+}
+
+type outportSimulator struct {
+	sender             OutportSender
+	marshaller         marshal.Marshalizer
+	blockGenerator     BlockGenerator
+	randomness         *rand.Rand
+	shardID            uint32
+	numBlocks          uint64
+	finalityDelay      uint32
+	forkProbability    float64
+	reorderProbability float64
+	roundDuration      time.Duration
+	retryDuration      time.Duration
+
+	tip             chainTip
+	pendingFinalize [][]byte
+	heldBack        *outportMessage
+	closeChan       chan struct{}
+	closeOnce       sync.Once
+}
+
+// NewOutportSimulator creates a simulator which behaves like an observer's websocket outport driver. It will send
+// generated blocks in the same topic order as a node would, optionally injecting forks and reordered messages
+func NewOutportSimulator(args ArgsOutportSimulator) (*outportSimulator, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outportSimulator{
+		sender:             args.Sender,
+		marshaller:         args.Marshaller,
+		blockGenerator:     args.BlockGenerator,
+		randomness:         args.Randomness,
+		shardID:            args.ShardID,
+		numBlocks:          args.NumBlocks,
+		finalityDelay:      args.FinalityDelay,
+		forkProbability:    args.ForkProbability,
+		reorderProbability: args.ReorderProbability,
+		roundDuration:      args.RoundDuration,
+		retryDuration:      args.RetryDuration,
+		pendingFinalize:    make([][]byte, 0),
+		closeChan:          make(chan struct{}),
+	}, nil
+}
+
+func checkArgs(args ArgsOutportSimulator) error {
+	if check.IfNil(args.Sender) {
+		return errNilOutportSender
+	}
+	if check.IfNil(args.Marshaller) {
+		return core.ErrNilMarshalizer
+	}
+	if check.IfNil(args.BlockGenerator) {
+		return errNilBlockGenerator
+	}
+	if args.Randomness == nil {
+		return errNilRandomness
+	}
+	if !isValidProbability(args.ForkProbability) || !isValidProbability(args.ReorderProbability) {
+		return errInvalidProbability
+	}
+
+	return nil
+}
+
+func isValidProbability(probability float64) bool {
+	return probability >= 0 && probability <= 1
+}
+
+// Run will generate and send blocks until the configured number of blocks is reached, the context is done or the
+// simulator is closed. A zero number of blocks means it will run indefinitely.
+func (sim *outportSimulator) Run(ctx context.Context) error {
+	for blockIdx := uint64(0); sim.numBlocks == 0 || blockIdx < sim.numBlocks; blockIdx++ {
+		messages, err := sim.createNextMessages()
+		if err != nil {
+			return err
+		}
+
+		err = sim.sendMessages(ctx, sim.reorder(messages))
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(sim.roundDuration):
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sim.closeChan:
+			return errSimulatorClosed
+		}
+	}
+
+	return sim.flush(ctx)
+}
+
+func (sim *outportSimulator) createNextMessages() ([]*outportMessage, error) {
+	messages := make([]*outportMessage, 0)
+
+	nonce, round := sim.tip.nonce+1, sim.tip.round+1
+	if sim.randomness.Float64() < sim.forkProbability {
+		forkMessages, err := sim.createForkMessages(nonce, round)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, forkMessages...)
+		round++
+	}
+
+	outportBlock, err := sim.blockGenerator.GenerateBlock(nonce, round, sim.tip.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	saveBlockMessage, err := sim.createMessage(outport.TopicSaveBlock, outportBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	messages = append(messages, saveBlockMessage)
+	sim.tip = chainTip{
+		nonce: nonce,
+		round: round,
+		hash:  outportBlock.BlockData.HeaderHash,
+	}
+	sim.pendingFinalize = append(sim.pendingFinalize, outportBlock.BlockData.HeaderHash)
+
+	log.Info("generated block",
+		"nonce", nonce,
+		"round", round,
+		"hash", hex.EncodeToString(outportBlock.BlockData.HeaderHash),
+		"num logs", len(outportBlock.TransactionPool.Logs))
+
+	for uint32(len(sim.pendingFinalize)) > sim.finalityDelay {
+		finalizedMessage, errFinalize := sim.createFinalizedMessage(sim.pendingFinalize[0])
+		if errFinalize != nil {
+			return nil, errFinalize
+		}
+
+		messages = append(messages, finalizedMessage)
+		sim.pendingFinalize = sim.pendingFinalize[1:]
+	}
+
+	return messages, nil
+}
+
+// createForkMessages will create a block which is saved and immediately reverted, as a node does when
+// it rolls back a block from a losing fork
+func (sim *outportSimulator) createForkMessages(nonce uint64, round uint64) ([]*outportMessage, error) {
+	forkBlock, err := sim.blockGenerator.GenerateBlock(nonce, round, sim.tip.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	saveBlockMessage, err := sim.createMessage(outport.TopicSaveBlock, forkBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	revertMessage, err := sim.createMessage(outport.TopicRevertIndexedBlock, forkBlock.BlockData)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("injected fork",
+		"nonce", nonce,
+		"round", round,
+		"reverted hash", hex.EncodeToString(forkBlock.BlockData.HeaderHash))
+
+	return []*outportMessage{saveBlockMessage, revertMessage}, nil
+}
+
+func (sim *outportSimulator) createFinalizedMessage(headerHash []byte) (*outportMessage, error) {
+	return sim.createMessage(outport.TopicFinalizedBlock, &outport.FinalizedBlock{
+		ShardID:    sim.shardID,
+		HeaderHash: headerHash,
+	})
+}
+
+func (sim *outportSimulator) createMessage(topic string, obj interface{}) (*outportMessage, error) {
+	payload, err := sim.marshaller.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outportMessage{
+		topic:   topic,
+		payload: payload,
+	}, nil
+}
+
+// reorder will hold back messages with the configured probability, such that they are sent after the next message.
+// A held back message can also be delayed across rounds.
+func (sim *outportSimulator) reorder(messages []*outportMessage) []*outportMessage {
+	reordered := make([]*outportMessage, 0, len(messages)+1)
+	for _, message := range messages {
+		if sim.heldBack == nil && sim.randomness.Float64() < sim.reorderProbability {
+			log.Info("reordering message", "topic", message.topic)
+			sim.heldBack = message
+			continue
+		}
+
+		reordered = append(reordered, message)
+		if sim.heldBack != nil {
+			reordered = append(reordered, sim.heldBack)
+			sim.heldBack = nil
+		}
+	}
+
+	return reordered
+}
+
+func (sim *outportSimulator) flush(ctx context.Context) error {
+	messages := make([]*outportMessage, 0)
+	if sim.heldBack != nil {
+		messages = append(messages, sim.heldBack)
+		sim.heldBack = nil
+	}
+
+	for _, headerHash := range sim.pendingFinalize {
+		finalizedMessage, err := sim.createFinalizedMessage(headerHash)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, finalizedMessage)
+	}
+	sim.pendingFinalize = make([][]byte, 0)
+
+	return sim.sendMessages(ctx, messages)
+}
+
+func (sim *outportSimulator) sendMessages(ctx context.Context, messages []*outportMessage) error {
+	for _, message := range messages {
+		err := sim.sendWithRetry(ctx, message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sim *outportSimulator) sendWithRetry(ctx context.Context, message *outportMessage) error {
+	for {
+		err := sim.sender.Send(message.payload, message.topic)
+		if err == nil {
+			log.Debug("sent outport message", "topic", message.topic)
+			return nil
+		}
+
+		log.Warn("could not send outport message, retrying", "topic", message.topic, "error", err)
+
+		select {
+		case <-time.After(sim.retryDuration):
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sim.closeChan:
+			return errSimulatorClosed
+		}
+	}
+}
+
+// Close will stop sending messages and close the underlying sender. Subsequent calls do nothing
+func (sim *outportSimulator) Close() error {
+	var err error
+	sim.closeOnce.Do(func() {
+		close(sim.closeChan)
+		err = sim.sender.Close()
+	})
+
+	return err
+}
+
+// IsInterfaceNil checks if the underlying pointer is nil
+func (sim *outportSimulator) IsInterfaceNil() bool {
+	return sim == nil
+}
@@ -0,0 +1,283 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-sovereign-notifier-go/testscommon"
+	"github.com/stretchr/testify/require"
+)
+
+type sentMessage struct {
+	topic string
+	hash  []byte
+}
+
+func createOutportSimulatorArgs() ArgsOutportSimulator {
+	generator, _ := NewBlockGenerator(createBlockGeneratorArgs())
+
+	return ArgsOutportSimulator{
+		Sender:         &testscommon.OutportSenderStub{},
+		Marshaller:     &testscommon.MarshallerMock{},
+		BlockGenerator: generator,
+		Randomness:     rand.New(rand.NewSource(1)),
+		ShardID:        1,
+		NumBlocks:      3,
+		FinalityDelay:  1,
+		RetryDuration:  time.Millisecond,
+	}
+}
+
+func createRecordingSender(t *testing.T, marshaller *testscommon.MarshallerMock, sent *[]*sentMessage) *testscommon.OutportSenderStub {
+	return &testscommon.OutportSenderStub{
+		SendCalled: func(payload []byte, topic string) error {
+			var hash []byte
+			switch topic {
+			case outport.TopicSaveBlock:
+				outportBlock := &outport.OutportBlock{}
+				require.Nil(t, marshaller.Unmarshal(outportBlock, payload))
+				hash = outportBlock.BlockData.HeaderHash
+			case outport.TopicRevertIndexedBlock:
+				blockData := &outport.BlockData{}
+				require.Nil(t, marshaller.Unmarshal(blockData, payload))
+				hash = blockData.HeaderHash
+			case outport.TopicFinalizedBlock:
+				finalizedBlock := &outport.FinalizedBlock{}
+				require.Nil(t, marshaller.Unmarshal(finalizedBlock, payload))
+				hash = finalizedBlock.HeaderHash
+			}
+
+			*sent = append(*sent, &sentMessage{topic: topic, hash: hash})
+			return nil
+		},
+	}
+}
+
+func TestNewOutportSimulator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		sim, err := NewOutportSimulator(createOutportSimulatorArgs())
+		require.Nil(t, err)
+		require.False(t, check.IfNil(sim))
+	})
+
+	t.Run("nil sender, should return error", func(t *testing.T) {
+		args := createOutportSimulatorArgs()
+		args.Sender = nil
+		sim, err := NewOutportSimulator(args)
+		require.Equal(t, errNilOutportSender, err)
+		require.Nil(t, sim)
+	})
+
+	t.Run("nil marshaller, should return error", func(t *testing.T) {
+		args := createOutportSimulatorArgs()
+		args.Marshaller = nil
+		sim, err := NewOutportSimulator(args)
+		require.Equal(t, core.ErrNilMarshalizer, err)
+		require.Nil(t, sim)
+	})
+
+	t.Run("nil block generator, should return error", func(t *testing.T) {
+		args := createOutportSimulatorArgs()
+		args.BlockGenerator = nil
+		sim, err := NewOutportSimulator(args)
+		require.Equal(t, errNilBlockGenerator, err)
+		require.Nil(t, sim)
+	})
+
+	t.Run("nil randomness, should return error", func(t *testing.T) {
+		args := createOutportSimulatorArgs()
+		args.Randomness = nil
+		sim, err := NewOutportSimulator(args)
+		require.Equal(t, errNilRandomness, err)
+		require.Nil(t, sim)
+	})
+
+	t.Run("invalid probabilities, should return error", func(t *testing.T) {
+		args := createOutportSimulatorArgs()
+		args.ForkProbability = 1.1
+		sim, err := NewOutportSimulator(args)
+		require.Equal(t, errInvalidProbability, err)
+		require.Nil(t, sim)
+
+		args = createOutportSimulatorArgs()
+		args.ReorderProbability = -0.1
+		sim, err = NewOutportSimulator(args)
+		require.Equal(t, errInvalidProbability, err)
+		require.Nil(t, sim)
+	})
+}
+
+func TestOutportSimulator_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocks are finalized after the finality delay", func(t *testing.T) {
+		t.Parallel()
+
+		sent := make([]*sentMessage, 0)
+		args := createOutportSimulatorArgs()
+		args.Sender = createRecordingSender(t, &testscommon.MarshallerMock{}, &sent)
+		sim, _ := NewOutportSimulator(args)
+
+		err := sim.Run(context.Background())
+		require.Nil(t, err)
+
+		require.Len(t, sent, 6)
+		expectedTopics := []string{
+			outport.TopicSaveBlock,
+			outport.TopicSaveBlock,
+			outport.TopicFinalizedBlock,
+			outport.TopicSaveBlock,
+			outport.TopicFinalizedBlock,
+			outport.TopicFinalizedBlock,
+		}
+		for idx, message := range sent {
+			require.Equal(t, expectedTopics[idx], message.topic)
+		}
+		require.Equal(t, sent[0].hash, sent[2].hash)
+		require.Equal(t, sent[1].hash, sent[4].hash)
+		require.Equal(t, sent[3].hash, sent[5].hash)
+	})
+
+	t.Run("forks are saved, reverted and replaced", func(t *testing.T) {
+		t.Parallel()
+
+		sent := make([]*sentMessage, 0)
+		savedBlocks := make([]*outport.OutportBlock, 0)
+		marshaller := &testscommon.MarshallerMock{}
+		recordingSender := createRecordingSender(t, marshaller, &sent)
+
+		args := createOutportSimulatorArgs()
+		args.NumBlocks = 2
+		args.FinalityDelay = 0
+		args.ForkProbability = 1
+		args.Sender = &testscommon.OutportSenderStub{
+			SendCalled: func(payload []byte, topic string) error {
+				if topic == outport.TopicSaveBlock {
+					outportBlock := &outport.OutportBlock{}
+					_ = marshaller.Unmarshal(outportBlock, payload)
+					savedBlocks = append(savedBlocks, outportBlock)
+				}
+
+				return recordingSender.Send(payload, topic)
+			},
+		}
+		sim, _ := NewOutportSimulator(args)
+
+		err := sim.Run(context.Background())
+		require.Nil(t, err)
+
+		require.Len(t, sent, 8)
+		for i := 0; i < 2; i++ {
+			forkSave, revert, save, finalize := sent[4*i], sent[4*i+1], sent[4*i+2], sent[4*i+3]
+			require.Equal(t, outport.TopicSaveBlock, forkSave.topic)
+			require.Equal(t, outport.TopicRevertIndexedBlock, revert.topic)
+			require.Equal(t, forkSave.hash, revert.hash)
+			require.Equal(t, outport.TopicSaveBlock, save.topic)
+			require.NotEqual(t, forkSave.hash, save.hash)
+			require.Equal(t, outport.TopicFinalizedBlock, finalize.topic)
+			require.Equal(t, save.hash, finalize.hash)
+		}
+
+		secondHeader := &block.HeaderV2{}
+		_ = marshaller.Unmarshal(secondHeader, savedBlocks[3].BlockData.HeaderBytes)
+		require.Equal(t, uint64(2), secondHeader.GetNonce())
+		require.Equal(t, sent[2].hash, secondHeader.GetPrevHash())
+	})
+
+	t.Run("reordered messages are sent after their successor", func(t *testing.T) {
+		t.Parallel()
+
+		sent := make([]*sentMessage, 0)
+		args := createOutportSimulatorArgs()
+		args.NumBlocks = 2
+		args.FinalityDelay = 0
+		args.ReorderProbability = 1
+		args.Sender = createRecordingSender(t, &testscommon.MarshallerMock{}, &sent)
+		sim, _ := NewOutportSimulator(args)
+
+		err := sim.Run(context.Background())
+		require.Nil(t, err)
+
+		require.Len(t, sent, 4)
+		for i := 0; i < 2; i++ {
+			require.Equal(t, outport.TopicFinalizedBlock, sent[2*i].topic)
+			require.Equal(t, outport.TopicSaveBlock, sent[2*i+1].topic)
+			require.Equal(t, sent[2*i].hash, sent[2*i+1].hash)
+		}
+	})
+
+	t.Run("send errors are retried", func(t *testing.T) {
+		t.Parallel()
+
+		numSendCalls := 0
+		args := createOutportSimulatorArgs()
+		args.NumBlocks = 1
+		args.FinalityDelay = 0
+		args.Sender = &testscommon.OutportSenderStub{
+			SendCalled: func(payload []byte, topic string) error {
+				numSendCalls++
+				if numSendCalls < 3 {
+					return errors.New("no clients connected")
+				}
+
+				return nil
+			},
+		}
+		sim, _ := NewOutportSimulator(args)
+
+		err := sim.Run(context.Background())
+		require.Nil(t, err)
+		require.Equal(t, 4, numSendCalls)
+	})
+
+	t.Run("context done, should stop", func(t *testing.T) {
+		t.Parallel()
+
+		args := createOutportSimulatorArgs()
+		args.NumBlocks = 0
+		args.RoundDuration = time.Hour
+		sim, _ := NewOutportSimulator(args)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := sim.Run(ctx)
+		require.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("closed simulator, should stop", func(t *testing.T) {
+		t.Parallel()
+
+		numCloseCalls := 0
+		args := createOutportSimulatorArgs()
+		args.NumBlocks = 0
+		args.RoundDuration = time.Hour
+		args.Sender = &testscommon.OutportSenderStub{
+			CloseCalled: func() error {
+				numCloseCalls++
+				return nil
+			},
+		}
+		sim, _ := NewOutportSimulator(args)
+
+		err := sim.Close()
+		require.Nil(t, err)
+		require.NotPanics(t, func() {
+			err = sim.Close()
+		})
+		require.Nil(t, err)
+		require.Equal(t, 1, numCloseCalls)
+
+		err = sim.Run(context.Background())
+		require.Equal(t, errSimulatorClosed, err)
+	})
+}
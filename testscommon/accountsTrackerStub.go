@@ -0,0 +1,41 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+
+// AccountsTrackerStub -
+type AccountsTrackerStub struct {
+	UpdateAccountsCalled func(alteredAccounts map[string]*alteredAccount.AlteredAccount)
+	GetAccountsCalled    func() map[string]*alteredAccount.AlteredAccount
+
+	GetUpdatedAccountsCalled func(alteredAccounts map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount
+}
+
+// UpdateAccounts -
+func (stub *AccountsTrackerStub) UpdateAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount) {
+	if stub.UpdateAccountsCalled != nil {
+		stub.UpdateAccountsCalled(alteredAccounts)
+	}
+}
+
+// GetAccounts -
+func (stub *AccountsTrackerStub) GetAccounts() map[string]*alteredAccount.AlteredAccount {
+	if stub.GetAccountsCalled != nil {
+		return stub.GetAccountsCalled()
+	}
+
+	return nil
+}
+
+// GetUpdatedAccounts -
+func (stub *AccountsTrackerStub) GetUpdatedAccounts(alteredAccounts map[string]*alteredAccount.AlteredAccount) map[string]*alteredAccount.AlteredAccount {
+	if stub.GetUpdatedAccountsCalled != nil {
+		return stub.GetUpdatedAccountsCalled(alteredAccounts)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *AccountsTrackerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,23 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+
+// HeaderAccountsSubscriberStub -
+type HeaderAccountsSubscriberStub struct {
+	HeaderSubscriberStub
+	AddAccountsCalled func(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error
+}
+
+// AddAccounts -
+func (stub *HeaderAccountsSubscriberStub) AddAccounts(headerHash []byte, accounts map[string]*alteredAccount.AlteredAccount) error {
+	if stub.AddAccountsCalled != nil {
+		return stub.AddAccountsCalled(headerHash, accounts)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *HeaderAccountsSubscriberStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,23 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-sovereign-notifier-go/process"
+
+// HeaderHeartbeatSubscriberStub -
+type HeaderHeartbeatSubscriberStub struct {
+	HeaderSubscriberStub
+	AddHeartbeatCalled func(heartbeat *process.Heartbeat) error
+}
+
+// AddHeartbeat -
+func (stub *HeaderHeartbeatSubscriberStub) AddHeartbeat(heartbeat *process.Heartbeat) error {
+	if stub.AddHeartbeatCalled != nil {
+		return stub.AddHeartbeatCalled(heartbeat)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *HeaderHeartbeatSubscriberStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,23 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/block"
+
+// HeaderMiniBlocksSubscriberStub -
+type HeaderMiniBlocksSubscriberStub struct {
+	HeaderSubscriberStub
+	AddIncomingMiniBlocksCalled func(headerHash []byte, miniBlocks []*block.MiniBlock) error
+}
+
+// AddIncomingMiniBlocks -
+func (stub *HeaderMiniBlocksSubscriberStub) AddIncomingMiniBlocks(headerHash []byte, miniBlocks []*block.MiniBlock) error {
+	if stub.AddIncomingMiniBlocksCalled != nil {
+		return stub.AddIncomingMiniBlocksCalled(headerHash, miniBlocks)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *HeaderMiniBlocksSubscriberStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,22 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data"
+
+// HeaderVerifierStub -
+type HeaderVerifierStub struct {
+	VerifyHeaderCalled func(header data.HeaderHandler, signersIndexes []uint64) error
+}
+
+// VerifyHeader -
+func (stub *HeaderVerifierStub) VerifyHeader(header data.HeaderHandler, signersIndexes []uint64) error {
+	if stub.VerifyHeaderCalled != nil {
+		return stub.VerifyHeaderCalled(header, signersIndexes)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *HeaderVerifierStub) IsInterfaceNil() bool {
+	return stub == nil
+}
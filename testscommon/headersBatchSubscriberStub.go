@@ -0,0 +1,23 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/sovereign"
+
+// HeadersBatchSubscriberStub -
+type HeadersBatchSubscriberStub struct {
+	HeaderAccountsSubscriberStub
+	AddHeadersCalled func(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error
+}
+
+// AddHeaders -
+func (stub *HeadersBatchSubscriberStub) AddHeaders(headersHashes [][]byte, headers []sovereign.IncomingHeaderHandler) error {
+	if stub.AddHeadersCalled != nil {
+		return stub.AddHeadersCalled(headersHashes, headers)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *HeadersBatchSubscriberStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -4,8 +4,14 @@ import "github.com/multiversx/mx-chain-core-go/data/outport"
 
 // IndexerStub -
 type IndexerStub struct {
-	SaveBlockCalled      func(outportBlock *outport.OutportBlock) error
-	FinalizedBlockCalled func(finalizedBlock *outport.FinalizedBlock) error
+	SaveBlockCalled             func(outportBlock *outport.OutportBlock) error
+	FinalizedBlockCalled        func(finalizedBlock *outport.FinalizedBlock) error
+	RevertIndexedBlockCalled    func(blockData *outport.BlockData) error
+	SaveAccountsCalled          func(accounts *outport.Accounts) error
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys *outport.ValidatorsPubKeys) error
+	SaveValidatorsRatingCalled  func(validatorsRating *outport.ValidatorsRating) error
+	SaveRoundsInfoCalled        func(roundsInfo *outport.RoundsInfo) error
+	CloseCalled                 func() error
 }
 
 // SaveBlock -
@@ -26,6 +32,60 @@ func (is *IndexerStub) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) er
 	return nil
 }
 
+// SaveAccounts -
+func (is *IndexerStub) SaveAccounts(accounts *outport.Accounts) error {
+	if is.SaveAccountsCalled != nil {
+		return is.SaveAccountsCalled(accounts)
+	}
+
+	return nil
+}
+
+// SaveValidatorsPubKeys -
+func (is *IndexerStub) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	if is.SaveValidatorsPubKeysCalled != nil {
+		return is.SaveValidatorsPubKeysCalled(validatorsPubKeys)
+	}
+
+	return nil
+}
+
+// SaveValidatorsRating -
+func (is *IndexerStub) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) error {
+	if is.SaveValidatorsRatingCalled != nil {
+		return is.SaveValidatorsRatingCalled(validatorsRating)
+	}
+
+	return nil
+}
+
+// RevertIndexedBlock -
+func (is *IndexerStub) RevertIndexedBlock(blockData *outport.BlockData) error {
+	if is.RevertIndexedBlockCalled != nil {
+		return is.RevertIndexedBlockCalled(blockData)
+	}
+
+	return nil
+}
+
+// SaveRoundsInfo -
+func (is *IndexerStub) SaveRoundsInfo(roundsInfo *outport.RoundsInfo) error {
+	if is.SaveRoundsInfoCalled != nil {
+		return is.SaveRoundsInfoCalled(roundsInfo)
+	}
+
+	return nil
+}
+
+// Close -
+func (is *IndexerStub) Close() error {
+	if is.CloseCalled != nil {
+		return is.CloseCalled()
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (is *IndexerStub) IsInterfaceNil() bool {
 	return is == nil
@@ -0,0 +1,38 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+// LivenessTrackerStub -
+type LivenessTrackerStub struct {
+	SaveRoundsInfoCalled     func(roundsInfo *outport.RoundsInfo)
+	SaveFinalizedBlockCalled func(headerHash []byte)
+	CloseCalled              func() error
+}
+
+// SaveRoundsInfo -
+func (stub *LivenessTrackerStub) SaveRoundsInfo(roundsInfo *outport.RoundsInfo) {
+	if stub.SaveRoundsInfoCalled != nil {
+		stub.SaveRoundsInfoCalled(roundsInfo)
+	}
+}
+
+// SaveFinalizedBlock -
+func (stub *LivenessTrackerStub) SaveFinalizedBlock(headerHash []byte) {
+	if stub.SaveFinalizedBlockCalled != nil {
+		stub.SaveFinalizedBlockCalled(headerHash)
+	}
+}
+
+// Close -
+func (stub *LivenessTrackerStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *LivenessTrackerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
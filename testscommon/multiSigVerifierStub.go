@@ -0,0 +1,20 @@
+package testscommon
+
+// MultiSigVerifierStub -
+type MultiSigVerifierStub struct {
+	VerifyAggregatedSigCalled func(pubKeysSigners [][]byte, message []byte, aggSig []byte) error
+}
+
+// VerifyAggregatedSig -
+func (stub *MultiSigVerifierStub) VerifyAggregatedSig(pubKeysSigners [][]byte, message []byte, aggSig []byte) error {
+	if stub.VerifyAggregatedSigCalled != nil {
+		return stub.VerifyAggregatedSigCalled(pubKeysSigners, message, aggSig)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *MultiSigVerifierStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -6,6 +6,7 @@ import "github.com/multiversx/mx-chain-core-go/data/outport"
 type OutportBlockCacheStub struct {
 	AddCalled     func(outportBlock *outport.OutportBlock) error
 	ExtractCalled func(headerHash []byte) (*outport.OutportBlock, error)
+	CloseCalled   func() error
 }
 
 // Add -
@@ -25,6 +26,15 @@ func (obc *OutportBlockCacheStub) Extract(headerHash []byte) (*outport.OutportBl
 	return nil, nil
 }
 
+// Close -
+func (obc *OutportBlockCacheStub) Close() error {
+	if obc.CloseCalled != nil {
+		return obc.CloseCalled()
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (obc *OutportBlockCacheStub) IsInterfaceNil() bool {
 	return obc == nil
@@ -0,0 +1,22 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+// OutportBlockFilterStub -
+type OutportBlockFilterStub struct {
+	FilterOutportBlockCalled func(outportBlock *outport.OutportBlock) *outport.OutportBlock
+}
+
+// FilterOutportBlock -
+func (stub *OutportBlockFilterStub) FilterOutportBlock(outportBlock *outport.OutportBlock) *outport.OutportBlock {
+	if stub.FilterOutportBlockCalled != nil {
+		return stub.FilterOutportBlockCalled(outportBlock)
+	}
+
+	return outportBlock
+}
+
+// IsInterfaceNil -
+func (stub *OutportBlockFilterStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,30 @@
+package testscommon
+
+// OutportSenderStub -
+type OutportSenderStub struct {
+	SendCalled  func(payload []byte, topic string) error
+	CloseCalled func() error
+}
+
+// Send -
+func (stub *OutportSenderStub) Send(payload []byte, topic string) error {
+	if stub.SendCalled != nil {
+		return stub.SendCalled(payload, topic)
+	}
+
+	return nil
+}
+
+// Close -
+func (stub *OutportSenderStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *OutportSenderStub) IsInterfaceNil() bool {
+	return stub == nil
+}
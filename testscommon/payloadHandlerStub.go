@@ -0,0 +1,30 @@
+package testscommon
+
+// PayloadHandlerStub -
+type PayloadHandlerStub struct {
+	ProcessPayloadCalled func(payload []byte, topic string, version uint32) error
+	CloseCalled          func() error
+}
+
+// ProcessPayload -
+func (stub *PayloadHandlerStub) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if stub.ProcessPayloadCalled != nil {
+		return stub.ProcessPayloadCalled(payload, topic, version)
+	}
+
+	return nil
+}
+
+// Close -
+func (stub *PayloadHandlerStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *PayloadHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,23 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+// QueuingNotifierStub -
+type QueuingNotifierStub struct {
+	SovereignNotifierStub
+	NotifyWithDeliveryHandlerCalled func(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error
+}
+
+// NotifyWithDeliveryHandler -
+func (qn *QueuingNotifierStub) NotifyWithDeliveryHandler(finalizedBlock *outport.OutportBlock, deliveryHandler func()) error {
+	if qn.NotifyWithDeliveryHandlerCalled != nil {
+		return qn.NotifyWithDeliveryHandlerCalled(finalizedBlock, deliveryHandler)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (qn *QueuingNotifierStub) IsInterfaceNil() bool {
+	return qn == nil
+}
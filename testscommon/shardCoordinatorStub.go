@@ -0,0 +1,20 @@
+package testscommon
+
+// ShardCoordinatorStub -
+type ShardCoordinatorStub struct {
+	ComputeIdCalled func(address []byte) uint32
+}
+
+// ComputeId -
+func (stub *ShardCoordinatorStub) ComputeId(address []byte) uint32 {
+	if stub.ComputeIdCalled != nil {
+		return stub.ComputeIdCalled(address)
+	}
+
+	return 0
+}
+
+// IsInterfaceNil -
+func (stub *ShardCoordinatorStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -7,8 +7,11 @@ import (
 
 // SovereignNotifierStub -
 type SovereignNotifierStub struct {
-	NotifyCalled          func(finalizedBlock *outport.OutportBlock) error
-	RegisterHandlerCalled func(handler process.IncomingHeaderSubscriber) error
+	NotifyCalled                    func(finalizedBlock *outport.OutportBlock) error
+	ComputeIncomingHeaderHashCalled func(outportBlock *outport.OutportBlock) ([]byte, error)
+	RegisterHandlerCalled           func(handler process.IncomingHeaderSubscriber) error
+	RegisterHandlerWithPolicyCalled func(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error
+	NotifyHeartbeatCalled           func(heartbeat *process.Heartbeat) error
 }
 
 // Notify -
@@ -20,6 +23,15 @@ func (sn *SovereignNotifierStub) Notify(finalizedBlock *outport.OutportBlock) er
 	return nil
 }
 
+// ComputeIncomingHeaderHash -
+func (sn *SovereignNotifierStub) ComputeIncomingHeaderHash(outportBlock *outport.OutportBlock) ([]byte, error) {
+	if sn.ComputeIncomingHeaderHashCalled != nil {
+		return sn.ComputeIncomingHeaderHashCalled(outportBlock)
+	}
+
+	return nil, nil
+}
+
 // RegisterHandler -
 func (sn *SovereignNotifierStub) RegisterHandler(handler process.IncomingHeaderSubscriber) error {
 	if sn.RegisterHandlerCalled != nil {
@@ -29,6 +41,24 @@ func (sn *SovereignNotifierStub) RegisterHandler(handler process.IncomingHeaderS
 	return nil
 }
 
+// RegisterHandlerWithPolicy -
+func (sn *SovereignNotifierStub) RegisterHandlerWithPolicy(handler process.IncomingHeaderSubscriber, policy process.NotificationPolicy) error {
+	if sn.RegisterHandlerWithPolicyCalled != nil {
+		return sn.RegisterHandlerWithPolicyCalled(handler, policy)
+	}
+
+	return nil
+}
+
+// NotifyHeartbeat -
+func (sn *SovereignNotifierStub) NotifyHeartbeat(heartbeat *process.Heartbeat) error {
+	if sn.NotifyHeartbeatCalled != nil {
+		return sn.NotifyHeartbeatCalled(heartbeat)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (sn *SovereignNotifierStub) IsInterfaceNil() bool {
 	return sn == nil
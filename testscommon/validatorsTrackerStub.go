@@ -0,0 +1,48 @@
+package testscommon
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+// ValidatorsTrackerStub -
+type ValidatorsTrackerStub struct {
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys *outport.ValidatorsPubKeys)
+	SaveValidatorsRatingCalled  func(validatorsRating *outport.ValidatorsRating)
+	GetValidatorsPubKeysCalled  func(shardID uint32, epoch uint32) ([][]byte, error)
+	GetValidatorsRatingsCalled  func(shardID uint32, epoch uint32) (map[string]float32, error)
+}
+
+// SaveValidatorsPubKeys -
+func (stub *ValidatorsTrackerStub) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) {
+	if stub.SaveValidatorsPubKeysCalled != nil {
+		stub.SaveValidatorsPubKeysCalled(validatorsPubKeys)
+	}
+}
+
+// SaveValidatorsRating -
+func (stub *ValidatorsTrackerStub) SaveValidatorsRating(validatorsRating *outport.ValidatorsRating) {
+	if stub.SaveValidatorsRatingCalled != nil {
+		stub.SaveValidatorsRatingCalled(validatorsRating)
+	}
+}
+
+// GetValidatorsPubKeys -
+func (stub *ValidatorsTrackerStub) GetValidatorsPubKeys(shardID uint32, epoch uint32) ([][]byte, error) {
+	if stub.GetValidatorsPubKeysCalled != nil {
+		return stub.GetValidatorsPubKeysCalled(shardID, epoch)
+	}
+
+	return nil, nil
+}
+
+// GetValidatorsRatings -
+func (stub *ValidatorsTrackerStub) GetValidatorsRatings(shardID uint32, epoch uint32) (map[string]float32, error) {
+	if stub.GetValidatorsRatingsCalled != nil {
+		return stub.GetValidatorsRatingsCalled(shardID, epoch)
+	}
+
+	return nil, nil
+}
+
+// IsInterfaceNil -
+func (stub *ValidatorsTrackerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,15 @@
+package testscommon
+
+// WSClientStub -
+type WSClientStub struct {
+	CloseCalled func() error
+}
+
+// Close -
+func (stub *WSClientStub) Close() error {
+	if stub.CloseCalled != nil {
+		return stub.CloseCalled()
+	}
+
+	return nil
+}